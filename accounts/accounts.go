@@ -19,6 +19,7 @@ import (
 
 	"github.com/ixios-io/ixiosSpark"
 	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/event"
 	"golang.org/x/crypto/sha3"
@@ -35,6 +36,7 @@ const (
 	MimetypeDataWithValidator = "data/validator"
 	MimetypeTypedData         = "data/typed"
 	MimetypeClique            = "application/x-clique-header"
+	MimetypeCliqueMKS         = "application/x-clique-mks-header"
 	MimetypeTextPlain         = "text/plain"
 )
 
@@ -149,6 +151,23 @@ type Wallet interface {
 	SignTxWithPassphrase(account Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
 }
 
+// SchemeSignature is a signature tagged with the keystore name of the scheme it
+// was made with, such as "ecdsa26" or "dilith5".
+type SchemeSignature struct {
+	Scheme    string        `json:"scheme"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// HybridSigner is an optional interface of wallets able to sign the same data
+// with several accounts, of possibly different signature schemes, in a single
+// request. It allows external signers to produce every half of a hybrid seal
+// with one round trip.
+type HybridSigner interface {
+	// SignHybridData requests the wallet to sign the hash of the given data with
+	// each of the accounts, returning the signatures in the order of the accounts.
+	SignHybridData(accounts []Account, mimeType string, data []byte) ([]SchemeSignature, error)
+}
+
 // Backend is a "wallet provider" that may contain a batch of accounts they can
 // sign transactions with and upon request, do so.
 type Backend interface {
@@ -24,6 +24,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/event"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/rpc"
@@ -167,6 +168,33 @@ func (api *ExternalSigner) SignData(account accounts.Account, mimeType string, d
 	return res, nil
 }
 
+// SignHybridData signs keccak256(data) with each of the accounts in a single
+// request, as needed for the hybrid seals of fastClique. The signatures are
+// returned in the order of the accounts, tagged with their scheme.
+func (api *ExternalSigner) SignHybridData(signers []accounts.Account, mimeType string, data []byte) ([]accounts.SchemeSignature, error) {
+	var res []accounts.SchemeSignature
+	var signAddresses = make([]common.MixedcaseAddress, len(signers))
+	for i, account := range signers {
+		signAddresses[i] = common.NewMixedcaseAddress(account.Address)
+	}
+	if err := api.client.Call(&res, "account_signHybridData",
+		mimeType,
+		signAddresses,
+		hexutil.Encode(data)); err != nil {
+		return nil, err
+	}
+	if len(res) != len(signers) {
+		return nil, fmt.Errorf("external signer returned %d signatures for %d accounts", len(res), len(signers))
+	}
+	for _, sig := range res {
+		// If V is on 27/28-form, convert to 0/1 for the secp256k1 halves of Clique seals
+		if len(sig.Signature) == crypto.SignatureLength && (sig.Signature[64] == 27 || sig.Signature[64] == 28) {
+			sig.Signature[64] -= 27
+		}
+	}
+	return res, nil
+}
+
 func (api *ExternalSigner) SignText(account accounts.Account, text []byte) ([]byte, error) {
 	var signature hexutil.Bytes
 	var signAddress = common.NewMixedcaseAddress(account.Address)
@@ -37,6 +37,20 @@ var DefaultBaseDerivationPath = DerivationPath{0x80000000 + 44, 0x80000000 + 60,
 // second at m/44'/60'/0'/1, etc.
 var LegacyLedgerBaseDerivationPath = DerivationPath{0x80000000 + 44, 0x80000000 + 60, 0x80000000 + 0, 0}
 
+// IxiosCoinType is the BIP-44 coin type of Ixios accounts.
+const IxiosCoinType = 1791
+
+// IxiosBaseDerivationPath is the base path from which Ixios ECDSA accounts are
+// incremented. As such, the first account will be at m/44'/1791'/0'/0/0, the
+// second at m/44'/1791'/0'/0/1, etc.
+var IxiosBaseDerivationPath = DerivationPath{0x80000000 + 44, 0x80000000 + IxiosCoinType, 0x80000000 + 0, 0, 0}
+
+// IxiosQuantumBaseDerivationPath is the base path from which Ixios post-quantum
+// accounts are incremented. Post-quantum keys are only derived along hardened
+// paths, the first account will be at m/44'/1791'/0'/0'/0', the second at
+// m/44'/1791'/0'/0'/1', etc.
+var IxiosQuantumBaseDerivationPath = DerivationPath{0x80000000 + 44, 0x80000000 + IxiosCoinType, 0x80000000 + 0, 0x80000000 + 0, 0x80000000 + 0}
+
 // DerivationPath represents the computer friendly version of a hierarchical
 // deterministic wallet account derivation path.
 //
@@ -54,6 +68,9 @@ var LegacyLedgerBaseDerivationPath = DerivationPath{0x80000000 + 44, 0x80000000
 // from https://archive.is/HE4rZ, albeit it's not set in stone
 // yet whether accounts should increment the last component or the children of
 // that. We will go with the simpler approach of incrementing the last component.
+//
+// Accounts held in software wallets derive along IxiosCoinType instead, see
+// IxiosBaseDerivationPath.
 type DerivationPath []uint32
 
 // ParseDerivationPath converts a user specified derivation path string to the
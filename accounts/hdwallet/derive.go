@@ -0,0 +1,135 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/crypto"
+)
+
+const hardenedOffset = 0x80000000 // First index of hardened child keys
+
+var (
+	masterKey = []byte("Bitcoin seed") // HMAC key of the BIP-32 master node
+
+	errInvalidNode  = errors.New("derived key is invalid, use the next index")
+	errNotHardened  = errors.New("post-quantum keys require a fully hardened derivation path")
+	errSeedTooShort = errors.New("seed must be at least 16 bytes")
+)
+
+// node is a BIP-32 extended private key.
+type node struct {
+	key   []byte // 32 byte secp256k1 private key
+	chain []byte // 32 byte chain code
+}
+
+// newMasterNode derives the BIP-32 master node from a seed.
+func newMasterNode(seed []byte) (*node, error) {
+	if len(seed) < 16 {
+		return nil, errSeedTooShort
+	}
+	mac := hmac.New(sha512.New, masterKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	k := new(big.Int).SetBytes(sum[:32])
+	if k.Sign() == 0 || k.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errInvalidNode
+	}
+	return &node{key: sum[:32], chain: sum[32:]}, nil
+}
+
+// child derives the child node at the given index.
+func (n *node) child(index uint32) (*node, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, n.key...)
+	} else {
+		priv, err := crypto.ToECDSA(n.key)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, crypto.CompressPubkey(&priv.PublicKey)...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, n.chain)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curveN := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, errInvalidNode
+	}
+	k := tweak.Add(tweak, new(big.Int).SetBytes(n.key))
+	k.Mod(k, curveN)
+	if k.Sign() == 0 {
+		return nil, errInvalidNode
+	}
+	return &node{key: k.FillBytes(make([]byte, 32)), chain: sum[32:]}, nil
+}
+
+// derive walks the derivation path down from the master node of the seed.
+func derive(seed []byte, path accounts.DerivationPath) (*node, error) {
+	n, err := newMasterNode(seed)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range path {
+		if n, err = n.child(index); err != nil {
+			return nil, fmt.Errorf("%v at %v", err, path[:i+1])
+		}
+	}
+	return n, nil
+}
+
+// DeriveECDSA derives the secp256k1 private key at the given BIP-32 path from a
+// seed. The address of the key follows crypto.PubkeyToAddress.
+func DeriveECDSA(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	n, err := derive(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(n.key)
+}
+
+// DeriveSeed derives the 64 byte key generation seed of a post-quantum key from
+// a seed. The path must be fully hardened, as post-quantum keys cannot support
+// public derivation; the domain separates the keys of different schemes that
+// share a path.
+func DeriveSeed(seed []byte, path accounts.DerivationPath, domain string) ([]byte, error) {
+	for _, index := range path {
+		if index < hardenedOffset {
+			return nil, errNotHardened
+		}
+	}
+	n, err := derive(seed, path)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha512.New, []byte("Ixios seed "+domain))
+	mac.Write(n.key)
+	mac.Write(n.chain)
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,162 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+// Package hdwallet implements BIP-39 mnemonics and the hierarchical
+// deterministic derivation of Ixios keys from them.
+//
+// ECDSA keys are derived along BIP-32. Post-quantum keys have no BIP-32
+// equivalent, they are generated from a seed taken from the BIP-32 node at a
+// fully hardened path, so that a single mnemonic backs up keys of every scheme.
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// DefaultEntropyBits is the entropy of newly generated mnemonics, yielding
+	// 24 words.
+	DefaultEntropyBits = 256
+
+	seedIterations = 2048 // PBKDF2 rounds of the mnemonic to seed conversion
+	seedLength     = 64   // Length of the seed derived from a mnemonic
+)
+
+var (
+	errEntropyBits      = errors.New("entropy must be 128 to 256 bits in multiples of 32")
+	errMnemonicLength   = errors.New("invalid mnemonic length")
+	errMnemonicChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// englishWords is the BIP-39 English word list.
+//
+//go:embed english.txt
+var englishWords string
+
+var (
+	wordList  = strings.Fields(englishWords)
+	wordIndex = make(map[string]int, len(wordList))
+)
+
+func init() {
+	if len(wordList) != 2048 {
+		panic(fmt.Sprintf("invalid BIP-39 word list length %d", len(wordList)))
+	}
+	for i, word := range wordList {
+		wordIndex[word] = i
+	}
+}
+
+// NewMnemonic generates a random mnemonic with the given bits of entropy.
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", errEntropyBits
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes entropy into its mnemonic, appending the checksum.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", errEntropyBits
+	}
+	checksum := bits / 32
+	hash := sha256.Sum256(entropy)
+
+	// Append the checksum bits to the entropy and split the result into 11 bit
+	// word indices, from the last word to the first.
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, uint(checksum))
+	data.Or(data, big.NewInt(int64(hash[0]>>(8-checksum))))
+
+	var (
+		words = make([]string, (bits+checksum)/11)
+		mask  = big.NewInt(2047)
+		index = new(big.Int)
+	)
+	for i := len(words) - 1; i >= 0; i-- {
+		index.And(data, mask)
+		data.Rsh(data, 11)
+		words[i] = wordList[index.Int64()]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes a mnemonic into its entropy, verifying the
+// checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, errMnemonicLength
+	}
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[strings.ToLower(word)]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic word %q", word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+	var (
+		checksum = len(words) / 3
+		size     = len(words) * 11 * 32 / 33 / 8
+		want     = new(big.Int).And(data, big.NewInt(1<<checksum-1)).Int64()
+		entropy  = make([]byte, size)
+	)
+	data.Rsh(data, uint(checksum)).FillBytes(entropy)
+
+	hash := sha256.Sum256(entropy)
+	if int64(hash[0]>>(8-checksum)) != want {
+		return nil, errMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid reports whether a mnemonic is made up of words of the list
+// and carries a valid checksum.
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err == nil
+}
+
+// NormalizeMnemonic returns the canonical form of a valid mnemonic, lowercase
+// and separated by single spaces.
+func NormalizeMnemonic(mnemonic string) string {
+	return strings.ToLower(strings.Join(strings.Fields(mnemonic), " "))
+}
+
+// MnemonicToSeed derives the BIP-39 seed of a mnemonic, protected by an
+// optional password. The mnemonic checksum is verified first.
+func MnemonicToSeed(mnemonic, password string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+	salt := norm.NFKD.String("mnemonic" + password)
+	return pbkdf2.Key([]byte(norm.NFKD.String(NormalizeMnemonic(mnemonic))), []byte(salt), seedIterations, seedLength, sha512.New), nil
+}
@@ -29,8 +29,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/accounts/hdwallet"
 	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/common/math"
+	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
+	"github.com/ixios-io/ixiosSpark/crypto/falcon"
 
 	"crypto/sha256"
 	"errors"
@@ -42,6 +47,28 @@ const (
 	version = 3
 )
 
+// Signature schemes of the keys held by the keystore, as recorded in the
+// keytype field of the key files.
+const (
+	SchemeECDSA      = "ecdsa26"
+	SchemeDilithium2 = "dilith2"
+	SchemeDilithium3 = "dilith3"
+	SchemeDilithium5 = "dilith5"
+	SchemeFalcon512  = "falcon512"
+)
+
+// Schemes lists the signature schemes supported by the keystore.
+var Schemes = []string{SchemeECDSA, SchemeDilithium2, SchemeDilithium3, SchemeDilithium5, SchemeFalcon512}
+
+// dilithiumSchemes maps the Dilithium key schemes to their parameter sets.
+var dilithiumSchemes = map[string]*dilithium.Params{
+	SchemeDilithium2: dilithium.Dilithium2,
+	SchemeDilithium3: dilithium.Dilithium3,
+	SchemeDilithium5: dilithium.Dilithium5,
+}
+
+var errUnknownScheme = errors.New("unknown key scheme")
+
 type Key struct {
 	Id uuid.UUID // Version 4 "random" for unique id not derived from key data
 	// to simplify lookups we also store the address
@@ -49,6 +76,15 @@ type Key struct {
 	// we only store privkey as pubkey/address can be derived from it
 	// privkey in this struct is always in plaintext
 	PrivateKey *ecdsa.PrivateKey
+	// Scheme is the signature scheme of the key. Post-quantum keys leave
+	// PrivateKey nil and hold their private key in the field of their scheme.
+	Scheme    string
+	Dilithium *dilithium.PrivateKey
+	Falcon    *falcon.PrivateKey
+	// Mnemonic and Path record the BIP-39 mnemonic and the derivation path the
+	// key was derived from, if any.
+	Mnemonic string
+	Path     accounts.DerivationPath
 }
 
 type keyStore interface {
@@ -63,15 +99,21 @@ type keyStore interface {
 type plainKeyJSON struct {
 	Address    string `json:"address"`
 	PrivateKey string `json:"privatekey"`
+	KeyType    string `json:"keytype,omitempty"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+	Path       string `json:"path,omitempty"`
 	Id         string `json:"id"`
 	Version    int    `json:"version"`
 }
 
 type encryptedKeyJSONV3 struct {
-	Address string     `json:"address"`
-	Crypto  CryptoJSON `json:"crypto"`
-	Id      string     `json:"id"`
-	Version int        `json:"version"`
+	Address  string      `json:"address"`
+	Crypto   CryptoJSON  `json:"crypto"`
+	KeyType  string      `json:"keytype,omitempty"`
+	Mnemonic *CryptoJSON `json:"mnemonic,omitempty"`
+	Path     string      `json:"path,omitempty"`
+	Id       string      `json:"id"`
+	Version  int         `json:"version"`
 }
 
 type encryptedKeyJSONV1 struct {
@@ -113,10 +155,15 @@ type CryptoJSON struct {
 
 func (k *Key) MarshalJSON() (j []byte, err error) {
 	jStruct := plainKeyJSON{
-		hex.EncodeToString(k.Address[:]),
-		hex.EncodeToString(crypto.FromECDSA(k.PrivateKey)),
-		k.Id.String(),
-		version,
+		Address:    hex.EncodeToString(k.Address[:]),
+		PrivateKey: hex.EncodeToString(k.privateKeyBytes()),
+		KeyType:    k.scheme(),
+		Mnemonic:   k.Mnemonic,
+		Id:         k.Id.String(),
+		Version:    version,
+	}
+	if k.Mnemonic != "" {
+		jStruct.Path = k.Path.String()
 	}
 	j, err = json.Marshal(jStruct)
 	return j, err
@@ -134,21 +181,110 @@ func (k *Key) UnmarshalJSON(data []byte) error {
 	}
 	k.Id = u
 
-	addr, err := hex.DecodeString(keyJSON.Address)
+	privkey, err := hex.DecodeString(keyJSON.PrivateKey)
 	if err != nil {
 		return err
 	}
-	k.Address = common.BytesToAddress(addr)
-
-	privkey, err := crypto.HexToECDSA(keyJSON.PrivateKey)
-	if err != nil {
+	if err := k.setPrivateKey(keyJSON.KeyType, privkey); err != nil {
 		return err
 	}
-	k.PrivateKey = privkey
+	return k.setMnemonic(keyJSON.Mnemonic, keyJSON.Path)
+}
+
+// scheme returns the signature scheme of the key.
+func (k *Key) scheme() string {
+	if k.Scheme == "" {
+		return SchemeECDSA
+	}
+	return k.Scheme
+}
+
+// privateKeyBytes returns the private key in the encoding of its scheme.
+func (k *Key) privateKeyBytes() []byte {
+	switch {
+	case k.Dilithium != nil:
+		return k.Dilithium.Bytes()
+	case k.Falcon != nil:
+		return k.Falcon.Bytes()
+	default:
+		return math.PaddedBigBytes(k.PrivateKey.D, 32)
+	}
+}
+
+// setPrivateKey decodes a private key of the given scheme into the key and
+// derives its address. An empty scheme denotes an ECDSA key.
+func (k *Key) setPrivateKey(scheme string, b []byte) error {
+	switch {
+	case scheme == "" || scheme == SchemeECDSA:
+		priv, err := crypto.ToECDSA(b)
+		if err != nil {
+			return fmt.Errorf("invalid key: %w", err)
+		}
+		k.PrivateKey, k.Scheme = priv, SchemeECDSA
+		k.Address = crypto.PubkeyToAddress(priv.PublicKey)
+
+	case dilithiumSchemes[scheme] != nil:
+		priv, err := dilithium.UnmarshalPrivateKey(dilithiumSchemes[scheme], b)
+		if err != nil {
+			return fmt.Errorf("invalid key: %w", err)
+		}
+		k.Dilithium, k.Scheme = priv, scheme
+		k.Address, _ = types.QuantumPubkeyToAddress(priv.Public().Bytes())
+
+	case scheme == SchemeFalcon512:
+		priv, err := falcon.UnmarshalPrivateKey(b)
+		if err != nil {
+			return fmt.Errorf("invalid key: %w", err)
+		}
+		k.Falcon, k.Scheme = priv, scheme
+		k.Address, _ = types.QuantumPubkeyToAddress(priv.Public().Bytes())
+
+	default:
+		return fmt.Errorf("%w: %q", errUnknownScheme, scheme)
+	}
+	return nil
+}
 
+// setMnemonic records the mnemonic and derivation path the key was derived
+// from, if any.
+func (k *Key) setMnemonic(mnemonic, path string) error {
+	if mnemonic == "" {
+		return nil
+	}
+	p, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return fmt.Errorf("invalid derivation path: %w", err)
+	}
+	k.Mnemonic, k.Path = mnemonic, p
 	return nil
 }
 
+// signHash signs the hash with the scheme of the key. ECDSA signatures are in
+// the [R || S || V] format where V is 0 or 1, post-quantum signatures are in the
+// native format of their scheme.
+func (k *Key) signHash(hash []byte) ([]byte, error) {
+	switch {
+	case k.Dilithium != nil:
+		return dilithium.Sign(k.Dilithium, hash)
+	case k.Falcon != nil:
+		return falcon.Sign(k.Falcon, hash)
+	default:
+		return crypto.Sign(hash, k.PrivateKey)
+	}
+}
+
+// signTx signs the transaction with the scheme of the key.
+func (k *Key) signTx(tx *types.Transaction, signer types.Signer) (*types.Transaction, error) {
+	switch {
+	case k.Dilithium != nil:
+		return types.SignDilithiumTx(tx, signer, k.Dilithium)
+	case k.Falcon != nil:
+		return types.SignFalconTx(tx, signer, k.Falcon)
+	default:
+		return types.SignTx(tx, signer, k.PrivateKey)
+	}
+}
+
 func newKeyFromECDSA(privateKeyECDSA *ecdsa.PrivateKey) *Key {
 	id, err := uuid.NewRandom()
 	if err != nil {
@@ -158,6 +294,7 @@ func newKeyFromECDSA(privateKeyECDSA *ecdsa.PrivateKey) *Key {
 		Id:         id,
 		Address:    crypto.PubkeyToAddress(privateKeyECDSA.PublicKey),
 		PrivateKey: privateKeyECDSA,
+		Scheme:     SchemeECDSA,
 	}
 	return key
 }
@@ -183,16 +320,112 @@ func NewKeyForDirectICAP(rand io.Reader) *Key {
 	return key
 }
 
-func newKey(rand io.Reader) (*Key, error) {
-	privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand)
+func newKey(rand io.Reader, scheme string) (*Key, error) {
+	var b []byte
+	switch {
+	case scheme == SchemeECDSA:
+		privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand)
+		if err != nil {
+			return nil, err
+		}
+		return newKeyFromECDSA(privateKeyECDSA), nil
+
+	case dilithiumSchemes[scheme] != nil:
+		priv, err := dilithium.GenerateKey(dilithiumSchemes[scheme], rand)
+		if err != nil {
+			return nil, err
+		}
+		b = priv.Bytes()
+
+	case scheme == SchemeFalcon512:
+		priv, err := falcon.GenerateKey(rand)
+		if err != nil {
+			return nil, err
+		}
+		b = priv.Bytes()
+
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownScheme, scheme)
+	}
+	return newKeyFromBytes(scheme, b)
+}
+
+// newKeyFromMnemonic derives the key of the given signature scheme at the given
+// path from a BIP-39 mnemonic. ECDSA keys derive along BIP-32, post-quantum keys
+// are generated from the seed of their fully hardened path, separated by the
+// name of their scheme.
+func newKeyFromMnemonic(mnemonic, scheme string, path accounts.DerivationPath) (*Key, error) {
+	seed, err := hdwallet.MnemonicToSeed(mnemonic, "")
 	if err != nil {
 		return nil, err
 	}
-	return newKeyFromECDSA(privateKeyECDSA), nil
+	var key *Key
+	switch {
+	case scheme == SchemeECDSA:
+		priv, err := hdwallet.DeriveECDSA(seed, path)
+		if err != nil {
+			return nil, err
+		}
+		key = newKeyFromECDSA(priv)
+
+	case dilithiumSchemes[scheme] != nil:
+		b, err := hdwallet.DeriveSeed(seed, path, scheme)
+		if err != nil {
+			return nil, err
+		}
+		priv, err := dilithium.NewKeyFromSeed(dilithiumSchemes[scheme], b[:dilithium.SeedSize])
+		if err != nil {
+			return nil, err
+		}
+		if key, err = newKeyFromBytes(scheme, priv.Bytes()); err != nil {
+			return nil, err
+		}
+
+	case scheme == SchemeFalcon512:
+		b, err := hdwallet.DeriveSeed(seed, path, scheme)
+		if err != nil {
+			return nil, err
+		}
+		priv, err := falcon.GenerateKey(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		if key, err = newKeyFromBytes(scheme, priv.Bytes()); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %q", errUnknownScheme, scheme)
+	}
+	key.Mnemonic, key.Path = hdwallet.NormalizeMnemonic(mnemonic), path
+	return key, nil
+}
+
+// BaseDerivationPath returns the default base derivation path of accounts of
+// the given signature scheme.
+func BaseDerivationPath(scheme string) accounts.DerivationPath {
+	if scheme == SchemeECDSA {
+		return accounts.IxiosBaseDerivationPath
+	}
+	return accounts.IxiosQuantumBaseDerivationPath
+}
+
+// newKeyFromBytes creates a key from a private key in the encoding of the given
+// signature scheme.
+func newKeyFromBytes(scheme string, b []byte) (*Key, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{Id: id}
+	if err := key.setPrivateKey(scheme, b); err != nil {
+		return nil, err
+	}
+	return key, nil
 }
 
-func storeNewKey(ks keyStore, rand io.Reader, auth string) (*Key, accounts.Account, error) {
-	key, err := newKey(rand)
+func storeNewKey(ks keyStore, rand io.Reader, auth string, scheme string) (*Key, accounts.Account, error) {
+	key, err := newKey(rand, scheme)
 	if err != nil {
 		return nil, accounts.Account{}, err
 	}
@@ -201,7 +434,7 @@ func storeNewKey(ks keyStore, rand io.Reader, auth string) (*Key, accounts.Accou
 		URL:     accounts.URL{Scheme: KeyStoreScheme, Path: ks.JoinPath(keyFileName(key.Address))},
 	}
 	if err := ks.StoreKey(a.URL.Path, key, auth); err != nil {
-		zeroKey(key.PrivateKey)
+		zeroKey(key)
 		return nil, a, err
 	}
 	return key, a, err
@@ -317,6 +550,7 @@ func decryptKey(fileContent []byte, password string) (key *Key, err error) {
 		Id:         uuid.UUID{},
 		Address:    crypto.PubkeyToAddress(ecKey.PublicKey),
 		PrivateKey: ecKey,
+		Scheme:     SchemeECDSA,
 	}
 	derivedAddr := hex.EncodeToString(key.Address.Bytes()) // needed because .Hex() gives leading "0x"
 	expectedAddr := preSaleKeyStruct.EthAddr
@@ -28,7 +28,6 @@ import (
 	"github.com/ixios-io/ixiosSpark/accounts"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/core/types"
-	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/event"
 )
 
@@ -40,6 +39,10 @@ var (
 	// ErrAccountAlreadyExists is returned if an account attempted to import is
 	// already present in the keystore.
 	ErrAccountAlreadyExists = errors.New("account already exists")
+
+	// ErrNoMnemonic is returned if the mnemonic of an account is requested that
+	// was not derived from one.
+	ErrNoMnemonic = errors.New("account not derived from a mnemonic")
 )
 
 // KeyStoreType is the reflect type of a keystore backend.
@@ -235,7 +238,7 @@ func (ks *KeyStore) Delete(a accounts.Account, passphrase string) error {
 	// immediately afterwards.
 	a, key, err := ks.getDecryptedKey(a, passphrase)
 	if key != nil {
-		zeroKey(key.PrivateKey)
+		zeroKey(key)
 	}
 	if err != nil {
 		return err
@@ -251,8 +254,9 @@ func (ks *KeyStore) Delete(a accounts.Account, passphrase string) error {
 	return err
 }
 
-// SignHash calculates a ECDSA signature for the given hash. The produced
-// signature is in the [R || S || V] format where V is 0 or 1.
+// SignHash calculates a signature for the given hash with the scheme of the
+// account. ECDSA signatures are in the [R || S || V] format where V is 0 or 1,
+// post-quantum signatures are in the native format of their scheme.
 func (ks *KeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 	// Look up the key to sign with and abort if it cannot be found
 	ks.mu.RLock()
@@ -262,8 +266,7 @@ func (ks *KeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 	if !found {
 		return nil, ErrLocked
 	}
-	// Sign the hash using plain ECDSA operations
-	return crypto.Sign(hash, unlockedKey.PrivateKey)
+	return unlockedKey.signHash(hash)
 }
 
 // SignTx signs the given transaction with the requested account.
@@ -278,19 +281,19 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 	}
 	// Depending on the presence of the chain ID, sign with 2718 or homestead
 	signer := types.LatestSignerForChainID(chainID)
-	return types.SignTx(tx, signer, unlockedKey.PrivateKey)
+	return unlockedKey.signTx(tx, signer)
 }
 
 // SignHashWithPassphrase signs hash if the private key matching the given address
 // can be decrypted with the given passphrase. The produced signature is in the
-// [R || S || V] format where V is 0 or 1.
+// same format as for SignHash.
 func (ks *KeyStore) SignHashWithPassphrase(a accounts.Account, passphrase string, hash []byte) (signature []byte, err error) {
 	_, key, err := ks.getDecryptedKey(a, passphrase)
 	if err != nil {
 		return nil, err
 	}
-	defer zeroKey(key.PrivateKey)
-	return crypto.Sign(hash, key.PrivateKey)
+	defer zeroKey(key)
+	return key.signHash(hash)
 }
 
 // SignTxWithPassphrase signs the transaction if the private key matching the
@@ -300,10 +303,10 @@ func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string,
 	if err != nil {
 		return nil, err
 	}
-	defer zeroKey(key.PrivateKey)
+	defer zeroKey(key)
 	// Depending on the presence of the chain ID, sign with or without replay protection.
 	signer := types.LatestSignerForChainID(chainID)
-	return types.SignTx(tx, signer, key.PrivateKey)
+	return key.signTx(tx, signer)
 }
 
 // Unlock unlocks the given account indefinitely.
@@ -343,7 +346,7 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 		if u.abort == nil {
 			// The address was unlocked indefinitely, so unlocking
 			// it with a timeout would be confusing.
-			zeroKey(key.PrivateKey)
+			zeroKey(key)
 			return nil
 		}
 		// Terminate the expire goroutine and replace it below.
@@ -390,7 +393,7 @@ func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Durati
 		// because the map stores a new pointer every time the key is
 		// unlocked.
 		if ks.unlocked[addr] == u {
-			zeroKey(u.PrivateKey)
+			zeroKey(u.Key)
 			delete(ks.unlocked, addr)
 		}
 		ks.mu.Unlock()
@@ -400,7 +403,13 @@ func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Durati
 // NewAccount generates a new key and stores it into the key directory,
 // encrypting it with the passphrase.
 func (ks *KeyStore) NewAccount(passphrase string) (accounts.Account, error) {
-	_, account, err := storeNewKey(ks.storage, crand.Reader, passphrase)
+	return ks.NewSchemeAccount(passphrase, SchemeECDSA)
+}
+
+// NewSchemeAccount generates a new key of the given signature scheme and stores
+// it into the key directory, encrypting it with the passphrase.
+func (ks *KeyStore) NewSchemeAccount(passphrase, scheme string) (accounts.Account, error) {
+	_, account, err := storeNewKey(ks.storage, crand.Reader, passphrase, scheme)
 	if err != nil {
 		return accounts.Account{}, err
 	}
@@ -426,11 +435,51 @@ func (ks *KeyStore) Export(a accounts.Account, passphrase, newPassphrase string)
 	return EncryptKey(key, newPassphrase, N, P)
 }
 
+// ImportMnemonic derives the key of the given signature scheme at the given
+// path from a BIP-39 mnemonic and stores it into the key directory, encrypting
+// it along with the mnemonic with the passphrase. A nil path selects the first
+// account of the scheme's base derivation path.
+func (ks *KeyStore) ImportMnemonic(mnemonic, scheme string, path accounts.DerivationPath, passphrase string) (accounts.Account, error) {
+	if path == nil {
+		path = BaseDerivationPath(scheme)
+	}
+	key, err := newKeyFromMnemonic(mnemonic, scheme, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(key)
+
+	ks.importMu.Lock()
+	defer ks.importMu.Unlock()
+
+	if ks.cache.hasAddress(key.Address) {
+		return accounts.Account{
+			Address: key.Address,
+		}, ErrAccountAlreadyExists
+	}
+	return ks.importKey(key, passphrase)
+}
+
+// ExportMnemonic returns the BIP-39 mnemonic and the derivation path the given
+// account was derived from.
+func (ks *KeyStore) ExportMnemonic(a accounts.Account, passphrase string) (string, accounts.DerivationPath, error) {
+	_, key, err := ks.getDecryptedKey(a, passphrase)
+	if err != nil {
+		return "", nil, err
+	}
+	defer zeroKey(key)
+
+	if key.Mnemonic == "" {
+		return "", nil, ErrNoMnemonic
+	}
+	return key.Mnemonic, key.Path, nil
+}
+
 // Import stores the given encrypted JSON key into the key directory.
 func (ks *KeyStore) Import(keyJSON []byte, passphrase, newPassphrase string) (accounts.Account, error) {
 	key, err := DecryptKey(keyJSON, passphrase)
-	if key != nil && key.PrivateKey != nil {
-		defer zeroKey(key.PrivateKey)
+	if key != nil {
+		defer zeroKey(key)
 	}
 	if err != nil {
 		return accounts.Account{}, err
@@ -460,6 +509,27 @@ func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (acco
 	return ks.importKey(key, passphrase)
 }
 
+// ImportSchemeKey stores a private key of the given signature scheme, in the
+// encoding of that scheme, into the key directory, encrypting it with the
+// passphrase.
+func (ks *KeyStore) ImportSchemeKey(scheme string, priv []byte, passphrase string) (accounts.Account, error) {
+	ks.importMu.Lock()
+	defer ks.importMu.Unlock()
+
+	key, err := newKeyFromBytes(scheme, priv)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(key)
+
+	if ks.cache.hasAddress(key.Address) {
+		return accounts.Account{
+			Address: key.Address,
+		}, ErrAccountAlreadyExists
+	}
+	return ks.importKey(key, passphrase)
+}
+
 func (ks *KeyStore) importKey(key *Key, passphrase string) (accounts.Account, error) {
 	a := accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: KeyStoreScheme, Path: ks.storage.JoinPath(keyFileName(key.Address))}}
 	if err := ks.storage.StoreKey(a.URL.Path, key, passphrase); err != nil {
@@ -499,10 +569,14 @@ func (ks *KeyStore) isUpdating() bool {
 	return ks.updating
 }
 
-// zeroKey zeroes a private key in memory.
-func zeroKey(k *ecdsa.PrivateKey) {
-	b := k.D.Bits()
-	for i := range b {
-		b[i] = 0
+// zeroKey zeroes a private key in memory. Post-quantum private keys cannot be
+// wiped in place, so they are only dropped.
+func zeroKey(k *Key) {
+	if k.PrivateKey != nil {
+		b := k.PrivateKey.D.Bits()
+		for i := range b {
+			b[i] = 0
+		}
 	}
+	k.Dilithium, k.Falcon, k.Mnemonic = nil, nil, ""
 }
@@ -34,7 +34,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/ixios-io/ixiosSpark/accounts"
 	"github.com/ixios-io/ixiosSpark/common"
-	"github.com/ixios-io/ixiosSpark/common/math"
 	"github.com/ixios-io/ixiosSpark/crypto"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
@@ -94,7 +93,13 @@ func (ks keyStorePassphrase) GetKey(addr common.Address, filename, auth string)
 
 // StoreKey generates a key, encrypts with 'auth' and stores in the given directory
 func StoreKey(dir, auth string, scryptN, scryptP int) (accounts.Account, error) {
-	_, a, err := storeNewKey(&keyStorePassphrase{dir, scryptN, scryptP, false}, rand.Reader, auth)
+	return StoreSchemeKey(dir, auth, SchemeECDSA, scryptN, scryptP)
+}
+
+// StoreSchemeKey generates a key of the given signature scheme, encrypts with
+// 'auth' and stores in the given directory.
+func StoreSchemeKey(dir, auth, scheme string, scryptN, scryptP int) (accounts.Account, error) {
+	_, a, err := storeNewKey(&keyStorePassphrase{dir, scryptN, scryptP, false}, rand.Reader, auth, scheme)
 	return a, err
 }
 
@@ -191,16 +196,26 @@ func EncryptDataV3(data, auth []byte, scryptN, scryptP int) (CryptoJSON, error)
 // EncryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on.
 func EncryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
-	keyBytes := math.PaddedBigBytes(key.PrivateKey.D, 32)
-	cryptoStruct, err := EncryptDataV3(keyBytes, []byte(auth), scryptN, scryptP)
+	cryptoStruct, err := EncryptDataV3(key.privateKeyBytes(), []byte(auth), scryptN, scryptP)
 	if err != nil {
 		return nil, err
 	}
 	encryptedKeyJSONV3 := encryptedKeyJSONV3{
-		hex.EncodeToString(key.Address[:]),
-		cryptoStruct,
-		key.Id.String(),
-		version,
+		Address: hex.EncodeToString(key.Address[:]),
+		Crypto:  cryptoStruct,
+		KeyType: key.scheme(),
+		Id:      key.Id.String(),
+		Version: version,
+	}
+	// Keys derived from a mnemonic carry it along, encrypted the same way, so
+	// that it can be exported again.
+	if key.Mnemonic != "" {
+		mnemonic, err := EncryptDataV3([]byte(key.Mnemonic), []byte(auth), scryptN, scryptP)
+		if err != nil {
+			return nil, err
+		}
+		encryptedKeyJSONV3.Mnemonic = &mnemonic
+		encryptedKeyJSONV3.Path = key.Path.String()
 	}
 	return json.Marshal(encryptedKeyJSONV3)
 }
@@ -215,6 +230,8 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 	// Depending on the version try to parse one way or another
 	var (
 		keyBytes, keyId []byte
+		scheme, path    string
+		mnemonic        []byte
 		err             error
 	)
 	if version, ok := m["version"].(string); ok && version == "1" {
@@ -229,24 +246,28 @@ func DecryptKey(keyjson []byte, auth string) (*Key, error) {
 			return nil, err
 		}
 		keyBytes, keyId, err = decryptKeyV3(k, auth)
+		scheme, path = k.KeyType, k.Path
+
+		if err == nil && k.Mnemonic != nil {
+			mnemonic, err = DecryptDataV3(*k.Mnemonic, auth)
+		}
 	}
 	// Handle any decryption errors and return the key
 	if err != nil {
 		return nil, err
 	}
-	key, err := crypto.ToECDSA(keyBytes)
-	if err != nil {
-		return nil, fmt.Errorf("invalid key: %w", err)
-	}
 	id, err := uuid.FromBytes(keyId)
 	if err != nil {
 		return nil, fmt.Errorf("invalid UUID: %w", err)
 	}
-	return &Key{
-		Id:         id,
-		Address:    crypto.PubkeyToAddress(key.PublicKey),
-		PrivateKey: key,
-	}, nil
+	key := &Key{Id: id}
+	if err := key.setPrivateKey(scheme, keyBytes); err != nil {
+		return nil, err
+	}
+	if err := key.setMnemonic(string(mnemonic), path); err != nil {
+		return nil, err
+	}
+	return key, nil
 }
 
 func DecryptDataV3(cryptoJson CryptoJSON, auth string) ([]byte, error) {
@@ -25,6 +25,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/rpc"
+	"github.com/ixios-io/ixiosSpark/trie"
 )
 
 // Client defines typed wrappers for the Ixios RPC API.
@@ -309,6 +310,119 @@ func (ec *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*
 	return r, err
 }
 
+// rpcInclusionProof is the JSON form of an inclusion proof.
+type rpcInclusionProof struct {
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	Value            hexutil.Bytes   `json:"value"`
+	Proof            []hexutil.Bytes `json:"proof"`
+}
+
+// TransactionProof returns the proof of inclusion of a transaction in its block.
+// Check it against a trusted header of the block with VerifyTransactionProof.
+func (ec *Client) TransactionProof(ctx context.Context, txHash common.Hash) (*types.InclusionProof, error) {
+	return ec.inclusionProof(ctx, "eth_getTransactionProof", txHash)
+}
+
+// ReceiptProof returns the proof of inclusion of the receipt of a transaction in
+// its block. Check it against a trusted header of the block with
+// VerifyReceiptProof.
+func (ec *Client) ReceiptProof(ctx context.Context, txHash common.Hash) (*types.InclusionProof, error) {
+	return ec.inclusionProof(ctx, "eth_getReceiptProof", txHash)
+}
+
+func (ec *Client) inclusionProof(ctx context.Context, method string, txHash common.Hash) (*types.InclusionProof, error) {
+	var r *rpcInclusionProof
+	err := ec.c.CallContext(ctx, &r, method, txHash)
+	if err != nil {
+		return nil, err
+	} else if r == nil {
+		return nil, ixiosSpark.NotFound
+	}
+	proof := &types.InclusionProof{
+		BlockHash:   r.BlockHash,
+		BlockNumber: uint64(r.BlockNumber),
+		Index:       uint64(r.TransactionIndex),
+		Value:       r.Value,
+		Proof:       make([][]byte, len(r.Proof)),
+	}
+	for i, node := range r.Proof {
+		proof.Proof[i] = node
+	}
+	return proof, nil
+}
+
+// ProvenReceipt returns the receipt of a transaction included in the block of
+// the given trusted header. Both the receipt and the transaction it belongs to
+// are verified against the header, so the node need not be trusted.
+func (ec *Client) ProvenReceipt(ctx context.Context, header *types.Header, txHash common.Hash) (*types.Receipt, error) {
+	txProof, err := ec.TransactionProof(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := VerifyTransactionProof(header, txProof)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Hash() != txHash {
+		return nil, fmt.Errorf("transaction %x proven instead of %x", tx.Hash(), txHash)
+	}
+	receiptProof, err := ec.ReceiptProof(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receiptProof.Index != txProof.Index {
+		return nil, fmt.Errorf("receipt proven at index %d, transaction at %d", receiptProof.Index, txProof.Index)
+	}
+	receipt, err := VerifyReceiptProof(header, receiptProof)
+	if err != nil {
+		return nil, err
+	}
+	receipt.TxHash = txHash
+	return receipt, nil
+}
+
+// VerifyTransactionProof checks the proof of inclusion of a transaction against
+// the header of its block and returns the proven transaction.
+func VerifyTransactionProof(header *types.Header, proof *types.InclusionProof) (*types.Transaction, error) {
+	if err := verifyInclusionProof(header, header.TxHash, proof); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(proof.Value); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// VerifyReceiptProof checks the proof of inclusion of a receipt against the
+// header of its block and returns the proven receipt. Receipts do not commit to
+// their transaction: to tie the receipt to one, verify the proof of the
+// transaction at the same index too, as ProvenReceipt does.
+func VerifyReceiptProof(header *types.Header, proof *types.InclusionProof) (*types.Receipt, error) {
+	if err := verifyInclusionProof(header, header.ReceiptHash, proof); err != nil {
+		return nil, err
+	}
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalBinary(proof.Value); err != nil {
+		return nil, err
+	}
+	receipt.BlockHash = proof.BlockHash
+	receipt.BlockNumber = new(big.Int).SetUint64(proof.BlockNumber)
+	receipt.TransactionIndex = uint(proof.Index)
+	return receipt, nil
+}
+
+// verifyInclusionProof checks an inclusion proof to be for the block of the
+// given header and to prove its value against the given root of the header.
+func verifyInclusionProof(header *types.Header, root common.Hash, proof *types.InclusionProof) error {
+	if hash := header.Hash(); proof.BlockHash != hash {
+		return fmt.Errorf("proof for block %x, header of %x", proof.BlockHash, hash)
+	}
+	return trie.VerifyInclusionProof(root, proof)
+}
+
 // SyncProgress retrieves the current progress of the sync algorithm. If there's
 // no sync currently running, it returns nil.
 func (ec *Client) SyncProgress(ctx context.Context) (*ixiosSpark.SyncProgress, error) {
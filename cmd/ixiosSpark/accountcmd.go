@@ -14,12 +14,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ixios-io/ixiosSpark/console/prompt"
 
 	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/accounts/hdwallet"
 	"github.com/ixios-io/ixiosSpark/accounts/keystore"
 	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/log"
@@ -33,7 +36,8 @@ var (
 		Description: `
 
 Manage accounts, list all existing accounts, import a private key into a new
-account, create a new account or update an existing account.
+account, create a new account, derive accounts from a mnemonic or update an
+existing account.
 
 It supports interactive mode, when you are prompted for password as well as
 non-interactive mode where passwords are supplied via a given password file.
@@ -70,12 +74,17 @@ Print a short summary of all accounts`,
 					DataDirFlag,
 					KeyStoreDirFlag,
 					PasswordFileFlag,
+					KeySchemeFlag,
 				},
 				Description: `
     ixiosSpark account new
 
 Creates a new account and prints the address.
 
+The signature scheme of the key is selected with --scheme, one of ecdsa26
+(default), dilith2, dilith3, dilith5 or falcon512. The address prefix follows
+the scheme.
+
 The account is saved in encrypted format, you are prompted for a password.
 
 You must remember this password to unlock your account in the future.
@@ -144,6 +153,54 @@ Note:
 As you can directly copy your encrypted accounts to another ixios instance,
 this import mechanism is not needed when you transfer an account between
 nodes.
+`,
+			},
+			{
+				Name:   "derive",
+				Usage:  "Derive accounts from a BIP-39 mnemonic",
+				Action: accountDerive,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					KeyStoreDirFlag,
+					PasswordFileFlag,
+					KeySchemeFlag,
+					HDPathFlag,
+					DeriveCountFlag,
+				},
+				Description: `
+    ixiosSpark account derive [options]
+
+Derives accounts from a BIP-39 mnemonic and imports them into the keystore,
+so that all of them can be restored from the one phrase. You are prompted for
+the mnemonic; if none is given, a new 24 word mnemonic is generated and printed.
+
+The signature scheme of the keys is selected with --scheme. Accounts are derived
+along --hdpath, incrementing its last component for each of the --count
+accounts. ECDSA accounts default to m/44'/1791'/0'/0/0, post-quantum accounts
+only derive along hardened paths and default to m/44'/1791'/0'/0'/0'.
+
+The accounts are saved in encrypted format along with the mnemonic, you are
+prompted for a password. The mnemonic can be printed again with
+'ixiosSpark account mnemonic'.
+`,
+			},
+			{
+				Name:      "mnemonic",
+				Usage:     "Print the BIP-39 mnemonic an account was derived from",
+				Action:    accountMnemonic,
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					KeyStoreDirFlag,
+					PasswordFileFlag,
+				},
+				Description: `
+    ixiosSpark account mnemonic <address>
+
+Prints the BIP-39 mnemonic and the derivation path of an account created with
+'ixiosSpark account derive'. You are prompted for the password of the account.
+
+Anyone knowing the mnemonic controls every account derived from it.
 `,
 			},
 		},
@@ -294,7 +351,7 @@ func accountCreate(ctx *cli.Context) error {
 
 	password := getPassPhraseWithList("Your new account is locked with a password. Please provide a password. Do not forget this password.", true, 0, MakePasswordList(ctx))
 
-	account, err := keystore.StoreKey(keydir, password, scryptN, scryptP)
+	account, err := keystore.StoreSchemeKey(keydir, password, ctx.String(KeySchemeFlag.Name), scryptN, scryptP)
 
 	if err != nil {
 		Fatalf("Failed to create account: %v", err)
@@ -382,3 +439,78 @@ func accountImport(ctx *cli.Context) error {
 	fmt.Printf("Address: {%x}\n", acct.Address)
 	return nil
 }
+
+// accountDerive derives accounts from a BIP-39 mnemonic into the keystore
+// defined by the CLI flags.
+func accountDerive(ctx *cli.Context) error {
+	scheme := ctx.String(KeySchemeFlag.Name)
+	base := keystore.BaseDerivationPath(scheme)
+	if ctx.IsSet(HDPathFlag.Name) {
+		path, err := accounts.ParseDerivationPath(ctx.String(HDPathFlag.Name))
+		if err != nil {
+			Fatalf("Invalid derivation path: %v", err)
+		}
+		base = path
+	}
+	mnemonic, err := prompt.Stdin.PromptPassword("Mnemonic (leave empty to generate a new one): ")
+	if err != nil {
+		Fatalf("Failed to read mnemonic: %v", err)
+	}
+	if strings.TrimSpace(mnemonic) == "" {
+		if mnemonic, err = hdwallet.NewMnemonic(hdwallet.DefaultEntropyBits); err != nil {
+			Fatalf("Failed to generate mnemonic: %v", err)
+		}
+		fmt.Printf("\nYour new mnemonic was generated\n\n%s\n\n", mnemonic)
+		fmt.Printf("- You must BACKUP your mnemonic! It restores every account derived from it.\n")
+		fmt.Printf("- You must NEVER share the mnemonic with anyone! It controls access to your funds!\n\n")
+	} else if !hdwallet.IsMnemonicValid(mnemonic) {
+		Fatalf("Invalid mnemonic")
+	}
+	am := makeAccountManager(ctx)
+	backends := am.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		Fatalf("Keystore is not available")
+	}
+	ks := backends[0].(*keystore.KeyStore)
+	passphrase := getPassPhraseWithList("Your new accounts are locked with a password. Please give a password. Do not forget this password.", true, 0, MakePasswordList(ctx))
+
+	next := accounts.DefaultIterator(base)
+	for i := uint(0); i < ctx.Uint(DeriveCountFlag.Name); i++ {
+		path := next()
+		acct, err := ks.ImportMnemonic(mnemonic, scheme, path, passphrase)
+		switch {
+		case errors.Is(err, keystore.ErrAccountAlreadyExists):
+			fmt.Printf("Address: {%x} %s (already in keystore)\n", acct.Address, path)
+		case err != nil:
+			Fatalf("Could not derive the account at %s: %v", path, err)
+		default:
+			fmt.Printf("Address: {%x} %s\n", acct.Address, path)
+		}
+	}
+	return nil
+}
+
+// accountMnemonic prints the mnemonic an account was derived from.
+func accountMnemonic(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		Fatalf("address must be given as the only argument")
+	}
+	am := makeAccountManager(ctx)
+	backends := am.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		Fatalf("Keystore is not available")
+	}
+	ks := backends[0].(*keystore.KeyStore)
+	account, err := MakeAddress(ks, ctx.Args().First())
+	if err != nil {
+		Fatalf("Could not find the account: %v", err)
+	}
+	passphrase := getPassPhraseWithList("", false, 0, MakePasswordList(ctx))
+
+	mnemonic, path, err := ks.ExportMnemonic(account, passphrase)
+	if err != nil {
+		Fatalf("Could not export the mnemonic: %v", err)
+	}
+	fmt.Printf("Mnemonic: %s\nPath: %s\n", mnemonic, path)
+	return nil
+}
@@ -233,7 +233,16 @@ func setAccountManagerBackends(conf *node.Config, am *accounts.Manager, keydir s
 		scryptP = keystore.LightScryptP
 	}
 
-	// Assemble the supported backends
+	// Assemble the supported backends, starting with the cosigners holding the
+	// member keys of a validator key group, which complement either signer
+	for _, url := range conf.ExternalCosigners {
+		log.Info("Using external cosigner", "url", url)
+		extBackend, err := external.NewExternalBackend(url)
+		if err != nil {
+			return fmt.Errorf("error connecting to external cosigner: %v", err)
+		}
+		am.AddBackend(extBackend)
+	}
 	if len(conf.ExternalSigner) > 0 {
 		log.Info("Using external signer", "url", conf.ExternalSigner)
 		if extBackend, err := external.NewExternalBackend(conf.ExternalSigner); err == nil {
@@ -225,10 +225,20 @@ var (
 	}
 	SyncModeFlag = &flags.TextMarshalerFlag{
 		Name:     "syncmode",
-		Usage:    `Blockchain sync mode ("full")`,
+		Usage:    `Blockchain sync mode ("full" or "light" to only verify headers)`,
 		Value:    &defaultSyncMode,
 		Category: flags.StateCategory,
 	}
+	SyncCheckpointFlag = &cli.StringFlag{
+		Name:     "sync.checkpoint",
+		Usage:    "Hash of a trusted epoch checkpoint header to snap sync (requires --snapshot) or light sync an empty chain from",
+		Category: flags.StateCategory,
+	}
+	SyncCheckpointTDFlag = &flags.BigFlag{
+		Name:     "sync.checkpoint.td",
+		Usage:    "Total difficulty of the trusted sync checkpoint header, as reported by a synced node",
+		Category: flags.StateCategory,
+	}
 	GCModeFlag = &cli.StringFlag{
 		Name:     "gcmode",
 		Usage:    `Blockchain garbage collection mode, only relevant in state.scheme=hash ("full", "archive")`,
@@ -449,12 +459,35 @@ var (
 		TakesFile: true,
 		Category:  flags.AccountCategory,
 	}
+	KeySchemeFlag = &cli.StringFlag{
+		Name:     "scheme",
+		Usage:    "Signature scheme of the new key (" + strings.Join(keystore.Schemes, ", ") + ")",
+		Value:    keystore.SchemeECDSA,
+		Category: flags.AccountCategory,
+	}
+	HDPathFlag = &cli.StringFlag{
+		Name:     "hdpath",
+		Usage:    "Base derivation path of accounts derived from a mnemonic (default depends on --scheme)",
+		Category: flags.AccountCategory,
+	}
+	DeriveCountFlag = &cli.UintFlag{
+		Name:     "count",
+		Usage:    "Number of accounts to derive from a mnemonic",
+		Value:    1,
+		Category: flags.AccountCategory,
+	}
 	ExternalSignerFlag = &cli.StringFlag{
 		Name:     "signer",
 		Usage:    "External signer (url or path to ipc file)",
 		Value:    "",
 		Category: flags.AccountCategory,
 	}
+	ExternalCosignersFlag = &cli.StringFlag{
+		Name:     "signer.cosigners",
+		Usage:    "Comma separated external signers (urls or paths to ipc files) holding member keys of the validator key group",
+		Value:    "",
+		Category: flags.AccountCategory,
+	}
 	InsecureUnlockAllowedFlag = &cli.BoolFlag{
 		Name:     "allow-insecure-unlock",
 		Usage:    "Allow insecure account unlocking when account-related RPCs are exposed by http",
@@ -1224,6 +1257,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(ExternalSignerFlag.Name) {
 		cfg.ExternalSigner = ctx.String(ExternalSignerFlag.Name)
 	}
+	if ctx.IsSet(ExternalCosignersFlag.Name) {
+		cfg.ExternalCosigners = SplitAndTrim(ctx.String(ExternalCosignersFlag.Name))
+	}
 
 	if ctx.IsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.String(KeyStoreDirFlag.Name)
@@ -1440,12 +1476,30 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 
 	syncMode := *flags.GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	if ctx.IsSet(SyncModeFlag.Name) {
-		if syncMode == downloader.FullSync {
-			cfg.SyncMode = downloader.FullSync
-		} else {
+		switch syncMode {
+		case downloader.FullSync, downloader.LightSync:
+			cfg.SyncMode = syncMode
+		default:
 			Fatalf("Unsupported syncmode")
 		}
 	}
+	if ctx.IsSet(SyncCheckpointFlag.Name) {
+		if cfg.SyncMode != downloader.LightSync && !ctx.Bool(SnapshotFlag.Name) {
+			Fatalf("--%s requires --%s", SyncCheckpointFlag.Name, SnapshotFlag.Name)
+		}
+		var hash common.Hash
+		if err := hash.UnmarshalText([]byte(ctx.String(SyncCheckpointFlag.Name))); err != nil {
+			Fatalf("Invalid sync checkpoint hash %s: %v", ctx.String(SyncCheckpointFlag.Name), err)
+		}
+		if !ctx.IsSet(SyncCheckpointTDFlag.Name) {
+			Fatalf("--%s requires --%s", SyncCheckpointFlag.Name, SyncCheckpointTDFlag.Name)
+		}
+		if cfg.SyncMode != downloader.LightSync {
+			cfg.SyncMode = downloader.SnapSync
+		}
+		cfg.SyncCheckpoint = hash
+		cfg.SyncCheckpointTD = flags.GlobalBig(ctx, SyncCheckpointTDFlag.Name)
+	}
 
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
@@ -1500,9 +1554,15 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		cfg.FilterLogCacheSize = ctx.Int(CacheLogSizeFlag.Name)
 	}
 
-	// Disable Snap
-	cfg.TrieCleanCache += cfg.SnapshotCache
-	cfg.SnapshotCache = 0
+	// Disable Snap, unless snapshots were explicitly enabled
+	if ctx.Bool(SnapshotFlag.Name) {
+		if cfg.SnapshotCache == 0 {
+			cfg.SnapshotCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheSnapshotFlag.Name) / 100
+		}
+	} else {
+		cfg.TrieCleanCache += cfg.SnapshotCache
+		cfg.SnapshotCache = 0
+	}
 
 	if ctx.IsSet(DocRootFlag.Name) {
 		cfg.DocRoot = ctx.String(DocRootFlag.Name)
@@ -1716,8 +1776,6 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) kvdb.D
 			break
 		}
 		chainDb = remotedb.New(client)
-	case ctx.String(SyncModeFlag.Name) == "light":
-		chainDb, err = stack.OpenDatabase("lightchaindata", cache, handles, "", readonly)
 	default:
 		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.String(AncientFlag.Name), "", readonly)
 	}
@@ -69,6 +69,7 @@ var (
 		MinFreeDiskSpaceFlag,
 		KeyStoreDirFlag,
 		ExternalSignerFlag,
+		ExternalCosignersFlag,
 		USBFlag,
 		OverrideCancun,
 		TxPoolLocalsFlag,
@@ -86,6 +87,8 @@ var (
 		BlobPoolDataCapFlag,
 		BlobPoolPriceBumpFlag,
 		SyncModeFlag,
+		SyncCheckpointFlag,
+		SyncCheckpointTDFlag,
 		SyncTargetFlag,
 		ExitWhenSyncedFlag,
 		GCModeFlag,
@@ -183,6 +186,10 @@ func init() {
 		dumpGenesisCommand,
 		// See accountcmd.go:
 		accountCommand,
+		// See validatorcmd.go:
+		validatorCommand,
+		// See signercmd.go:
+		signerCommand,
 		// See consolecmd.go:
 		consoleCommand,
 		attachCommand,
@@ -0,0 +1,452 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/accounts/keystore"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/internal/flags"
+	"github.com/ixios-io/ixiosSpark/log"
+	"github.com/ixios-io/ixiosSpark/node"
+	"github.com/ixios-io/ixiosSpark/params"
+	"github.com/ixios-io/ixiosSpark/rpc"
+	"github.com/ixios-io/ixiosSpark/signer/core"
+	"github.com/ixios-io/ixiosSpark/signer/fourbyte"
+	"github.com/ixios-io/ixiosSpark/signer/rules"
+	"github.com/ixios-io/ixiosSpark/signer/storage"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	signerSeedFile    = "masterseed.json" // Encrypted master seed of the signer's storages
+	signerSeedLength  = 256               // Length of the master seed
+	signerIPCFile     = "signer.ipc"      // Default IPC endpoint within the config directory
+	signerRulesetHash = "ruleset_sha256"  // Config storage key of the attested ruleset
+)
+
+var (
+	signerConfigDirFlag = &cli.PathFlag{
+		Name:      "signer.configdir",
+		Usage:     "Directory for the signer's master seed, credentials and audit log (default = inside the datadir)",
+		TakesFile: true,
+	}
+	signerChainIDFlag = &cli.Uint64Flag{
+		Name:  "signer.chainid",
+		Usage: "Chain id to sign transactions for",
+		Value: params.MainnetChainConfig.ChainID.Uint64(),
+	}
+	signerRulesFlag = &cli.PathFlag{
+		Name:      "signer.rules",
+		Usage:     "JavaScript ruleset to approve requests with, must be attested with 'signer attest'",
+		TakesFile: true,
+	}
+	signerAuditLogFlag = &cli.PathFlag{
+		Name:      "signer.auditlog",
+		Usage:     "File to log all signing requests to (default = audit.log inside the config directory)",
+		TakesFile: true,
+	}
+	signerAdvancedFlag = &cli.BoolFlag{
+		Name:  "signer.advanced",
+		Usage: "Warn instead of rejecting transactions failing validation",
+	}
+	signerStdioUIFlag = &cli.BoolFlag{
+		Name:  "signer.stdio-ui",
+		Usage: "Talk to an external UI over stdin/stdout instead of prompting on the terminal",
+	}
+	signerHTTPFlag = &cli.BoolFlag{
+		Name:  "signer.http",
+		Usage: "Enable the HTTP-RPC server of the signer",
+	}
+	signerHTTPAddrFlag = &cli.StringFlag{
+		Name:  "signer.http.addr",
+		Usage: "HTTP-RPC server listening interface of the signer",
+		Value: node.DefaultHTTPHost,
+	}
+	signerHTTPPortFlag = &cli.IntFlag{
+		Name:  "signer.http.port",
+		Usage: "HTTP-RPC server listening port of the signer",
+		Value: 8550,
+	}
+	signerHTTPVHostsFlag = &cli.StringFlag{
+		Name:  "signer.http.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.HTTPVirtualHosts, ","),
+	}
+	signerIPCDisabledFlag = &cli.BoolFlag{
+		Name:  "signer.ipcdisable",
+		Usage: "Disable the IPC-RPC server of the signer",
+	}
+	signerIPCPathFlag = &cli.PathFlag{
+		Name:      "signer.ipcpath",
+		Usage:     "Filename for the IPC socket of the signer (default = signer.ipc inside the config directory)",
+		TakesFile: true,
+	}
+
+	signerFlags = []cli.Flag{
+		DataDirFlag,
+		KeyStoreDirFlag,
+		signerConfigDirFlag,
+	}
+
+	signerCommand = &cli.Command{
+		Name:   "signer",
+		Usage:  "Run a standalone signer serving the account API",
+		Action: signerRun,
+		Flags: flags.Merge(signerFlags, []cli.Flag{
+			signerChainIDFlag,
+			signerRulesFlag,
+			signerAuditLogFlag,
+			signerAdvancedFlag,
+			signerStdioUIFlag,
+			signerHTTPFlag,
+			signerHTTPAddrFlag,
+			signerHTTPPortFlag,
+			signerHTTPVHostsFlag,
+			signerIPCDisabledFlag,
+			signerIPCPathFlag,
+		}),
+		Description: `
+    ixiosSpark signer [options]
+
+Runs a signer holding the keys of the keystore outside of the node process. It
+serves the account API over IPC and optionally HTTP, which nodes connect to with
+--signer, e.g. validators sealing blocks with a key the node never sees.
+
+Every request is confirmed on the terminal, or by an external UI with
+--signer.stdio-ui, unless a JavaScript ruleset given with --signer.rules approves
+or rejects it. All requests are written to the audit log.
+
+Account passwords and the state of the ruleset are kept in storages encrypted
+with keys derived from a master seed, created with 'ixiosSpark signer init'. The
+master seed is unlocked with its password when the signer starts.`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "init",
+				Usage:  "Create the master seed of the signer",
+				Action: signerInit,
+				Flags:  signerFlags,
+				Description: `
+    ixiosSpark signer init
+
+Creates the master seed the signer derives the keys of its encrypted storages
+from, encrypted with a password you are prompted for.`,
+			},
+			{
+				Name:      "setpw",
+				Usage:     "Store the password of an account in the signer",
+				Action:    signerSetPassword,
+				ArgsUsage: "<address>",
+				Flags:     signerFlags,
+				Description: `
+    ixiosSpark signer setpw <address>
+
+Stores the password of an account in the encrypted credential storage, so that
+the signer can unlock the account for requests approved by the ruleset without
+prompting.`,
+			},
+			{
+				Name:      "delpw",
+				Usage:     "Remove the password of an account from the signer",
+				Action:    signerDeletePassword,
+				ArgsUsage: "<address>",
+				Flags:     signerFlags,
+				Description: `
+    ixiosSpark signer delpw <address>
+
+Removes the password of an account from the encrypted credential storage.`,
+			},
+			{
+				Name:      "attest",
+				Usage:     "Attest the sha256 hash of a ruleset",
+				Action:    signerAttest,
+				ArgsUsage: "<sha256>",
+				Flags:     signerFlags,
+				Description: `
+    ixiosSpark signer attest <sha256>
+
+Records the sha256 hash of the ruleset the signer may run with. The signer
+refuses to start with a ruleset whose hash was not attested, so that the rules
+cannot be altered behind its back.`,
+			},
+		},
+	}
+)
+
+// signerStorages are the encrypted storages of the signer, keyed by the
+// master seed.
+type signerStorages struct {
+	credentials storage.Storage // Account passwords
+	rules       storage.Storage // State of the JavaScript ruleset
+	config      storage.Storage // Configuration, e.g. the attested ruleset
+}
+
+// signerConfigDir returns the directory holding the signer's master seed and
+// storages.
+func signerConfigDir(ctx *cli.Context) string {
+	if ctx.IsSet(signerConfigDirFlag.Name) {
+		return ctx.Path(signerConfigDirFlag.Name)
+	}
+	cfg := loadBaseConfig(ctx)
+	if cfg.Node.DataDir == "" {
+		Fatalf("Can't use an ephemeral datadir for the signer, set --signer.configdir")
+	}
+	return filepath.Join(cfg.Node.DataDir, "signer")
+}
+
+// openSignerStorages unlocks the master seed and opens the storages keyed by
+// it. The master seed password is prompted for and never stored.
+func openSignerStorages(ctx *cli.Context) *signerStorages {
+	dir := signerConfigDir(ctx)
+	blob, err := os.ReadFile(filepath.Join(dir, signerSeedFile))
+	if err != nil {
+		Fatalf("Failed to read the master seed, create one with 'ixiosSpark signer init': %v", err)
+	}
+	var enc keystore.CryptoJSON
+	if err := json.Unmarshal(blob, &enc); err != nil {
+		Fatalf("Failed to parse the master seed: %v", err)
+	}
+	password := getPassPhrase("Please give the password of the signer's master seed.", false)
+	seed, err := keystore.DecryptDataV3(enc, password)
+	if err != nil {
+		Fatalf("Failed to decrypt the master seed: %v", err)
+	}
+	if len(seed) != signerSeedLength {
+		Fatalf("Invalid master seed length %d", len(seed))
+	}
+	// The storages live in a directory named after the seed, so that a new seed
+	// never reads the storages of a previous one.
+	vault := filepath.Join(dir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), seed)[:10]))
+	if err := os.MkdirAll(vault, 0700); err != nil {
+		Fatalf("Failed to create the signer storage directory: %v", err)
+	}
+	return &signerStorages{
+		credentials: storage.NewAESEncryptedStorage(filepath.Join(vault, "credentials.json"), crypto.Keccak256([]byte("credentials"), seed)[:32]),
+		rules:       storage.NewAESEncryptedStorage(filepath.Join(vault, "jsstorage.json"), crypto.Keccak256([]byte("jsstorage"), seed)[:32]),
+		config:      storage.NewAESEncryptedStorage(filepath.Join(vault, "config.json"), crypto.Keccak256([]byte("config"), seed)[:32]),
+	}
+}
+
+// signerAddress parses the address given as the only argument.
+func signerAddress(ctx *cli.Context) common.Address {
+	if ctx.Args().Len() != 1 {
+		Fatalf("address must be given as the only argument")
+	}
+	addr, err := common.ParseAddress(ctx.Args().First())
+	if err != nil {
+		Fatalf("Invalid address: %v", err)
+	}
+	return addr
+}
+
+func signerInit(ctx *cli.Context) error {
+	dir := signerConfigDir(ctx)
+	file := filepath.Join(dir, signerSeedFile)
+	if _, err := os.Stat(file); err == nil {
+		Fatalf("Master seed %s already exists", file)
+	}
+	password := getPassPhrase("The master seed of the signer is locked with a password. Please give a password. Do not forget this password.", true)
+	if err := core.ValidatePasswordFormat(password); err != nil {
+		Fatalf("Invalid password: %v", err)
+	}
+	seed := make([]byte, signerSeedLength)
+	if _, err := rand.Read(seed); err != nil {
+		Fatalf("Failed to generate the master seed: %v", err)
+	}
+	enc, err := keystore.EncryptDataV3(seed, []byte(password), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		Fatalf("Failed to encrypt the master seed: %v", err)
+	}
+	blob, err := json.Marshal(enc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		Fatalf("Failed to create the signer config directory: %v", err)
+	}
+	if err := os.WriteFile(file, blob, 0400); err != nil {
+		Fatalf("Failed to write the master seed: %v", err)
+	}
+	fmt.Printf("Master seed written to %s\n", file)
+	fmt.Printf("- You must BACKUP the master seed and REMEMBER its password! Without them the stored credentials are lost.\n")
+	return nil
+}
+
+func signerSetPassword(ctx *cli.Context) error {
+	addr := signerAddress(ctx)
+	stores := openSignerStorages(ctx)
+
+	password := getPassPhrase(fmt.Sprintf("Please give the password of account %s.", addr.Hex()), true)
+	stores.credentials.Put(addr.Hex(), password)
+	fmt.Printf("Password stored for %s\n", addr.Hex())
+	return nil
+}
+
+func signerDeletePassword(ctx *cli.Context) error {
+	addr := signerAddress(ctx)
+	stores := openSignerStorages(ctx)
+
+	stores.credentials.Del(addr.Hex())
+	fmt.Printf("Password removed for %s\n", addr.Hex())
+	return nil
+}
+
+func signerAttest(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		Fatalf("ruleset hash must be given as the only argument")
+	}
+	hash := strings.ToLower(strings.TrimPrefix(ctx.Args().First(), "0x"))
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+		Fatalf("Invalid ruleset hash, want 32 bytes of hex")
+	}
+	stores := openSignerStorages(ctx)
+	stores.config.Put(signerRulesetHash, hash)
+	fmt.Printf("Ruleset %s attested\n", hash)
+	return nil
+}
+
+func signerRun(ctx *cli.Context) error {
+	var (
+		dir    = signerConfigDir(ctx)
+		stores *signerStorages
+	)
+	if _, err := os.Stat(filepath.Join(dir, signerSeedFile)); err == nil {
+		stores = openSignerStorages(ctx)
+	} else {
+		log.Warn("No master seed, running without credential storage and ruleset", "init", "ixiosSpark signer init")
+	}
+	// Assemble the UI, wrapped by the ruleset if one is configured
+	var ui core.UIClientAPI = core.NewCommandlineUI()
+	if ctx.Bool(signerStdioUIFlag.Name) {
+		ui = core.NewStdIOUI()
+	}
+	if ctx.IsSet(signerRulesFlag.Name) {
+		if stores == nil {
+			Fatalf("A ruleset requires a master seed, create one with 'ixiosSpark signer init'")
+		}
+		ruleset, err := os.ReadFile(ctx.Path(signerRulesFlag.Name))
+		if err != nil {
+			Fatalf("Failed to read the ruleset: %v", err)
+		}
+		hash := sha256.Sum256(ruleset)
+		if attested, err := stores.config.Get(signerRulesetHash); err != nil || attested != hex.EncodeToString(hash[:]) {
+			Fatalf("Ruleset %x is not attested, attest it with 'ixiosSpark signer attest'", hash)
+		}
+		engine, err := rules.NewRuleEvaluator(ui, stores.rules)
+		if err != nil {
+			Fatalf("Failed to create the rule engine: %v", err)
+		}
+		if err := engine.Init(string(ruleset)); err != nil {
+			Fatalf("Failed to load the ruleset: %v", err)
+		}
+		ui = engine
+		log.Info("Loaded ruleset", "sha256", hex.EncodeToString(hash[:]))
+	}
+	credentials := storage.Storage(&storage.NoStorage{})
+	if stores != nil {
+		credentials = stores.credentials
+	}
+	// Assemble the signer over the keystore, never over an external signer
+	cfg := loadBaseConfig(ctx)
+	keydir, isEphemeral, err := cfg.Node.GetKeyStoreDir()
+	if err != nil {
+		Fatalf("Failed to get the keystore directory: %v", err)
+	}
+	if isEphemeral {
+		Fatalf("Can't use ephemeral directory as keystore path")
+	}
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if cfg.Node.UseLightweightKDF {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	am := accounts.NewManager(&accounts.Config{}, keystore.NewKeyStore(keydir, scryptN, scryptP))
+	defer am.Close()
+
+	validator, err := fourbyte.NewWithFile(filepath.Join(dir, "4byte.json"))
+	if err != nil {
+		Fatalf("Failed to load the 4byte database: %v", err)
+	}
+	signer := core.NewSignerAPI(am, int64(ctx.Uint64(signerChainIDFlag.Name)), true, ui, validator, ctx.Bool(signerAdvancedFlag.Name), credentials)
+	ui.RegisterUIServer(core.NewUIServerAPI(signer))
+
+	auditPath := filepath.Join(dir, "audit.log")
+	if ctx.IsSet(signerAuditLogFlag.Name) {
+		auditPath = ctx.Path(signerAuditLogFlag.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(auditPath), 0700); err != nil {
+		Fatalf("Failed to create the audit log directory: %v", err)
+	}
+	api, err := core.NewAuditLogger(auditPath, signer)
+	if err != nil {
+		Fatalf("Failed to open the audit log: %v", err)
+	}
+	apis := []rpc.API{{Namespace: "account", Service: api}}
+
+	info := map[string]interface{}{
+		"intapi_version": core.InternalAPIVersion,
+		"extapi_version": core.ExternalAPIVersion,
+	}
+	if ctx.Bool(signerHTTPFlag.Name) {
+		srv := rpc.NewServer()
+		if err := srv.RegisterName("account", api); err != nil {
+			Fatalf("Failed to register the account API: %v", err)
+		}
+		handler := node.NewHTTPHandlerStack(srv, nil, SplitAndTrim(ctx.String(signerHTTPVHostsFlag.Name)), nil)
+		endpoint := fmt.Sprintf("%s:%d", ctx.String(signerHTTPAddrFlag.Name), ctx.Int(signerHTTPPortFlag.Name))
+		httpServer, addr, err := node.StartHTTPEndpoint(endpoint, rpc.DefaultHTTPTimeouts, handler)
+		if err != nil {
+			Fatalf("Failed to start the HTTP server: %v", err)
+		}
+		defer httpServer.Close()
+		defer srv.Stop()
+
+		url := "http://" + addr.String()
+		log.Info("HTTP endpoint opened", "url", url)
+		info["extapi_http"] = url
+	}
+	if !ctx.Bool(signerIPCDisabledFlag.Name) {
+		endpoint := filepath.Join(dir, signerIPCFile)
+		if ctx.IsSet(signerIPCPathFlag.Name) {
+			endpoint = ctx.Path(signerIPCPathFlag.Name)
+		}
+		listener, srv, err := rpc.StartIPCEndpoint(endpoint, apis)
+		if err != nil {
+			Fatalf("Failed to start the IPC server: %v", err)
+		}
+		defer listener.Close()
+		defer srv.Stop()
+
+		log.Info("IPC endpoint opened", "url", endpoint)
+		info["extapi_ipc"] = endpoint
+	}
+	ui.OnSignerStartup(core.StartupInfo{Info: info})
+
+	abort := make(chan os.Signal, 1)
+	signal.Notify(abort, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-abort
+	log.Info("Exiting signer", "signal", sig)
+	return nil
+}
@@ -0,0 +1,465 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ixios-io/ixiosSpark/accounts/keystore"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
+	"github.com/ixios-io/ixiosSpark/params"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	validatorCommand = &cli.Command{
+		Name:  "validator",
+		Usage: "Manage validator keys",
+		Description: `
+
+Manage the keys of the mainnet validators, list the validators registered in the
+genesis and verify or import an encrypted validator key bundle.
+
+A validator key bundle (validator_secretkeys_*.tar.enc) is an AES-256-CBC
+encrypted tar archive of the secp256k1, Dilithium5 and SPHINCS+ secret keys of a
+validator, as produced by the validator key generation. Bundles are decrypted in
+memory, neither the decrypted keys nor the password ever touch the disk.`,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "Print the validators registered in the genesis",
+				Action: validatorList,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					KeyStoreDirFlag,
+				},
+				Description: `
+    ixiosSpark validator list
+
+Print the validators registered in the mainnet genesis along with whether their
+key is present in the local keystore.`,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify an encrypted validator key bundle",
+				Action:    validatorVerify,
+				ArgsUsage: "[<bundle>]",
+				Flags: []cli.Flag{
+					PasswordFileFlag,
+				},
+				Description: `
+    ixiosSpark validator verify [<bundle>]
+
+Decrypts the given validator key bundle and checks each of its keys against the
+MKS public keys registered for the validator in the genesis, without importing
+anything.
+
+If no bundle is given, validator_secretkeys_*.tar.enc is looked up in the current
+directory, the home directory and /tmp/ixios-secure-keygen, in that order.
+
+You are prompted for the password of the bundle. For non-interactive use the
+password can be specified with the --password flag.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import an encrypted validator key bundle into the keystore",
+				Action:    validatorImport,
+				ArgsUsage: "[<bundle>]",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					KeyStoreDirFlag,
+					PasswordFileFlag,
+				},
+				Description: `
+    ixiosSpark validator import [<bundle>]
+
+Decrypts the given validator key bundle, checks each of its keys against the MKS
+public keys registered for the validator in the genesis and imports the
+secp256k1 and Dilithium5 keys into the keystore. SPHINCS+ keys are verified but
+not imported, the keystore does not support the scheme.
+
+If no bundle is given, validator_secretkeys_*.tar.enc is looked up in the current
+directory, the home directory and /tmp/ixios-secure-keygen, in that order.
+
+You are prompted for the password of the bundle and for the password to lock the
+imported accounts with. For non-interactive use the passwords can be specified
+with the --password flag, the first line holding the bundle password and the
+second the account password. If the file holds a single line, it is used for
+both.`,
+			},
+		},
+	}
+)
+
+const (
+	validatorSecp256k1KeyFile = "secp256k1_secret.key"
+	validatorDilithiumKeyFile = "dilithium5_secret.key"
+	validatorSphincsKeyFile   = "sphincs_secret.key"
+
+	sphincsPublicKeySize = params.MKSSize - params.MKSSphincsOffset // SPHINCS+-256 public key, PK.seed || PK.root
+	sphincsSecretKeySize = 128
+)
+
+var (
+	errBundleFormat   = errors.New("not an encrypted validator key bundle")
+	errBundlePassword = errors.New("decryption failed, wrong password")
+)
+
+// validatorKeys is a set of secret keys of a single validator, as found in a
+// decrypted key bundle.
+type validatorKeys struct {
+	dir       string
+	secp256k1 *ecdsa.PrivateKey
+	dilithium *dilithium.PrivateKey
+	sphincs   []byte
+}
+
+// zero wipes the secret keys from memory.
+func (k *validatorKeys) zero() {
+	if k.secp256k1 != nil {
+		b := k.secp256k1.D.Bits()
+		clear(b)
+		k.secp256k1 = nil
+	}
+	k.dilithium = nil
+	clear(k.sphincs)
+}
+
+// verify checks the keys against the MKS public keys registered for the
+// validator in the genesis, returning the validator they belong to.
+func (k *validatorKeys) verify(validators []core.ValidatorMKS) (common.Address, error) {
+	if k.secp256k1 == nil {
+		return common.Address{}, fmt.Errorf("missing %s", validatorSecp256k1KeyFile)
+	}
+	addr := crypto.PubkeyToAddress(k.secp256k1.PublicKey)
+
+	var registered *core.ValidatorMKS
+	for i := range validators {
+		if validators[i].Address == addr {
+			registered = &validators[i]
+			break
+		}
+	}
+	if registered == nil {
+		return addr, fmt.Errorf("%x is not a registered validator", addr)
+	}
+	if k.dilithium == nil {
+		return addr, fmt.Errorf("missing %s", validatorDilithiumKeyFile)
+	}
+	if !bytes.Equal(k.dilithium.Public().Bytes(), registered.MKSData[params.MKSDilithiumOffset:params.MKSSphincsOffset]) {
+		return addr, errors.New("dilithium5 key does not match the registered MKS public key")
+	}
+	if k.sphincs == nil {
+		return addr, fmt.Errorf("missing %s", validatorSphincsKeyFile)
+	}
+	if len(k.sphincs) != sphincsSecretKeySize {
+		return addr, fmt.Errorf("invalid sphincs+ key length %d, want %d", len(k.sphincs), sphincsSecretKeySize)
+	}
+	if !bytes.Equal(k.sphincs[sphincsSecretKeySize-sphincsPublicKeySize:], registered.MKSData[params.MKSSphincsOffset:params.MKSSize]) {
+		return addr, errors.New("sphincs+ key does not match the registered MKS public key")
+	}
+	return addr, nil
+}
+
+func validatorList(ctx *cli.Context) error {
+	validators, err := core.EmbeddedValidators()
+	if err != nil {
+		Fatalf("Failed to load the genesis validators: %v", err)
+	}
+	ks := validatorKeyStore(ctx)
+	for i, v := range validators {
+		status := "not in keystore"
+		if ks.HasAddress(v.Address) {
+			status = "in keystore"
+		}
+		fmt.Printf("Validator #%d: {%x} %s\n", i, v.Address, status)
+	}
+	return nil
+}
+
+func validatorVerify(ctx *cli.Context) error {
+	bundle := validatorBundlePath(ctx)
+	password := getPassPhraseWithList("Please give the password of the validator key bundle.", false, 0, MakePasswordList(ctx))
+
+	sets, validators := openValidatorBundle(bundle, password)
+	defer zeroValidatorKeys(sets)
+
+	var failed int
+	for _, keys := range sets {
+		addr, err := keys.verify(validators)
+		if err != nil {
+			fmt.Printf("Validator {%x}: %v (%s)\n", addr, err, keys.dir)
+			failed++
+			continue
+		}
+		fmt.Printf("Validator {%x}: keys match the registered MKS public keys\n", addr)
+	}
+	if failed > 0 {
+		Fatalf("%d of %d validator key sets failed verification", failed, len(sets))
+	}
+	return nil
+}
+
+func validatorImport(ctx *cli.Context) error {
+	bundle := validatorBundlePath(ctx)
+	passwords := MakePasswordList(ctx)
+	password := getPassPhraseWithList("Please give the password of the validator key bundle.", false, 0, passwords)
+
+	sets, validators := openValidatorBundle(bundle, password)
+	defer zeroValidatorKeys(sets)
+
+	// Verify every key set before touching the keystore, so that a broken
+	// bundle is not imported partially.
+	for _, keys := range sets {
+		if addr, err := keys.verify(validators); err != nil {
+			Fatalf("Validator {%x}: %v (%s)", addr, err, keys.dir)
+		}
+	}
+	ks := validatorKeyStore(ctx)
+	passphrase := getPassPhraseWithList("Your validator accounts are locked with a password. Please give a password. Do not forget this password.", true, 1, passwords)
+
+	for _, keys := range sets {
+		acct, err := ks.ImportECDSA(keys.secp256k1, passphrase)
+		switch {
+		case errors.Is(err, keystore.ErrAccountAlreadyExists):
+			fmt.Printf("Validator: {%x} (already in keystore)\n", acct.Address)
+		case err != nil:
+			Fatalf("Could not import the validator key: %v", err)
+		default:
+			fmt.Printf("Validator: {%x}\n", acct.Address)
+		}
+		acct, err = ks.ImportSchemeKey(keystore.SchemeDilithium5, keys.dilithium.Bytes(), passphrase)
+		switch {
+		case errors.Is(err, keystore.ErrAccountAlreadyExists):
+			fmt.Printf("Dilithium5: {%x} (already in keystore)\n", acct.Address)
+		case err != nil:
+			Fatalf("Could not import the dilithium5 key: %v", err)
+		default:
+			fmt.Printf("Dilithium5: {%x}\n", acct.Address)
+		}
+	}
+	return nil
+}
+
+// validatorKeyStore opens the keystore defined by the CLI flags.
+func validatorKeyStore(ctx *cli.Context) *keystore.KeyStore {
+	backends := makeAccountManager(ctx).Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		Fatalf("Keystore is not available")
+	}
+	return backends[0].(*keystore.KeyStore)
+}
+
+// validatorBundlePath returns the bundle given on the command line, or looks it
+// up in the locations the validator key generation leaves it in.
+func validatorBundlePath(ctx *cli.Context) string {
+	if ctx.Args().Len() > 1 {
+		Fatalf("At most one validator key bundle may be given")
+	}
+	if ctx.Args().Len() == 1 {
+		return ctx.Args().First()
+	}
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+	dirs = append(dirs, filepath.Join(os.TempDir(), "ixios-secure-keygen"))
+
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "validator_secretkeys_*.tar.enc"))
+		if len(matches) > 0 {
+			sort.Strings(matches)
+			return matches[0]
+		}
+	}
+	Fatalf("No validator key bundle given and none found in the expected locations")
+	return ""
+}
+
+// openValidatorBundle decrypts a validator key bundle and loads the keys in it,
+// along with the validators registered in the genesis to check them against.
+func openValidatorBundle(file, password string) ([]*validatorKeys, []core.ValidatorMKS) {
+	validators, err := core.EmbeddedValidators()
+	if err != nil {
+		Fatalf("Failed to load the genesis validators: %v", err)
+	}
+	enc, err := os.ReadFile(file)
+	if err != nil {
+		Fatalf("Failed to read the validator key bundle: %v", err)
+	}
+	archive, err := decryptValidatorBundle(enc, password)
+	if err != nil {
+		Fatalf("Failed to decrypt %s: %v", file, err)
+	}
+	defer clear(archive)
+
+	sets, err := readValidatorKeys(archive)
+	if err != nil {
+		Fatalf("Failed to read %s: %v", file, err)
+	}
+	if len(sets) == 0 {
+		Fatalf("No validator keys found in %s", file)
+	}
+	return sets, validators
+}
+
+func zeroValidatorKeys(sets []*validatorKeys) {
+	for _, keys := range sets {
+		keys.zero()
+	}
+}
+
+// decryptValidatorBundle decrypts a bundle in the format of
+// `openssl enc -aes256 -salt -pbkdf2 -iter 100000`, that is "Salted__", an
+// 8 byte salt and the AES-256-CBC ciphertext, with the key and IV derived from
+// the password with PBKDF2-HMAC-SHA256.
+func decryptValidatorBundle(enc []byte, password string) ([]byte, error) {
+	const (
+		magic     = "Salted__"
+		saltLen   = 8
+		iterCount = 100000
+		keyLen    = 32
+	)
+	if len(enc) < len(magic)+saltLen+aes.BlockSize || string(enc[:len(magic)]) != magic {
+		return nil, errBundleFormat
+	}
+	salt, ciphertext := enc[len(magic):len(magic)+saltLen], enc[len(magic)+saltLen:]
+	if len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errBundleFormat
+	}
+	derived := pbkdf2.Key([]byte(password), salt, iterCount, keyLen+aes.BlockSize, sha256.New)
+	defer clear(derived)
+
+	block, err := aes.NewCipher(derived[:keyLen])
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, derived[keyLen:]).CryptBlocks(plain, ciphertext)
+
+	// A wrong password shows as broken padding in all but rare cases, in which
+	// reading the archive fails instead.
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > aes.BlockSize {
+		clear(plain)
+		return nil, errBundlePassword
+	}
+	for _, b := range plain[len(plain)-pad:] {
+		if int(b) != pad {
+			clear(plain)
+			return nil, errBundlePassword
+		}
+	}
+	return plain[:len(plain)-pad], nil
+}
+
+// readValidatorKeys loads the validator keys from a decrypted bundle. Keys in
+// the same directory of the archive belong to the same validator.
+func readValidatorKeys(archive []byte) ([]*validatorKeys, error) {
+	var (
+		sets   = make(map[string]*validatorKeys)
+		reader = tar.NewReader(bytes.NewReader(archive))
+	)
+	for {
+		hdr, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dir, name := path.Split(path.Clean(hdr.Name))
+		switch name {
+		case validatorSecp256k1KeyFile, validatorDilithiumKeyFile, validatorSphincsKeyFile:
+		default:
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		key := decodeKeyFile(data)
+		clear(data)
+
+		keys := sets[dir]
+		if keys == nil {
+			keys = &validatorKeys{dir: dir}
+			sets[dir] = keys
+		}
+		switch name {
+		case validatorSecp256k1KeyFile:
+			keys.secp256k1, err = crypto.ToECDSA(key)
+			clear(key)
+		case validatorDilithiumKeyFile:
+			keys.dilithium, err = decodeDilithiumKey(key)
+			clear(key)
+		case validatorSphincsKeyFile:
+			keys.sphincs = key
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", hdr.Name, err)
+		}
+	}
+	dirs := make([]string, 0, len(sets))
+	for dir := range sets {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	result := make([]*validatorKeys, 0, len(dirs))
+	for _, dir := range dirs {
+		result = append(result, sets[dir])
+	}
+	return result, nil
+}
+
+// decodeKeyFile decodes a key file holding either a hex encoded key, with or
+// without 0x prefix, or the raw key bytes.
+func decodeKeyFile(data []byte) []byte {
+	text := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(strings.TrimPrefix(text, "0x")); err == nil {
+		return key
+	}
+	return common.CopyBytes(data)
+}
+
+// decodeDilithiumKey decodes a Dilithium5 secret key, either packed or as the
+// seed it is derived from.
+func decodeDilithiumKey(b []byte) (*dilithium.PrivateKey, error) {
+	if len(b) == dilithium.SeedSize {
+		return dilithium.NewKeyFromSeed(dilithium.Dilithium5, b)
+	}
+	return dilithium.UnmarshalPrivateKey(dilithium.Dilithium5, b)
+}
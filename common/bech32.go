@@ -0,0 +1,245 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Ixios addresses have a checksummed text form, encoded with bech32m (BIP-350):
+//
+//	ixs1 <scheme> <payload> <checksum>
+//
+// The scheme is the last byte of the signature scheme prefix (the first five
+// bytes of which are zero), encoded as a single character, so that the scheme
+// of an address is visible at a glance:
+//
+//	ixs1q... ECDSA       ixs1z... Dilithium2   ixs1r... Dilithium3
+//	ixs19... Dilithium5  ixs1x... Falcon512
+//
+// The payload holds the 26 bytes of the address following the prefix.
+
+// AddressHRP is the human-readable part of Ixios addresses in text form.
+const AddressHRP = "ixs"
+
+const (
+	addressPrefixLength = 6 // Length of the signature scheme prefix, see params.SignaturePrefixLength
+
+	bech32Charset    = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32mConst     = 0x2bc830a3 // Checksum constant of bech32m
+	bech32ChecksumLn = 6          // Number of characters of the checksum
+	bech32MaxLength  = 90         // Maximum length of a bech32 string
+)
+
+var (
+	errBech32Length   = errors.New("invalid bech32 string length")
+	errBech32Case     = errors.New("mixed case bech32 string")
+	errBech32Char     = errors.New("invalid bech32 character")
+	errBech32Checksum = errors.New("invalid bech32m checksum")
+	errBech32Padding  = errors.New("invalid bech32 padding")
+
+	errAddressHRP     = fmt.Errorf("invalid address prefix, want %q", AddressHRP)
+	errAddressScheme  = errors.New("invalid address scheme")
+	errAddressPayload = errors.New("invalid address payload length")
+	errAddressNoText  = errors.New("address has no text form")
+)
+
+// Bech32 returns the checksummed text form of the address. Only addresses with
+// a well-formed signature scheme prefix have a text form.
+func (a Address) Bech32() (string, error) {
+	for _, b := range a[:addressPrefixLength-1] {
+		if b != 0 {
+			return "", errAddressNoText
+		}
+	}
+	scheme := a[addressPrefixLength-1]
+	if scheme >= 32 {
+		return "", errAddressNoText
+	}
+	payload, _ := convertBits(a[addressPrefixLength:], 8, 5, true)
+	return bech32Encode(AddressHRP, append([]byte{scheme}, payload...)), nil
+}
+
+// Bech32ToAddress parses an address in its checksummed text form.
+func Bech32ToAddress(s string) (Address, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return Address{}, err
+	}
+	if hrp != AddressHRP {
+		return Address{}, errAddressHRP
+	}
+	if len(data) == 0 {
+		return Address{}, errAddressScheme
+	}
+	payload, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return Address{}, err
+	}
+	if len(payload) != AddressLength-addressPrefixLength {
+		return Address{}, errAddressPayload
+	}
+	var a Address
+	a[addressPrefixLength-1] = data[0]
+	copy(a[addressPrefixLength:], payload)
+	return a, nil
+}
+
+// IsBech32Address verifies whether a string is a valid Ixios address in its
+// checksummed text form.
+func IsBech32Address(s string) bool {
+	_, err := Bech32ToAddress(s)
+	return err == nil
+}
+
+// hasBech32Prefix reports whether s looks like an address in text form, as
+// opposed to hex.
+func hasBech32Prefix(s string) bool {
+	return len(s) > len(AddressHRP) && strings.EqualFold(s[:len(AddressHRP)+1], AddressHRP+"1")
+}
+
+// ParseAddress parses an address in either its checksummed text form or hex.
+// Unlike HexToAddress, the hex form must be exactly 32 bytes long and, if it is
+// mixed case, carry a valid checksum.
+func ParseAddress(s string) (Address, error) {
+	if hasBech32Prefix(s) {
+		return Bech32ToAddress(s)
+	}
+	if !IsHexAddress(s) {
+		return Address{}, errors.New("invalid address")
+	}
+	a := HexToAddress(s)
+	if has0xPrefix(s) {
+		s = s[2:]
+	}
+	if s != strings.ToLower(s) && s != strings.ToUpper(s) && s != a.Hex()[2:] {
+		return Address{}, errors.New("invalid address checksum")
+	}
+	return a, nil
+}
+
+// bech32Polymod computes the BCH checksum over the given 5 bit values.
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for checksum computation.
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Encode encodes 5 bit values with a bech32m checksum.
+func bech32Encode(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumLn)...)
+	mod := bech32Polymod(values) ^ bech32mConst
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(data) + bech32ChecksumLn)
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range data {
+		b.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < bech32ChecksumLn; i++ {
+		b.WriteByte(bech32Charset[(mod>>(5*(5-i)))&31])
+	}
+	return b.String()
+}
+
+// bech32Decode decodes a bech32m string, verifying its checksum and returning
+// the lowercase human-readable part and the 5 bit values of the data part.
+func bech32Decode(s string) (string, []byte, error) {
+	if len(s) > bech32MaxLength {
+		return "", nil, errBech32Length
+	}
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return "", nil, errBech32Case
+	}
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+bech32ChecksumLn+1 > len(lower) {
+		return "", nil, errBech32Length
+	}
+	hrp := lower[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, errBech32Char
+		}
+	}
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(bech32Charset, lower[i])
+		if v < 0 {
+			return "", nil, errBech32Char
+		}
+		data = append(data, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != bech32mConst {
+		return "", nil, errBech32Checksum
+	}
+	return hrp, data[:len(data)-bech32ChecksumLn], nil
+}
+
+// convertBits regroups a sequence of from bit values into to bit values. When
+// not padding, leftover bits must be fewer than from and all zero.
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var (
+		acc  uint32
+		bits uint
+		out  = make([]byte, 0, (uint(len(data))*from+to-1)/to)
+		mask = uint32(1)<<to - 1
+		keep = uint32(1)<<(from+to-1) - 1
+	)
+	for _, v := range data {
+		if uint32(v)>>from != 0 {
+			return nil, errBech32Char
+		}
+		acc = (acc<<from | uint32(v)) & keep
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&mask))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&mask))
+		}
+	} else if bits >= from || acc<<(to-bits)&mask != 0 {
+		return nil, errBech32Padding
+	}
+	return out, nil
+}
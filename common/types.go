@@ -324,16 +324,32 @@ func (a Address) MarshalText() ([]byte, error) {
 	return hexutil.Bytes(a[:]).MarshalText()
 }
 
-// UnmarshalText parses a hash in hex syntax.
+// UnmarshalText parses an address in hex syntax or in its text form.
 func (a *Address) UnmarshalText(input []byte) error {
+	if hasBech32Prefix(string(input)) {
+		return a.unmarshalBech32(string(input))
+	}
 	return hexutil.UnmarshalFixedText("Address", input, a[:])
 }
 
-// UnmarshalJSON parses a hash in hex syntax.
+// UnmarshalJSON parses an address in hex syntax or in its text form.
 func (a *Address) UnmarshalJSON(input []byte) error {
+	if isString(input) && hasBech32Prefix(string(input[1:])) {
+		return a.unmarshalBech32(string(input[1 : len(input)-1]))
+	}
 	return hexutil.UnmarshalFixedJSON(addressT, input, a[:])
 }
 
+// unmarshalBech32 parses an address in its text form.
+func (a *Address) unmarshalBech32(input string) error {
+	addr, err := Bech32ToAddress(input)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %v", input, err)
+	}
+	*a = addr
+	return nil
+}
+
 // Scan implements Scanner for database/sql.
 func (a *Address) Scan(src interface{}) error {
 	srcB, ok := src.([]byte)
@@ -394,6 +410,13 @@ func NewMixedcaseAddress(addr Address) MixedcaseAddress {
 
 // NewMixedcaseAddressFromString is mainly meant for unit-testing
 func NewMixedcaseAddressFromString(hexaddr string) (*MixedcaseAddress, error) {
+	if hasBech32Prefix(hexaddr) {
+		a, err := Bech32ToAddress(hexaddr)
+		if err != nil {
+			return nil, err
+		}
+		return &MixedcaseAddress{addr: a, original: hexaddr}, nil
+	}
 	if !IsHexAddress(hexaddr) {
 		return nil, errors.New("invalid address")
 	}
@@ -403,7 +426,7 @@ func NewMixedcaseAddressFromString(hexaddr string) (*MixedcaseAddress, error) {
 
 // UnmarshalJSON parses MixedcaseAddress
 func (ma *MixedcaseAddress) UnmarshalJSON(input []byte) error {
-	if err := hexutil.UnmarshalFixedJSON(addressT, input, ma.addr[:]); err != nil {
+	if err := ma.addr.UnmarshalJSON(input); err != nil {
 		return err
 	}
 	return json.Unmarshal(input, &ma.original)
@@ -411,6 +434,9 @@ func (ma *MixedcaseAddress) UnmarshalJSON(input []byte) error {
 
 // MarshalJSON marshals the original value
 func (ma MixedcaseAddress) MarshalJSON() ([]byte, error) {
+	if hasBech32Prefix(ma.original) {
+		return json.Marshal(ma.original)
+	}
 	if strings.HasPrefix(ma.original, "0x") || strings.HasPrefix(ma.original, "0X") {
 		return json.Marshal(fmt.Sprintf("0x%s", ma.original[2:]))
 	}
@@ -430,8 +456,12 @@ func (ma *MixedcaseAddress) String() string {
 	return fmt.Sprintf("%s [chksum INVALID]", ma.original)
 }
 
-// ValidChecksum returns true if the address has valid checksum
+// ValidChecksum returns true if the address has valid checksum. Addresses in
+// text form only parse with a valid checksum.
 func (ma *MixedcaseAddress) ValidChecksum() bool {
+	if hasBech32Prefix(ma.original) {
+		return true
+	}
 	return ma.original == ma.addr.Hex()
 }
 
@@ -115,3 +115,13 @@ type Engine interface {
 	// Close terminates any background threads maintained by the consensus engine.
 	Close() error
 }
+
+// CheckpointEngine is a consensus engine whose checkpoint headers carry enough
+// state to bootstrap header verification without the history preceding them.
+type CheckpointEngine interface {
+	Engine
+
+	// SeedCheckpoint validates a trusted checkpoint header and initialises the
+	// engine state from it, so its descendants can be verified.
+	SeedCheckpoint(header *types.Header) error
+}
@@ -16,10 +16,12 @@ package fastClique
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/consensus"
+	"github.com/ixios-io/ixiosSpark/core/rawdb"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/rlp"
 	"github.com/ixios-io/ixiosSpark/rpc"
@@ -103,12 +105,23 @@ func (api *API) Proposals() map[common.Address]bool {
 }
 
 // Propose injects a new authorization proposal that the signer will attempt to
-// push through.
-func (api *API) Propose(address common.Address, auth bool) {
+// push through. Past the MKS fork, authorizing an account requires its MKS
+// public keys (Dilithium5 followed by SPHINCS+), which are registered for the
+// account once the vote passes.
+func (api *API) Propose(address common.Address, auth bool, mks *hexutil.Bytes) error {
+	if mks != nil && len(*mks) != extraMKS {
+		return fmt.Errorf("invalid MKS public keys length: have %d, want %d", len(*mks), extraMKS)
+	}
 	api.clique.lock.Lock()
 	defer api.clique.lock.Unlock()
 
 	api.clique.proposals[address] = auth
+	if auth && mks != nil {
+		api.clique.proposalMKS[address] = common.CopyBytes(*mks)
+	} else {
+		delete(api.clique.proposalMKS, address)
+	}
+	return nil
 }
 
 // Discard drops a currently running proposal, stopping the signer from casting
@@ -118,6 +131,202 @@ func (api *API) Discard(address common.Address) {
 	defer api.clique.lock.Unlock()
 
 	delete(api.clique.proposals, address)
+	delete(api.clique.proposalMKS, address)
+}
+
+// validatorKeys is the post-quantum key material registered for a signer.
+type validatorKeys struct {
+	MKS        hexutil.Bytes `json:"mks"`        // Raw MKS public keys as carried in the extra-data
+	Dilithium5 hexutil.Bytes `json:"dilithium5"` // Dilithium5 public key
+	Sphincs    hexutil.Bytes `json:"sphincs"`    // SPHINCS+ public key
+}
+
+// GetValidatorKeys retrieves the MKS public keys of the authorized signers at
+// the specified block. Signers without registered keys map to null.
+func (api *API) GetValidatorKeys(number *rpc.BlockNumber) (map[common.Address]*validatorKeys, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	// Ensure we have an actually valid block and return the keys from its snapshot
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.clique.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.validatorKeys(), nil
+}
+
+// GetValidatorKeysAtHash retrieves the MKS public keys of the authorized signers
+// at the specified block.
+func (api *API) GetValidatorKeysAtHash(hash common.Hash) (map[common.Address]*validatorKeys, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.clique.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.validatorKeys(), nil
+}
+
+// ProposeKeyGroup injects a key group declaration that the signer will make for
+// itself past the key group fork, backing its validator identity by a threshold
+// of the given member keys from the next block on. A zero threshold with no
+// members drops the key group of the signer.
+func (api *API) ProposeKeyGroup(threshold int, members []common.Address) error {
+	var group *KeyGroup
+	if threshold != 0 || len(members) != 0 {
+		group = &KeyGroup{Threshold: threshold, Members: members}
+		if err := group.validate(); err != nil {
+			return fmt.Errorf("%w: want 1 to %d distinct secp256k1 members and a threshold of 1 to their count", err, maxKeyGroupSize)
+		}
+	}
+	api.clique.lock.Lock()
+	defer api.clique.lock.Unlock()
+
+	api.clique.groupProposal, api.clique.groupProposed = group, true
+	return nil
+}
+
+// DiscardKeyGroup drops the pending key group declaration of the signer.
+func (api *API) DiscardKeyGroup() {
+	api.clique.lock.Lock()
+	defer api.clique.lock.Unlock()
+
+	api.clique.groupProposal, api.clique.groupProposed = nil, false
+}
+
+// GetKeyGroups retrieves the key groups backing the authorized signers at the
+// specified block. Signers sealing with their own key are omitted.
+func (api *API) GetKeyGroups(number *rpc.BlockNumber) (map[common.Address]*KeyGroup, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	// Ensure we have an actually valid block and return the groups from its snapshot
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.clique.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Groups, nil
+}
+
+// rewards is the breakdown of the value credited for sealing a block.
+type rewards struct {
+	Validator       common.Address  `json:"validator"`          // Sealer of the block
+	ValidatorReward *hexutil.Big    `json:"validatorReward"`    // Block reward credited to the sealer
+	Treasury        *common.Address `json:"treasury,omitempty"` // Recipient of the treasury share
+	TreasuryReward  *hexutil.Big    `json:"treasuryReward"`     // Block reward credited to the treasury
+	Fees            *hexutil.Big    `json:"fees,omitempty"`     // Transaction fees credited to the sealer, if receipts are available
+}
+
+// GetRewards retrieves the block reward and transaction fees credited for
+// sealing the specified block.
+func (api *API) GetRewards(number *rpc.BlockNumber) (*rewards, error) {
+	// Retrieve the requested block number (or current if none requested)
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	signer, err := api.clique.Author(header)
+	if err != nil {
+		return nil, err
+	}
+	validator, treasury := blockRewards(api.clique.config, header.Number)
+	result := &rewards{
+		Validator:       signer,
+		ValidatorReward: (*hexutil.Big)(validator),
+		TreasuryReward:  (*hexutil.Big)(treasury),
+	}
+	if treasury.Sign() > 0 {
+		result.Treasury = api.clique.config.Treasury
+	}
+	receipts := rawdb.ReadReceipts(api.clique.db, header.Hash(), header.Number.Uint64(), header.Time, api.chain.Config())
+	if receipts != nil || header.TxHash == types.EmptyTxsHash {
+		fees := new(big.Int)
+		for _, receipt := range receipts {
+			fees.Add(fees, new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice))
+		}
+		result.Fees = (*hexutil.Big)(fees)
+	}
+	return result, nil
+}
+
+// GetValidatorStats retrieves the number of blocks each validator sealed in-turn
+// and out-of-turn, and the number of in-turn slots it missed, over the blocks
+// from and to inclusive. Every signer authorized at the end of the range is
+// reported, even if it sealed nothing. If to is omitted, the range ends at the
+// current head.
+func (api *API) GetValidatorStats(from rpc.BlockNumber, to *rpc.BlockNumber) (map[common.Address]ValidatorStats, error) {
+	// Retrieve the last block of the range (or current if none requested)
+	var last *types.Header
+	if to == nil || *to == rpc.LatestBlockNumber {
+		last = api.chain.CurrentHeader()
+	} else {
+		last = api.chain.GetHeaderByNumber(uint64(to.Int64()))
+	}
+	if last == nil {
+		return nil, errUnknownBlock
+	}
+	// The genesis block is not sealed, so the range starts at block 1 at the earliest
+	start := uint64(1)
+	if from == rpc.LatestBlockNumber {
+		start = last.Number.Uint64()
+	} else if from > 0 {
+		start = uint64(from.Int64())
+	}
+	if start > last.Number.Uint64() {
+		return nil, fmt.Errorf("invalid range: from %d is past to %d", start, last.Number.Uint64())
+	}
+	first := api.chain.GetHeaderByNumber(start - 1)
+	if first == nil {
+		return nil, errUnknownBlock
+	}
+	// Compute the sealing record over the range from the snapshots around it
+	end, err := api.clique.snapshot(api.chain, last.Number.Uint64(), last.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	begin, err := api.clique.snapshot(api.chain, first.Number.Uint64(), first.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if begin.StatsSince != end.StatsSince {
+		return nil, fmt.Errorf("validator stats not available before block %d", end.StatsSince+1)
+	}
+	stats := make(map[common.Address]ValidatorStats)
+	for signer := range end.Signers {
+		stats[signer] = ValidatorStats{}
+	}
+	for signer, s := range end.Stats {
+		prev := begin.Stats[signer]
+		s.InTurn -= prev.InTurn
+		s.Missed -= prev.Missed
+		s.OutOfTurn -= prev.OutOfTurn
+
+		if _, ok := stats[signer]; ok || s != (ValidatorStats{}) {
+			stats[signer] = s
+		}
+	}
+	return stats, nil
 }
 
 type status struct {
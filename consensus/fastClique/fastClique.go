@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/holiman/uint256"
 	"github.com/ixios-io/ixiosSpark/accounts"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
@@ -32,6 +33,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/core/state"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/params"
@@ -46,9 +48,10 @@ const (
 	checkpointInterval = 2048                   // Number of blocks after which to save the vote snapshot to the database
 	inmemorySnapshots  = 128                    // Number of recent vote snapshots to keep in memory
 	inmemorySignatures = 4096                   // Number of recent block signatures to keep in memory
-	extraMKS           = 2656                   // Reserve 2690 bytes for Multi-Key-Signature (MKS)
+	extraMKS           = params.MKSSize         // Fixed number of bytes of the MKS public keys of a signer
 	extraVanity        = 32                     // Fixed number of extra-data prefix bytes reserved for signer vanity
 	extraSeal          = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for signer seal
+	extraPQSeal        = 4627                   // Dilithium5 seal preceding the signer seal once MKS is active
 	maxBlocksOOT       = 3                      // Maximum number of blocks a validator can sign out-of-turn
 	ootWaitMinimum     = 3500
 	ootWaitLowerBound  = 6000
@@ -105,6 +108,10 @@ var (
 	// invalid list of signers (i.e. non divisible by 32 bytes).
 	errInvalidCheckpointSigners = errors.New("invalid signer list on checkpoint block")
 
+	// errNotCheckpoint is returned if a header seeded as a trusted checkpoint is
+	// not an epoch transition block.
+	errNotCheckpoint = errors.New("header is not an epoch checkpoint")
+
 	// errMismatchingCheckpointSigners is returned if a checkpoint block contains a
 	// list of signers different than the one the local node calculated.
 	errMismatchingCheckpointSigners = errors.New("mismatching signer list on checkpoint block")
@@ -137,14 +144,71 @@ var (
 	// that already signed a header recently, thus is temporarily not allowed to.
 	errRecentlySigned = errors.New("recently signed")
 
+	// errInvalidMKSSize is returned if a checkpoint block past the MKS fork contains
+	// a signer list that is not made up of whole address and MKS key entries.
 	errInvalidMKSSize = errors.New("extra-data has incorrect MKS region size")
+
+	// errMissingMKSKey is returned if a block past the MKS fork is sealed by a
+	// signer which has no post-quantum public key recorded in the snapshot.
+	errMissingMKSKey = errors.New("signer has no MKS public key")
+
+	// errInvalidMKSSeal is returned if the post-quantum half of a hybrid seal
+	// does not verify against the signer's MKS public key.
+	errInvalidMKSSeal = errors.New("invalid MKS seal")
+
+	// errMKSSignerUnsupported is returned if no signing backend producing both
+	// halves of hybrid seals has been authorized past the MKS fork.
+	errMKSSignerUnsupported = errors.New("signer does not support MKS seals")
 )
 
 // SignerFn hashes and signs the data to be signed by a backing account.
 type SignerFn func(signer accounts.Account, mimeType string, message []byte) ([]byte, error)
 
+// MKSSignerFn hashes and signs the data of a hybrid seal with both the signer
+// account and the Dilithium5 account of its MKS keys, returning the secp256k1
+// and the Dilithium5 signature.
+type MKSSignerFn func(signer, mksSigner accounts.Account, message []byte) (sig, pqsig []byte, err error)
+
+// signerSealSize returns the number of extra-data suffix bytes taken up by the
+// secp256k1 part of the seal of a header: a single signature, or the member
+// signatures of a key group seal.
+func signerSealSize(config *params.CliqueConfig, header *types.Header) int {
+	if size := groupSealSize(config, header); size > 0 {
+		return size
+	}
+	return extraSeal
+}
+
+// sealSize returns the number of extra-data suffix bytes taken up by the seal of
+// a header: the secp256k1 part, preceded by the Dilithium5 signature once the
+// MKS fork is active.
+func sealSize(config *params.CliqueConfig, header *types.Header) int {
+	size := signerSealSize(config, header)
+	if config.IsMKS(header.Number) {
+		size += extraPQSeal
+	}
+	return size
+}
+
+// sealHash returns the hash signed by the block sealer, taking the MKS and key
+// group forks into account.
+func sealHash(config *params.CliqueConfig, header *types.Header) (hash common.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	encodeSigHeader(hasher, header, sealSize(config, header))
+	hasher.(crypto.KeccakState).Read(hash[:])
+	return hash
+}
+
+// sealRLP returns the rlp bytes signed by the block sealer, taking the MKS and
+// key group forks into account.
+func sealRLP(config *params.CliqueConfig, header *types.Header) []byte {
+	b := new(bytes.Buffer)
+	encodeSigHeader(b, header, sealSize(config, header))
+	return b.Bytes()
+}
+
 // ecrecover extracts the Ixios account address from a signed header.
-func ecrecover(header *types.Header, sigcache *sigLRU) (common.Address, error) {
+func ecrecover(header *types.Header, sigcache *sigLRU, config *params.CliqueConfig) (common.Address, error) {
 	// If the signature's already cached, return that
 	hash := header.Hash()
 	if address, known := sigcache.Get(hash); known {
@@ -152,17 +216,42 @@ func ecrecover(header *types.Header, sigcache *sigLRU) (common.Address, error) {
 	}
 
 	// Retrieve the signature from the header extra-data
-	if len(header.Extra) < extraSeal {
+	if len(header.Extra) < sealSize(config, header) {
 		return common.Address{}, errMissingSignature
 	}
+	// Key group seals name the validator they seal for, the member signatures
+	// are checked against its group by verifySeal
+	if size := groupSealSize(config, header); size > 0 {
+		signer := common.BytesToAddress(header.Extra[len(header.Extra)-size : len(header.Extra)-size+common.AddressLength])
+		sigcache.Add(hash, signer)
+		return signer, nil
+	}
 	signature := header.Extra[len(header.Extra)-extraSeal:]
+	sighash := sealHash(config, header)
 
-	// Recover the public key and the Ixios address
-	pubkey, err := crypto.Ecrecover(SealHash(header).Bytes(), signature)
+	signer, err := recoverSigner(sighash, signature)
 	if err != nil {
 		return common.Address{}, err
 	}
 
+	log.Trace("Clique ecrecover details",
+		"block", header.Number,
+		"recovered_signer", signer,
+		"header_hash", header.Hash(),
+		"seal_hash", sighash,
+		"sig_len", len(signature))
+
+	sigcache.Add(hash, signer)
+	return signer, nil
+}
+
+// recoverSigner recovers the Ixios account address of the secp256k1 key that
+// made a seal signature.
+func recoverSigner(sighash common.Hash, signature []byte) (common.Address, error) {
+	pubkey, err := crypto.Ecrecover(sighash.Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
 	var signer common.Address
 	fullHash := crypto.Keccak256(pubkey[1:])
 	copy(signer[:], fullHash)
@@ -171,15 +260,6 @@ func ecrecover(header *types.Header, sigcache *sigLRU) (common.Address, error) {
 	for i := 0; i < 6; i++ {
 		signer[i] = 0
 	}
-
-	log.Trace("Clique ecrecover details",
-		"block", header.Number,
-		"recovered_signer", signer,
-		"header_hash", header.Hash(),
-		"seal_hash", SealHash(header),
-		"sig_len", len(signature))
-
-	sigcache.Add(hash, signer)
 	return signer, nil
 }
 
@@ -190,11 +270,17 @@ type Clique struct {
 	recents    *lru.Cache[common.Hash, *Snapshot] // Snapshots for recent block to speed up reorgs
 	signatures *sigLRU                            // Signatures of recent blocks to speed up mining
 
-	proposals map[common.Address]bool // Current list of proposals we are pushing
+	proposals   map[common.Address]bool   // Current list of proposals we are pushing
+	proposalMKS map[common.Address][]byte // MKS public keys of the accounts proposed for authorization
 
-	signer common.Address // Ixios address of the signing key
-	signFn SignerFn       // Signer function to authorize hashes with
-	lock   sync.RWMutex   // Protects the signer and proposals fields
+	groupProposal *KeyGroup // Key group the signer declares for itself, nil to drop its group
+	groupProposed bool      // Whether the signer has a key group declaration to push
+
+	signer      common.Address // Ixios address of the signing key
+	signFn      SignerFn       // Signer function to authorize hashes with
+	signMKSFn   MKSSignerFn    // Signer function to produce hybrid seals with past the MKS fork
+	signGroupFn GroupSignerFn  // Signer function to seal with the key group backing the signer
+	lock        sync.RWMutex   // Protects the signer and proposals fields
 
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
@@ -213,18 +299,19 @@ func New(config *params.CliqueConfig, db kvdb.Database) *Clique {
 	signatures := lru.NewCache[common.Hash, common.Address](inmemorySignatures)
 
 	return &Clique{
-		config:     &conf,
-		db:         db,
-		recents:    recents,
-		signatures: signatures,
-		proposals:  make(map[common.Address]bool),
+		config:      &conf,
+		db:          db,
+		recents:     recents,
+		signatures:  signatures,
+		proposals:   make(map[common.Address]bool),
+		proposalMKS: make(map[common.Address][]byte),
 	}
 }
 
 // Author implements consensus.Engine, returning the Ixios address recovered
 // from the signature in the header's extra-data section.
 func (c *Clique) Author(header *types.Header) (common.Address, error) {
-	return ecrecover(header, c.signatures)
+	return ecrecover(header, c.signatures, c.config)
 }
 
 // VerifyHeader checks whether a header conforms to the consensus rules.
@@ -284,17 +371,41 @@ func (c *Clique) verifyHeader(chain consensus.ChainHeaderReader, header *types.H
 	if len(header.Extra) < extraVanity {
 		return errMissingVanity
 	}
-	if len(header.Extra) < extraVanity+extraSeal {
+	if len(header.Extra) < extraVanity+sealSize(c.config, header) {
 		return errMissingSignature
 	}
 	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
-	signersBytes := len(header.Extra) - extraVanity - extraSeal
-	if !checkpoint && signersBytes != 0 {
+	signersBytes := len(header.Extra) - extraVanity - sealSize(c.config, header)
+	groupDecl, declared := declaredKeyGroup(c.config, header)
+	mksVote := !checkpoint && !declared && c.config.IsMKS(header.Number) && bytes.Equal(header.Nonce[:], nonceAuthVote)
+	if !checkpoint && signersBytes != 0 && !mksVote && !declared {
 		return errExtraSigners
 	}
+	// Past the key group fork, validators may declare the key group backing them
+	if declared {
+		if _, err := decodeKeyGroup(groupDecl); err != nil {
+			return err
+		}
+	}
+	// Past the MKS fork, authorization votes carry the MKS public keys of the voted account
+	if mksVote && signersBytes != extraMKS {
+		return errInvalidMKSSize
+	}
+	// Past the key group fork, the checkpoint signer list is headed by the key groups
+	if checkpoint && c.config.IsThreshold(header.Number) {
+		_, size, err := decodeCheckpointGroups(header.Extra[extraVanity : extraVanity+signersBytes])
+		if err != nil {
+			return err
+		}
+		signersBytes -= size
+	}
 	if checkpoint && signersBytes%common.AddressLength != 0 {
 		return errInvalidCheckpointSigners
 	}
+	// Past the MKS fork, every checkpoint signer entry carries its MKS public keys
+	if checkpoint && c.config.IsMKS(header.Number) && signersBytes%(common.AddressLength+extraMKS) != 0 {
+		return errInvalidMKSSize
+	}
 	// Ensure that the mix digest is zero as we don't have fork protection currently
 	if header.MixDigest != (common.Hash{}) {
 		return errInvalidMixDigest
@@ -333,14 +444,6 @@ func (c *Clique) verifyHeader(chain consensus.ChainHeaderReader, header *types.H
 		return fmt.Errorf("invalid parentBeaconRoot, have %#x, expected nil", header.ParentBeaconRoot)
 	}
 
-	// Determine how many bytes are left for signers & MKS
-	// The last extraSeal bytes are always the MKS portion.
-	// We'll also subtract our known extraMKS length.
-	//extraSuffix := len(header.Extra) - extraSeal
-	//mksStart := extraSuffix - extraMKS
-	// todo: implement MKS here
-	// todo: errInvalidMKSSize
-
 	// All basic checks passed, verify cascading fields
 	return c.verifyCascadingFields(chain, header, parents)
 }
@@ -386,8 +489,14 @@ func (c *Clique) verifyCascadingFields(chain consensus.ChainHeaderReader, header
 	if err != nil {
 		return err
 	}
-	// If the block is a checkpoint block, verify the signer list
-
+	// If the block is a checkpoint block, verify the signer list, MKS public keys
+	// and key groups against the ones of the parent snapshot
+	if number%c.config.Epoch == 0 {
+		signers := header.Extra[extraVanity : len(header.Extra)-sealSize(c.config, header)]
+		if !bytes.Equal(signers, snap.checkpointSigners(header.Number)) {
+			return errMismatchingCheckpointSigners
+		}
+	}
 	// All basic checks passed, verify the seal and return
 	return c.verifySeal(snap, header, parents)
 }
@@ -422,17 +531,10 @@ func (c *Clique) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 			if checkpoint != nil {
 				hash := checkpoint.Hash()
 
-				// MKS Implementation
-				// Extract signers from the genesis or checkpoint block's extra data
-				// Each signer entry consists of the address (32 bytes) followed by MKS data (2690 bytes)
-				signerEntrySize := common.AddressLength + extraMKS
-				signers := make([]common.Address, (len(checkpoint.Extra)-extraVanity-extraSeal)/signerEntrySize)
-				for i := 0; i < len(signers); i++ {
-					// Extract only the address part, skipping the MKS data for each signer
-					startPos := extraVanity + (i * signerEntrySize)
-					copy(signers[i][:], checkpoint.Extra[startPos:startPos+common.AddressLength])
+				var err error
+				if snap, err = c.checkpointSnapshot(checkpoint); err != nil {
+					return nil, err
 				}
-				snap = newSnapshot(c.config, c.signatures, number, hash, signers)
 				if err := snap.store(c.db); err != nil {
 					return nil, err
 				}
@@ -480,6 +582,79 @@ func (c *Clique) snapshot(chain consensus.ChainHeaderReader, number uint64, hash
 	return snap, err
 }
 
+// checkpointSnapshot creates the voting snapshot at a genesis or checkpoint
+// block from the signer list held in its extra-data.
+func (c *Clique) checkpointSnapshot(checkpoint *types.Header) (*Snapshot, error) {
+	// Extract signers from the genesis or checkpoint block's extra data. Each
+	// signer entry consists of the address (32 bytes), followed by its MKS public
+	// keys (params.MKSSize bytes) in the genesis and past the MKS fork. Past the
+	// key group fork, the key groups of the signers head the list
+	var (
+		start  = extraVanity
+		end    = len(checkpoint.Extra) - sealSize(c.config, checkpoint)
+		groups map[common.Address]*KeyGroup
+	)
+	if end < start {
+		return nil, errMissingSignature
+	}
+	if c.config.IsThreshold(checkpoint.Number) {
+		decoded, size, err := decodeCheckpointGroups(checkpoint.Extra[start:end])
+		if err != nil {
+			return nil, err
+		}
+		groups, start = decoded, start+size
+	}
+	signerEntrySize := common.AddressLength
+	if checkpointMKS(c.config, checkpoint.Number) {
+		signerEntrySize += extraMKS
+	}
+	if (end-start)%signerEntrySize != 0 {
+		return nil, errInvalidCheckpointSigners
+	}
+	signers := make([]common.Address, (end-start)/signerEntrySize)
+	keys := make([][]byte, len(signers))
+	for i := 0; i < len(signers); i++ {
+		startPos := start + (i * signerEntrySize)
+		copy(signers[i][:], checkpoint.Extra[startPos:startPos+common.AddressLength])
+		if signerEntrySize > common.AddressLength {
+			keys[i] = common.CopyBytes(checkpoint.Extra[startPos+common.AddressLength : startPos+signerEntrySize])
+		}
+	}
+	snap := newSnapshot(c.config, c.signatures, checkpoint.Number.Uint64(), checkpoint.Hash(), signers, keys)
+	for validator, group := range groups {
+		snap.Groups[validator] = group
+	}
+	return snap, nil
+}
+
+// SeedCheckpoint implements consensus.CheckpointEngine, storing the voting
+// snapshot of a trusted epoch checkpoint so that its descendants verify without
+// replaying the seals preceding it. The checkpoint must be sealed by one of the
+// signers it lists.
+func (c *Clique) SeedCheckpoint(header *types.Header) error {
+	number := header.Number.Uint64()
+	if number == 0 || number%c.config.Epoch != 0 {
+		return errNotCheckpoint
+	}
+	snap, err := c.checkpointSnapshot(header)
+	if err != nil {
+		return err
+	}
+	if len(snap.Signers) == 0 {
+		return errInvalidCheckpointSigners
+	}
+	if err := c.verifySeal(snap, header, nil); err != nil {
+		return err
+	}
+	if err := snap.store(c.db); err != nil {
+		return err
+	}
+	c.recents.Add(snap.Hash, snap)
+
+	log.Info("Seeded checkpoint snapshot", "number", number, "hash", snap.Hash, "signers", len(snap.Signers))
+	return nil
+}
+
 // VerifyUncles implements consensus.Engine, always returning an error for any
 // uncles as this consensus mechanism doesn't permit uncles.
 func (c *Clique) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
@@ -499,7 +674,7 @@ func (c *Clique) verifySeal(snap *Snapshot, header *types.Header, parents []*typ
 		return errUnknownBlock
 	}
 
-	signer, err := ecrecover(header, c.signatures)
+	signer, err := ecrecover(header, c.signatures, c.config)
 
 	if err != nil {
 		log.Debug("Clique ecrecover failed",
@@ -509,45 +684,81 @@ func (c *Clique) verifySeal(snap *Snapshot, header *types.Header, parents []*typ
 	}
 
 	// Check authorized signers
-	authorized := false
-	for authSigner := range snap.Signers {
-		// Check for zero prefix in authorized signer
-		hasZeroPrefix := true
-		zeroPrefix := make([]byte, 12) // Creates 12 zero bytes
-		if !bytes.Equal(authSigner[:12], zeroPrefix) {
-			hasZeroPrefix = false
+	var (
+		authorized bool
+		validator  common.Address
+	)
+	grouped := groupSealSize(c.config, header) > 0
+	if grouped {
+		// Key group seals name their validator in full
+		if _, ok := snap.Signers[signer]; ok {
+			authorized, validator = true, signer
 		}
+	} else {
+		for authSigner := range snap.Signers {
+			// Check for zero prefix in authorized signer
+			hasZeroPrefix := true
+			zeroPrefix := make([]byte, 12) // Creates 12 zero bytes
+			if !bytes.Equal(authSigner[:12], zeroPrefix) {
+				hasZeroPrefix = false
+			}
 
-		log.Trace("Clique checking signer",
-			"block", number,
-			"auth_signer", authSigner,
-			"has_zero_prefix", hasZeroPrefix,
-			"last_20_equal", bytes.Equal(signer[12:], authSigner[12:]),
-			"full_equal", signer == authSigner)
-
-		// For zero-prefixed authorized signers, compare only the last 20 bytes
-		if hasZeroPrefix && bytes.Equal(signer[12:], authSigner[12:]) {
-			log.Trace("Clique authorized via last 20 bytes",
+			log.Trace("Clique checking signer",
 				"block", number,
-				"signer", signer,
-				"auth_signer", authSigner)
-			authorized = true
-			break
-		}
+				"auth_signer", authSigner,
+				"has_zero_prefix", hasZeroPrefix,
+				"last_20_equal", bytes.Equal(signer[12:], authSigner[12:]),
+				"full_equal", signer == authSigner)
+
+			// For zero-prefixed authorized signers, compare only the last 20 bytes
+			if hasZeroPrefix && bytes.Equal(signer[12:], authSigner[12:]) {
+				log.Trace("Clique authorized via last 20 bytes",
+					"block", number,
+					"signer", signer,
+					"auth_signer", authSigner)
+				authorized, validator = true, authSigner
+				break
+			}
 
-		// For full 32-byte authorized signers, compare everything
-		if !hasZeroPrefix && signer == authSigner {
-			log.Debug("Clique authorized via full match",
-				"block", number,
-				"signer", signer)
-			authorized = true
-			break
+			// For full 32-byte authorized signers, compare everything
+			if !hasZeroPrefix && signer == authSigner {
+				log.Debug("Clique authorized via full match",
+					"block", number,
+					"signer", signer)
+				authorized, validator = true, authSigner
+				break
+			}
 		}
 	}
 
 	if !authorized {
 		return errUnauthorizedSigner
 	}
+	// Validators backed by a key group may only seal with it, and their group
+	// seals must be made by enough of its members
+	if grouped {
+		if err := verifyGroupSeal(c.config, snap, validator, header); err != nil {
+			return err
+		}
+	} else if snap.Groups[validator] != nil {
+		return errInvalidGroupSeal
+	}
+	// Key groups may only be declared by the validator they back
+	if blob, ok := declaredKeyGroup(c.config, header); ok {
+		group, err := decodeKeyGroup(blob)
+		if err != nil {
+			return err
+		}
+		if header.Coinbase != validator || !snap.validKeyGroup(validator, group) {
+			return errInvalidKeyGroup
+		}
+	}
+	// Past the MKS fork, the post-quantum half of the seal must verify too
+	if c.config.IsMKS(header.Number) {
+		if err := verifyMKSSeal(c.config, snap, validator, header); err != nil {
+			return err
+		}
+	}
 
 	// Count recent blocks by each validator
 	recentBlocks := countRecentBlocksByValidator(snap, number)
@@ -573,6 +784,23 @@ func (c *Clique) verifySeal(snap *Snapshot, header *types.Header, parents []*typ
 	return nil
 }
 
+// verifyMKSSeal checks the Dilithium5 signature of a hybrid seal against the MKS
+// public key the snapshot holds for the given validator.
+func verifyMKSSeal(config *params.CliqueConfig, snap *Snapshot, validator common.Address, header *types.Header) error {
+	pub, err := snap.dilithiumKey(validator)
+	if err != nil {
+		return err
+	}
+	end := len(header.Extra) - signerSealSize(config, header)
+	if end < extraPQSeal {
+		return errMissingSignature
+	}
+	if !dilithium.Verify(pub, sealHash(config, header).Bytes(), header.Extra[end-extraPQSeal:end]) {
+		return errInvalidMKSSeal
+	}
+	return nil
+}
+
 // getOutOfTurnootWait returns a random ootWait time between 1500ms and 9500ms for out-of-turn signers.
 func getOutOfTurnootWait() time.Duration {
 	if rand.Int()%3 == 0 {
@@ -630,7 +858,7 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 	}
 
 	c.lock.RLock()
-	signer, signFn := c.signer, c.signFn
+	signer, signFn, signMKSFn, signGroupFn := c.signer, c.signFn, c.signMKSFn, c.signGroupFn
 	c.lock.RUnlock()
 	log.Debug("Begin Sealing block")
 
@@ -737,13 +965,47 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 			return
 		}
 
-		// Sign the block
-		sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, CliqueRLP(header))
-		if err != nil {
-			log.Error("Failed to sign block: signFn failed", "error", err)
-			return
+		// Sign the block, with the key group backing the signer if it has one, or
+		// with both the signer and its Dilithium5 key past the MKS fork
+		if group := newSnap.Groups[signer]; group != nil {
+			if err := sealGroup(c.config, newSnap, header, signer, group, signFn, signGroupFn); err != nil {
+				log.Error("Failed to sign block: key group seal failed", "error", err)
+				return
+			}
+		} else if c.config.IsMKS(header.Number) {
+			if signMKSFn == nil {
+				log.Error("Failed to sign block", "error", errMKSSignerUnsupported)
+				return
+			}
+			pub, err := newSnap.dilithiumKey(signer)
+			if err != nil {
+				log.Error("Failed to sign block: MKS key unavailable", "error", err)
+				return
+			}
+			mksSigner, err := types.QuantumPubkeyToAddress(pub.Bytes())
+			if err != nil {
+				log.Error("Failed to sign block: MKS key unavailable", "error", err)
+				return
+			}
+			sighash, pqsig, err := signMKSFn(accounts.Account{Address: signer}, accounts.Account{Address: mksSigner}, CliqueMKSRLP(header))
+			if err != nil {
+				log.Error("Failed to sign block: MKS signFn failed", "error", err)
+				return
+			}
+			if len(sighash) != extraSeal || len(pqsig) != extraPQSeal {
+				log.Error("Failed to sign block", "error", errMKSSignerUnsupported)
+				return
+			}
+			copy(header.Extra[len(header.Extra)-extraSeal-extraPQSeal:], pqsig)
+			copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
+		} else {
+			sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, CliqueRLP(header))
+			if err != nil {
+				log.Error("Failed to sign block: signFn failed", "error", err)
+				return
+			}
+			copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
 		}
-		copy(header.Extra[len(header.Extra)-extraSeal:], sighash)
 
 		// Check if block arrived during wait (final check)
 		if !inTurn && hasBlockArrived(chain, number) {
@@ -754,7 +1016,6 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 		// Send the sealed block
 		select {
 		case results <- block.WithSeal(header):
-			//estReward := c.EstimateReward(chain, block)
 			if !inTurn {
 				log.Warn("Sealed out of turn, in-turn signer failed to sign", "block", number, "delay", delay)
 			}
@@ -766,21 +1027,6 @@ func (c *Clique) Seal(chain consensus.ChainHeaderReader, block *types.Block, res
 	return nil
 }
 
-func (c *Clique) EstimateReward(chain consensus.ChainHeaderReader, block *types.Block) *big.Int {
-	totalGasFees := new(big.Int)
-	txs := block.Transactions()
-	for _, tx := range txs {
-		// If the transaction has a gas price, approximate fees as (tx.Gas() * tx.GasPrice()).
-		if tx.GasPrice() != nil {
-			gasLimitBig := new(big.Int).SetUint64(tx.Gas())
-			fees := new(big.Int).Mul(gasLimitBig, tx.GasPrice())
-			totalGasFees.Add(totalGasFees, fees)
-		}
-	}
-
-	return totalGasFees
-}
-
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
 // header for running the transactions on top.
 func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -794,10 +1040,17 @@ func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 	if err != nil {
 		return err
 	}
+	mks := c.config.IsMKS(header.Number)
+
 	c.lock.RLock()
+	var voteKeys []byte
 	if number%c.config.Epoch != 0 {
 		addresses := make([]common.Address, 0, len(c.proposals))
 		for address, authorize := range c.proposals {
+			// Past the MKS fork, accounts can only be authorized along with their keys
+			if authorize && mks && len(c.proposalMKS[address]) != extraMKS {
+				continue
+			}
 			if snap.validVote(address, authorize) {
 				addresses = append(addresses, address)
 			}
@@ -806,11 +1059,24 @@ func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 			header.Coinbase = addresses[rand.Intn(len(addresses))]
 			if c.proposals[header.Coinbase] {
 				copy(header.Nonce[:], nonceAuthVote)
+				if mks {
+					voteKeys = c.proposalMKS[header.Coinbase]
+				}
 			} else {
 				copy(header.Nonce[:], nonceDropVote)
 			}
 		}
 	}
+	// Past the key group fork, a pending key group declaration of the signer
+	// takes precedence over votes
+	var groupDecl []byte
+	if number%c.config.Epoch != 0 && c.config.IsThreshold(header.Number) && c.groupProposed {
+		if !c.groupProposal.equal(snap.Groups[c.signer]) && snap.validKeyGroup(c.signer, c.groupProposal) {
+			header.Coinbase, voteKeys = c.signer, nil
+			copy(header.Nonce[:], nonceAuthVote)
+			groupDecl = c.groupProposal.encode()
+		}
+	}
 	signer := c.signer
 	c.lock.RUnlock()
 
@@ -821,12 +1087,22 @@ func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 		header.Extra = append(header.Extra, bytes.Repeat([]byte{0x00}, extraVanity-len(header.Extra))...)
 	}
 	header.Extra = header.Extra[:extraVanity]
+	header.Extra = append(header.Extra, voteKeys...)
+	header.Extra = append(header.Extra, groupDecl...)
 	if number%c.config.Epoch == 0 {
-		for _, s := range snap.signers() {
-			header.Extra = append(header.Extra, s[:]...)
-		}
+		header.Extra = append(header.Extra, snap.checkpointSigners(header.Number)...)
+	}
+	// Reserve the seal, laid out for the key group of the signer if it has one
+	seal := extraSeal
+	if mks {
+		seal += extraPQSeal
+	}
+	if group := snap.Groups[signer]; group != nil {
+		header.Extra = append(header.Extra, make([]byte, seal-extraSeal)...)
+		header.Extra = append(header.Extra, groupSealTemplate(signer, group.Threshold)...)
+	} else {
+		header.Extra = append(header.Extra, make([]byte, seal)...)
 	}
-	header.Extra = append(header.Extra, make([]byte, extraSeal)...)
 
 	// Mix digest is reserved/unused, set to empty
 	header.MixDigest = common.Hash{}
@@ -846,10 +1122,43 @@ func (c *Clique) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 	return nil
 }
 
-// Finalize implements consensus.Engine. There is no post-transaction
-// consensus rules in clique, do nothing here.
+// Finalize implements consensus.Engine, crediting the block reward of the
+// active reward schedule to the sealer of the block and the treasury.
 func (c *Clique) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, withdrawals []*types.Withdrawal) {
-	// No block rewards in PoA, so the state remains as is
+	if c.config.RewardAt(header.Number) == nil {
+		return
+	}
+	signer, err := ecrecover(header, c.signatures, c.config)
+	if err != nil {
+		log.Error("Failed to recover sealer for block reward", "number", header.Number, "err", err)
+		return
+	}
+	accumulateRewards(c.config, state, header, signer)
+}
+
+// blockRewards returns the shares of the block reward minted at block number
+// that go to the sealer and to the treasury.
+func blockRewards(config *params.CliqueConfig, number *big.Int) (*big.Int, *big.Int) {
+	r := config.RewardAt(number)
+	if r == nil {
+		return new(big.Int), new(big.Int)
+	}
+	treasury := new(big.Int).Mul(r.Reward, new(big.Int).SetUint64(r.TreasuryShare))
+	treasury.Div(treasury, big.NewInt(params.CliqueRewardDenominator))
+	return new(big.Int).Sub(r.Reward, treasury), treasury
+}
+
+// accumulateRewards credits the block reward to the sealer and the treasury.
+// Rewards always go to the full 32 byte accounts: the sealer address is derived
+// from its key and legacy 20 byte treasuries are rejected by the config checks.
+func accumulateRewards(config *params.CliqueConfig, state *state.StateDB, header *types.Header, signer common.Address) {
+	validator, treasury := blockRewards(config, header.Number)
+	if validator.Sign() > 0 {
+		state.AddBalance(signer, uint256.MustFromBig(validator))
+	}
+	if treasury.Sign() > 0 {
+		state.AddBalance(*config.Treasury, uint256.MustFromBig(treasury))
+	}
 }
 
 func (c *Clique) FinalizeAndAssemble(
@@ -864,8 +1173,14 @@ func (c *Clique) FinalizeAndAssemble(
 	if len(withdrawals) > 0 {
 		return nil, errors.New("withdrawals are not supported")
 	}
-	// Finalise the block
-	c.Finalize(chain, header, state, txs, ommers, withdrawals)
+	// Finalise the block, the header is not sealed yet so credit the local signer
+	if c.config.RewardAt(header.Number) != nil {
+		c.lock.RLock()
+		signer := c.signer
+		c.lock.RUnlock()
+
+		accumulateRewards(c.config, state, header, signer)
+	}
 
 	// Assign the final state root to the header.
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
@@ -884,6 +1199,36 @@ func (c *Clique) Authorize(signer common.Address, signFn SignerFn) {
 	c.signFn = signFn
 }
 
+// AuthorizeMKS injects the function producing the hybrid seals of the signer
+// into the consensus engine, needed to mint blocks past the MKS fork.
+func (c *Clique) AuthorizeMKS(signMKSFn MKSSignerFn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.signMKSFn = signMKSFn
+}
+
+// AuthorizeGroup injects the function signing with the member keys of the key
+// group backing the signer into the consensus engine, needed to mint blocks
+// once the signer has registered a key group.
+func (c *Clique) AuthorizeGroup(signGroupFn GroupSignerFn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.signGroupFn = signGroupFn
+}
+
+// KeyGroup returns the key group backing the validator at the head of the chain,
+// or nil if it seals with its own key.
+func (c *Clique) KeyGroup(chain consensus.ChainHeaderReader, validator common.Address) (*KeyGroup, error) {
+	head := chain.CurrentHeader()
+	snap, err := c.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return snap.Groups[validator], nil
+}
+
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have:
 // * DIFF_NOTURN(2) if BLOCK_NUMBER % SIGNER_COUNT != SIGNER_INDEX
@@ -908,7 +1253,7 @@ func calcDifficulty(snap *Snapshot, signer common.Address) *big.Int {
 
 // SealHash returns the hash of a block prior to it being sealed.
 func (c *Clique) SealHash(header *types.Header) common.Hash {
-	return SealHash(header)
+	return sealHash(c.config, header)
 }
 
 // Close implements consensus.Engine. It's a noop for clique as there are no background threads.
@@ -928,7 +1273,17 @@ func (c *Clique) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 // SealHash returns the hash of a block prior to it being sealed.
 func SealHash(header *types.Header) (hash common.Hash) {
 	hasher := sha3.NewLegacyKeccak256()
-	encodeSigHeader(hasher, header)
+	encodeSigHeader(hasher, header, extraSeal)
+	hasher.(crypto.KeccakState).Read(hash[:])
+	return hash
+}
+
+// MKSSealHash returns the hash of a block prior to it being sealed with a hybrid
+// Multi-Key-Signature. Both the secp256k1 and the Dilithium5 halves of the seal
+// sign this hash.
+func MKSSealHash(header *types.Header) (hash common.Hash) {
+	hasher := sha3.NewLegacyKeccak256()
+	encodeSigHeader(hasher, header, extraPQSeal+extraSeal)
 	hasher.(crypto.KeccakState).Read(hash[:])
 	return hash
 }
@@ -942,11 +1297,20 @@ func SealHash(header *types.Header) (hash common.Hash) {
 // or not), which could be abused to produce different hashes for the same header.
 func CliqueRLP(header *types.Header) []byte {
 	b := new(bytes.Buffer)
-	encodeSigHeader(b, header)
+	encodeSigHeader(b, header, extraSeal)
+	return b.Bytes()
+}
+
+// CliqueMKSRLP returns the rlp bytes which needs to be signed for a hybrid MKS
+// seal. It is the same as CliqueRLP, except that both the 4627 byte Dilithium5
+// signature and the 65 byte secp256k1 signature are stripped from the extra data.
+func CliqueMKSRLP(header *types.Header) []byte {
+	b := new(bytes.Buffer)
+	encodeSigHeader(b, header, extraPQSeal+extraSeal)
 	return b.Bytes()
 }
 
-func encodeSigHeader(w io.Writer, header *types.Header) {
+func encodeSigHeader(w io.Writer, header *types.Header, sealLen int) {
 	enc := []interface{}{
 		header.ParentHash,
 		header.OmmerHash,
@@ -960,7 +1324,7 @@ func encodeSigHeader(w io.Writer, header *types.Header) {
 		header.GasLimit,
 		header.GasUsed,
 		header.Time,
-		header.Extra[:len(header.Extra)-crypto.SignatureLength], // Yes, this will panic if extra is too short
+		header.Extra[:len(header.Extra)-sealLen], // Yes, this will panic if extra is too short
 		header.MixDigest,
 		header.Nonce,
 	}
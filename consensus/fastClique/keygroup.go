@@ -0,0 +1,365 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package fastClique
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/params"
+	"golang.org/x/exp/slices"
+)
+
+// Key groups let a validator identity be backed by a t-of-n group of secp256k1
+// keys, so that custody of the sealing authority can be distributed over
+// several machines. Past the key group fork, a validator registers its group by
+// sealing a block that credits itself, carries an authorization nonce and holds
+// the group declaration in place of the vote keys:
+//
+//	threshold (1 byte) || member addresses (32 bytes each)
+//
+// A declaration with a zero threshold and no members drops the group. From the
+// next block on, the validator seals with a key group seal in place of the 65
+// byte secp256k1 signature, which its own key no longer satisfies:
+//
+//	validator address (32 bytes) || signatures (65 bytes each) || count (1 byte) || 0xff
+//
+// The member signatures cover the same seal hash as a single key seal would, the
+// header without its seal. Past the MKS fork, the Dilithium5 half of the seal is
+// still made with the MKS key of the validator and precedes the key group seal.
+const (
+	maxKeyGroupSize = 16   // Maximum number of member keys in a key group
+	groupSealMarker = 0xff // Last byte of key group seals, never a valid recovery id of a secp256k1 seal
+)
+
+var (
+	// errInvalidKeyGroup is returned if a key group declaration is malformed, is
+	// not made by the validator it is declared for, or reuses member keys of
+	// another validator.
+	errInvalidKeyGroup = errors.New("invalid key group")
+
+	// errInvalidGroupSeal is returned if a block of a validator backed by a key
+	// group is not sealed by enough distinct members of the group.
+	errInvalidGroupSeal = errors.New("invalid key group seal")
+
+	// errGroupSignerUnsupported is returned if the local node seals for a validator
+	// backed by a key group without a signing backend for the member keys.
+	errGroupSignerUnsupported = errors.New("signer does not support key group seals")
+)
+
+// KeyGroup is a t-of-n group of secp256k1 keys backing a validator identity.
+type KeyGroup struct {
+	Threshold int              `json:"threshold"` // Number of member signatures required to seal a block
+	Members   []common.Address `json:"members"`   // Addresses of the member keys
+}
+
+// GroupSignerFn hashes and signs the data to be signed with the member keys of a
+// key group, returning at least threshold signatures made by distinct members.
+type GroupSignerFn func(members []accounts.Account, threshold int, mimeType string, message []byte) ([][]byte, error)
+
+// contains returns whether the address is a member key of the group.
+func (g *KeyGroup) contains(address common.Address) bool {
+	return slices.Contains(g.Members, address)
+}
+
+// equal returns whether both groups have the same threshold and members. A nil
+// group only equals another nil group.
+func (g *KeyGroup) equal(other *KeyGroup) bool {
+	if g == nil || other == nil {
+		return g == other
+	}
+	return g.Threshold == other.Threshold && slices.Equal(g.Members, other.Members)
+}
+
+// encode returns the extra-data encoding of the group declaration. A nil group
+// encodes the declaration dropping the group.
+func (g *KeyGroup) encode() []byte {
+	if g == nil {
+		return []byte{0}
+	}
+	blob := make([]byte, 1, 1+len(g.Members)*common.AddressLength)
+	blob[0] = byte(g.Threshold)
+	for _, member := range g.Members {
+		blob = append(blob, member[:]...)
+	}
+	return blob
+}
+
+// decodeKeyGroup decodes and sanity checks a key group declaration, returning
+// nil for a declaration dropping the group.
+func decodeKeyGroup(blob []byte) (*KeyGroup, error) {
+	if len(blob) == 0 || (len(blob)-1)%common.AddressLength != 0 {
+		return nil, errInvalidKeyGroup
+	}
+	group := &KeyGroup{Threshold: int(blob[0])}
+	for i := 1; i < len(blob); i += common.AddressLength {
+		group.Members = append(group.Members, common.BytesToAddress(blob[i:i+common.AddressLength]))
+	}
+	if group.Threshold == 0 && len(group.Members) == 0 {
+		return nil, nil
+	}
+	if err := group.validate(); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// validate checks that the group is a meaningful t-of-n group of distinct
+// secp256k1 keys.
+func (g *KeyGroup) validate() error {
+	if len(g.Members) == 0 || len(g.Members) > maxKeyGroupSize {
+		return errInvalidKeyGroup
+	}
+	if g.Threshold < 1 || g.Threshold > len(g.Members) {
+		return errInvalidKeyGroup
+	}
+	seen := make(map[common.Address]struct{}, len(g.Members))
+	for _, member := range g.Members {
+		if !bytes.Equal(types.GetSignatureType(member), types.SigTypeECDSA2) {
+			return errInvalidKeyGroup
+		}
+		if _, ok := seen[member]; ok {
+			return errInvalidKeyGroup
+		}
+		seen[member] = struct{}{}
+	}
+	return nil
+}
+
+// declaredKeyGroup returns the key group declaration carried by a header, if it
+// is one. Declarations are the only non-checkpoint bodies whose length is one
+// byte off a multiple of the address length, which tells them apart from the
+// MKS keys of authorization votes.
+func declaredKeyGroup(config *params.CliqueConfig, header *types.Header) ([]byte, bool) {
+	if !config.IsThreshold(header.Number) || header.Number.Uint64()%config.Epoch == 0 {
+		return nil, false
+	}
+	if !bytes.Equal(header.Nonce[:], nonceAuthVote) {
+		return nil, false
+	}
+	end := len(header.Extra) - sealSize(config, header)
+	if end < extraVanity || (end-extraVanity)%common.AddressLength != 1 {
+		return nil, false
+	}
+	return header.Extra[extraVanity:end], true
+}
+
+// groupSealSize returns the number of extra-data suffix bytes taken up by the
+// key group seal of the header, or 0 if it is sealed by a single key.
+func groupSealSize(config *params.CliqueConfig, header *types.Header) int {
+	if !config.IsThreshold(header.Number) {
+		return 0
+	}
+	extra := header.Extra
+	if len(extra) < extraVanity+common.AddressLength+2 || extra[len(extra)-1] != groupSealMarker {
+		return 0
+	}
+	size := common.AddressLength + int(extra[len(extra)-2])*extraSeal + 2
+	if len(extra) < extraVanity+size {
+		return 0
+	}
+	return size
+}
+
+// groupSealTemplate returns an unsigned key group seal of the validator, with
+// room for the given number of member signatures.
+func groupSealTemplate(validator common.Address, count int) []byte {
+	seal := make([]byte, common.AddressLength+count*extraSeal+2)
+	copy(seal, validator[:])
+	seal[len(seal)-2] = byte(count)
+	seal[len(seal)-1] = groupSealMarker
+	return seal
+}
+
+// verifyGroupSeal checks that the key group seal of a header is made by at least
+// as many distinct members of the validator's key group as its threshold.
+func verifyGroupSeal(config *params.CliqueConfig, snap *Snapshot, validator common.Address, header *types.Header) error {
+	group := snap.Groups[validator]
+	if group == nil {
+		return errInvalidGroupSeal
+	}
+	var (
+		size  = groupSealSize(config, header)
+		seal  = header.Extra[len(header.Extra)-size:]
+		count = int(seal[len(seal)-2])
+	)
+	if count < group.Threshold {
+		return errInvalidGroupSeal
+	}
+	sighash := sealHash(config, header)
+	seen := make(map[common.Address]struct{}, count)
+	for i := 0; i < count; i++ {
+		offset := common.AddressLength + i*extraSeal
+		member, err := recoverSigner(sighash, seal[offset:offset+extraSeal])
+		if err != nil {
+			return err
+		}
+		if _, ok := seen[member]; ok || !group.contains(member) {
+			return errInvalidGroupSeal
+		}
+		seen[member] = struct{}{}
+	}
+	return nil
+}
+
+// sealGroup fills in the key group seal of a header prepared by the validator
+// backed by the group, along with the Dilithium5 half of the seal past the MKS
+// fork, which is still made with the MKS key of the validator.
+func sealGroup(config *params.CliqueConfig, snap *Snapshot, header *types.Header, validator common.Address, group *KeyGroup, signFn SignerFn, signGroupFn GroupSignerFn) error {
+	size := groupSealSize(config, header)
+	if size == 0 || int(header.Extra[len(header.Extra)-2]) != group.Threshold {
+		return errInvalidGroupSeal
+	}
+	if signGroupFn == nil {
+		return errGroupSignerUnsupported
+	}
+	var (
+		sigdata  = sealRLP(config, header)
+		mimeType = accounts.MimetypeClique
+	)
+	if config.IsMKS(header.Number) {
+		mimeType = accounts.MimetypeCliqueMKS
+
+		pub, err := snap.dilithiumKey(validator)
+		if err != nil {
+			return err
+		}
+		mksSigner, err := types.QuantumPubkeyToAddress(pub.Bytes())
+		if err != nil {
+			return err
+		}
+		if signFn == nil {
+			return errMKSSignerUnsupported
+		}
+		pqsig, err := signFn(accounts.Account{Address: mksSigner}, accounts.MimetypeCliqueMKS, sigdata)
+		if err != nil {
+			return err
+		}
+		if len(pqsig) != extraPQSeal {
+			return errMKSSignerUnsupported
+		}
+		copy(header.Extra[len(header.Extra)-size-extraPQSeal:], pqsig)
+	}
+	members := make([]accounts.Account, len(group.Members))
+	for i, member := range group.Members {
+		members[i] = accounts.Account{Address: member}
+	}
+	sigs, err := signGroupFn(members, group.Threshold, mimeType, sigdata)
+	if err != nil {
+		return err
+	}
+	if len(sigs) < group.Threshold {
+		return errInvalidGroupSeal
+	}
+	seal := header.Extra[len(header.Extra)-size:]
+	for i, sig := range sigs[:group.Threshold] {
+		if len(sig) != extraSeal {
+			return errInvalidGroupSeal
+		}
+		copy(seal[common.AddressLength+i*extraSeal:], sig)
+	}
+	return nil
+}
+
+// validKeyGroup returns whether the validator may register the key group. The
+// member keys may neither be authorized signers nor belong to the group of
+// another validator, so that no seal can be claimed by two identities.
+func (s *Snapshot) validKeyGroup(validator common.Address, group *KeyGroup) bool {
+	if group == nil {
+		return true
+	}
+	for _, member := range group.Members {
+		if _, ok := s.Signers[member]; ok {
+			return false
+		}
+		for owner, other := range s.Groups {
+			if owner != validator && other.contains(member) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pruneKeyGroups drops the key groups no longer valid after a change of the
+// signer list: those of deauthorized validators and those with a member key
+// that got authorized as a signer. Groups are checked in ascending validator
+// order so that every node drops the same ones.
+func (s *Snapshot) pruneKeyGroups() {
+	validators := make([]common.Address, 0, len(s.Groups))
+	for validator := range s.Groups {
+		validators = append(validators, validator)
+	}
+	slices.SortFunc(validators, common.Address.Cmp)
+
+	for _, validator := range validators {
+		if _, ok := s.Signers[validator]; !ok || !s.validKeyGroup(validator, s.Groups[validator]) {
+			delete(s.Groups, validator)
+		}
+	}
+}
+
+// checkpointGroups returns the key groups of the snapshot as carried by the
+// checkpoint headers past the key group fork, ahead of the signer list: a count
+// byte, then the validator address, the member count and the declaration of
+// every group, in ascending validator order.
+func (s *Snapshot) checkpointGroups() []byte {
+	validators := make([]common.Address, 0, len(s.Groups))
+	for validator := range s.Groups {
+		validators = append(validators, validator)
+	}
+	slices.SortFunc(validators, common.Address.Cmp)
+
+	blob := []byte{byte(len(validators))}
+	for _, validator := range validators {
+		group := s.Groups[validator]
+		blob = append(blob, validator[:]...)
+		blob = append(blob, byte(len(group.Members)))
+		blob = append(blob, group.encode()...)
+	}
+	return blob
+}
+
+// decodeCheckpointGroups decodes the key groups heading the signer list of a
+// checkpoint header, returning them along with the number of bytes they take.
+func decodeCheckpointGroups(blob []byte) (map[common.Address]*KeyGroup, int, error) {
+	if len(blob) == 0 {
+		return nil, 0, errInvalidCheckpointSigners
+	}
+	groups := make(map[common.Address]*KeyGroup, int(blob[0]))
+	offset := 1
+	for i := 0; i < int(blob[0]); i++ {
+		if len(blob) < offset+common.AddressLength+1 {
+			return nil, 0, errInvalidCheckpointSigners
+		}
+		validator := common.BytesToAddress(blob[offset : offset+common.AddressLength])
+		offset += common.AddressLength
+
+		size := 1 + int(blob[offset])*common.AddressLength
+		offset++
+		if len(blob) < offset+size {
+			return nil, 0, errInvalidCheckpointSigners
+		}
+		group, err := decodeKeyGroup(blob[offset : offset+size])
+		if err != nil || group == nil {
+			return nil, 0, errInvalidKeyGroup
+		}
+		groups[validator] = group
+		offset += size
+	}
+	return groups, offset, nil
+}
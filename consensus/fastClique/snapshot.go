@@ -16,12 +16,15 @@ package fastClique
 import (
 	"bytes"
 	"encoding/json"
+	"math/big"
 	"time"
 
 	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/common/lru"
 	"github.com/ixios-io/ixiosSpark/core/rawdb"
 	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/params"
@@ -40,8 +43,17 @@ type Vote struct {
 // Tally is a simple vote tally to keep the current score of votes. Votes that
 // go against the proposal aren't counted since it's equivalent to not voting.
 type Tally struct {
-	Authorize bool `json:"authorize"` // Whether the vote is about authorizing or kicking someone
-	Votes     int  `json:"votes"`     // Number of votes until now wanting to pass the proposal
+	Authorize bool          `json:"authorize"`     // Whether the vote is about authorizing or kicking someone
+	Votes     int           `json:"votes"`         // Number of votes until now wanting to pass the proposal
+	MKS       hexutil.Bytes `json:"mks,omitempty"` // MKS public keys the authorized account is registered with
+}
+
+// ValidatorStats is the sealing record of a signer, accumulated over every
+// header applied to a snapshot.
+type ValidatorStats struct {
+	InTurn    uint64 `json:"inTurn"`    // Number of blocks sealed in-turn
+	Missed    uint64 `json:"missed"`    // Number of in-turn slots sealed by someone else
+	OutOfTurn uint64 `json:"outOfTurn"` // Number of blocks sealed out-of-turn
 }
 
 type sigLRU = lru.Cache[common.Hash, common.Address]
@@ -51,29 +63,43 @@ type Snapshot struct {
 	config   *params.CliqueConfig // Consensus engine parameters to fine tune behavior
 	sigcache *sigLRU              // Cache of recent block signatures to speed up ecrecover
 
-	Number  uint64                      `json:"number"`  // Block number where the snapshot was created
-	Hash    common.Hash                 `json:"hash"`    // Block hash where the snapshot was created
-	Signers map[common.Address]struct{} `json:"signers"` // Set of authorized signers at this moment
-	Recents map[uint64]common.Address   `json:"recents"` // Set of recent signers for spam protections
-	Votes   []*Vote                     `json:"votes"`   // List of votes cast in chronological order
-	Tally   map[common.Address]Tally    `json:"tally"`   // Current vote tally to avoid recalculating
+	Number  uint64                           `json:"number"`           // Block number where the snapshot was created
+	Hash    common.Hash                      `json:"hash"`             // Block hash where the snapshot was created
+	Signers map[common.Address]struct{}      `json:"signers"`          // Set of authorized signers at this moment
+	MKS     map[common.Address]hexutil.Bytes `json:"mks,omitempty"`    // MKS public keys of the authorized signers
+	Groups  map[common.Address]*KeyGroup     `json:"groups,omitempty"` // Key groups backing the authorized signers that registered one
+	Recents map[uint64]common.Address        `json:"recents"`          // Set of recent signers for spam protections
+	Votes   []*Vote                          `json:"votes"`            // List of votes cast in chronological order
+	Tally   map[common.Address]Tally         `json:"tally"`            // Current vote tally to avoid recalculating
+
+	Stats      map[common.Address]ValidatorStats `json:"stats"`      // Sealing record of every signer since StatsSince
+	StatsSince uint64                            `json:"statsSince"` // Block number after which the sealing record starts
 }
 
 // newSnapshot creates a new snapshot with the specified startup parameters. This
 // method does not initialize the set of recent signers, so only ever use if for
-// the genesis block.
-func newSnapshot(config *params.CliqueConfig, sigcache *sigLRU, number uint64, hash common.Hash, signers []common.Address) *Snapshot {
+// the genesis block. The optional keys hold the MKS public keys of the signers
+// at the same index.
+func newSnapshot(config *params.CliqueConfig, sigcache *sigLRU, number uint64, hash common.Hash, signers []common.Address, keys [][]byte) *Snapshot {
 	snap := &Snapshot{
 		config:   config,
 		sigcache: sigcache,
 		Number:   number,
 		Hash:     hash,
 		Signers:  make(map[common.Address]struct{}),
+		MKS:      make(map[common.Address]hexutil.Bytes),
+		Groups:   make(map[common.Address]*KeyGroup),
 		Recents:  make(map[uint64]common.Address),
 		Tally:    make(map[common.Address]Tally),
+
+		Stats:      make(map[common.Address]ValidatorStats),
+		StatsSince: number,
 	}
-	for _, signer := range signers {
+	for i, signer := range signers {
 		snap.Signers[signer] = struct{}{}
+		if i < len(keys) && len(keys[i]) > 0 {
+			snap.MKS[signer] = keys[i]
+		}
 	}
 	return snap
 }
@@ -90,7 +116,17 @@ func loadSnapshot(config *params.CliqueConfig, sigcache *sigLRU, db kvdb.Databas
 	}
 	snap.config = config
 	snap.sigcache = sigcache
-
+	if snap.MKS == nil {
+		snap.MKS = make(map[common.Address]hexutil.Bytes)
+	}
+	if snap.Groups == nil {
+		snap.Groups = make(map[common.Address]*KeyGroup)
+	}
+	// Snapshots stored before the sealing record existed start it afresh
+	if snap.Stats == nil {
+		snap.Stats = make(map[common.Address]ValidatorStats)
+		snap.StatsSince = snap.Number
+	}
 	return snap, nil
 }
 
@@ -111,19 +147,33 @@ func (s *Snapshot) copy() *Snapshot {
 		Number:   s.Number,
 		Hash:     s.Hash,
 		Signers:  make(map[common.Address]struct{}),
+		MKS:      make(map[common.Address]hexutil.Bytes),
+		Groups:   make(map[common.Address]*KeyGroup),
 		Recents:  make(map[uint64]common.Address),
 		Votes:    make([]*Vote, len(s.Votes)),
 		Tally:    make(map[common.Address]Tally),
+
+		Stats:      make(map[common.Address]ValidatorStats),
+		StatsSince: s.StatsSince,
 	}
 	for signer := range s.Signers {
 		cpy.Signers[signer] = struct{}{}
 	}
+	for signer, key := range s.MKS {
+		cpy.MKS[signer] = key
+	}
+	for signer, group := range s.Groups {
+		cpy.Groups[signer] = group
+	}
 	for block, signer := range s.Recents {
 		cpy.Recents[block] = signer
 	}
 	for address, tally := range s.Tally {
 		cpy.Tally[address] = tally
 	}
+	for signer, stats := range s.Stats {
+		cpy.Stats[signer] = stats
+	}
 	copy(cpy.Votes, s.Votes)
 
 	return cpy
@@ -136,18 +186,22 @@ func (s *Snapshot) validVote(address common.Address, authorize bool) bool {
 	return (signer && !authorize) || (!signer && authorize)
 }
 
-// cast adds a new vote into the tally.
-func (s *Snapshot) cast(address common.Address, authorize bool) bool {
+// cast adds a new vote into the tally. Authorization votes carrying MKS public
+// keys are only counted together with votes proposing the very same keys.
+func (s *Snapshot) cast(address common.Address, authorize bool, mks []byte) bool {
 	// Ensure the vote is meaningful
 	if !s.validVote(address, authorize) {
 		return false
 	}
 	// Cast the vote into an existing or new tally
 	if old, ok := s.Tally[address]; ok {
+		if !bytes.Equal(old.MKS, mks) {
+			return false
+		}
 		old.Votes++
 		s.Tally[address] = old
 	} else {
-		s.Tally[address] = Tally{Authorize: authorize, Votes: 1}
+		s.Tally[address] = Tally{Authorize: authorize, Votes: 1, MKS: common.CopyBytes(mks)}
 	}
 	return true
 }
@@ -208,7 +262,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			delete(snap.Recents, number-limit)
 		}
 		// Resolve the authorization key and check against signers
-		signer, err := ecrecover(header, s.sigcache)
+		signer, err := ecrecover(header, s.sigcache, s.config)
 		if err != nil {
 			return nil, err
 		}
@@ -216,7 +270,24 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			return nil, errUnauthorizedSigner
 		}
 		snap.Recents[number] = signer
+		snap.record(number, signer)
 
+		// Blocks crediting their own signer carry no vote, but may declare the
+		// key group backing the signer from the next block on
+		if header.Coinbase == signer {
+			if blob, ok := declaredKeyGroup(s.config, header); ok {
+				group, err := decodeKeyGroup(blob)
+				if err != nil {
+					return nil, err
+				}
+				if group != nil {
+					snap.Groups[signer] = group
+				} else {
+					delete(snap.Groups, signer)
+				}
+			}
+			continue
+		}
 		// Header authorized, discard any previous votes from the signer
 		for i, vote := range snap.Votes {
 			if vote.Signer == signer && vote.Address == header.Coinbase {
@@ -238,7 +309,8 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		default:
 			return nil, errInvalidVote
 		}
-		if snap.cast(header.Coinbase, authorize) {
+		mks := voteMKS(s.config, header)
+		if snap.cast(header.Coinbase, authorize, mks) {
 			snap.Votes = append(snap.Votes, &Vote{
 				Signer:    signer,
 				Block:     number,
@@ -246,8 +318,50 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				Authorize: authorize,
 			})
 		}
-		// If the vote passed, update the list of signers
-		// todo: implement MKS
+		// If the vote passed, update the list of signers. Signer changes only
+		// take effect past the MKS fork, so that every new signer is known
+		// together with its post-quantum keys.
+		if !s.config.IsMKS(header.Number) {
+			continue
+		}
+		tally := snap.Tally[header.Coinbase]
+		if tally.Votes <= len(snap.Signers)/2 || (tally.Authorize && len(tally.MKS) != extraMKS) {
+			continue
+		}
+		if tally.Authorize {
+			snap.Signers[header.Coinbase] = struct{}{}
+			snap.MKS[header.Coinbase] = tally.MKS
+		} else {
+			delete(snap.Signers, header.Coinbase)
+			delete(snap.MKS, header.Coinbase)
+
+			// Signer list shrunk, delete any leftover recent caches
+			if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
+				delete(snap.Recents, number-limit)
+			}
+			// Discard any previous votes the deauthorized signer cast
+			for i := 0; i < len(snap.Votes); i++ {
+				if snap.Votes[i].Signer == header.Coinbase {
+					// Uncast the vote from the cached tally
+					snap.uncast(snap.Votes[i].Address, snap.Votes[i].Authorize)
+
+					// Uncast the vote from the chronological list
+					snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
+					i--
+				}
+			}
+		}
+		// The signer list changed, drop the key groups it invalidated
+		snap.pruneKeyGroups()
+
+		// Discard any previous votes around the just changed account
+		for i := 0; i < len(snap.Votes); i++ {
+			if snap.Votes[i].Address == header.Coinbase {
+				snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
+				i--
+			}
+		}
+		delete(snap.Tally, header.Coinbase)
 	}
 
 	if time.Since(start) > 8*time.Second {
@@ -259,6 +373,26 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	return snap, nil
 }
 
+// record accounts a block sealed by the given signer in the sealing record. If
+// the signer is out-of-turn, the in-turn signer is charged with a missed slot.
+func (s *Snapshot) record(number uint64, signer common.Address) {
+	signers := s.signers()
+	inturn := signers[number%uint64(len(signers))]
+
+	stats := s.Stats[signer]
+	if signer == inturn {
+		stats.InTurn++
+		s.Stats[signer] = stats
+		return
+	}
+	stats.OutOfTurn++
+	s.Stats[signer] = stats
+
+	missed := s.Stats[inturn]
+	missed.Missed++
+	s.Stats[inturn] = missed
+}
+
 // signers retrieves the list of authorized signers in ascending order.
 func (s *Snapshot) signers() []common.Address {
 	sigs := make([]common.Address, 0, len(s.Signers))
@@ -269,6 +403,84 @@ func (s *Snapshot) signers() []common.Address {
 	return sigs
 }
 
+// voteMKS returns the MKS public keys an authorization vote registers for the
+// voted account, or nil if the header carries none.
+func voteMKS(config *params.CliqueConfig, header *types.Header) []byte {
+	if !bytes.Equal(header.Nonce[:], nonceAuthVote) {
+		return nil
+	}
+	if len(header.Extra) != extraVanity+extraMKS+sealSize(config, header) {
+		return nil
+	}
+	return header.Extra[extraVanity : extraVanity+extraMKS]
+}
+
+// mksKey returns the MKS public keys of a signer in their extra-data encoding,
+// zero filled if the snapshot holds none.
+func (s *Snapshot) mksKey(signer common.Address) []byte {
+	blob := make([]byte, extraMKS)
+	copy(blob, s.MKS[signer])
+	return blob
+}
+
+// checkpointMKS returns whether the signer list of a checkpoint header carries
+// the MKS public keys of the signers. The genesis always carries them.
+func checkpointMKS(config *params.CliqueConfig, number *big.Int) bool {
+	return number.Sign() == 0 || config.IsMKS(number)
+}
+
+// checkpointSigners returns the signer list a checkpoint header at the given
+// number carries in its extra-data: past the key group fork the key groups of
+// the signers, followed by the signer addresses, each with its MKS public keys
+// past the MKS fork.
+func (s *Snapshot) checkpointSigners(number *big.Int) []byte {
+	var blob []byte
+	if s.config.IsThreshold(number) {
+		blob = append(blob, s.checkpointGroups()...)
+	}
+	mks := checkpointMKS(s.config, number)
+	for _, signer := range s.signers() {
+		blob = append(blob, signer[:]...)
+		if mks {
+			blob = append(blob, s.mksKey(signer)...)
+		}
+	}
+	return blob
+}
+
+// validatorKeys returns the MKS public keys of every authorized signer, split up
+// into their individual schemes.
+func (s *Snapshot) validatorKeys() map[common.Address]*validatorKeys {
+	keys := make(map[common.Address]*validatorKeys, len(s.Signers))
+	for signer := range s.Signers {
+		blob := s.MKS[signer]
+		if len(blob) != extraMKS {
+			keys[signer] = nil
+			continue
+		}
+		keys[signer] = &validatorKeys{
+			MKS:        common.CopyBytes(blob),
+			Dilithium5: common.CopyBytes(blob[params.MKSDilithiumOffset:params.MKSSphincsOffset]),
+			Sphincs:    common.CopyBytes(blob[params.MKSSphincsOffset:params.MKSSize]),
+		}
+	}
+	return keys
+}
+
+// dilithiumKey returns the Dilithium5 public key contained in the MKS keys of
+// the given signer.
+func (s *Snapshot) dilithiumKey(signer common.Address) (*dilithium.PublicKey, error) {
+	blob := s.MKS[signer]
+	if len(blob) != extraMKS {
+		return nil, errMissingMKSKey
+	}
+	packed := blob[params.MKSDilithiumOffset:params.MKSSphincsOffset]
+	if bytes.Equal(packed, make([]byte, len(packed))) {
+		return nil, errMissingMKSKey
+	}
+	return dilithium.UnmarshalPublicKey(dilithium.Dilithium5, packed)
+}
+
 // inturn returns if a signer at a given block height is in-turn or not.
 func (s *Snapshot) inturn(number uint64, signer common.Address) bool {
 	signers, offset := s.signers(), 0
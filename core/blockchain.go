@@ -2335,6 +2335,57 @@ func (bc *BlockChain) InsertHeaderChain(chain []*types.Header) (int, error) {
 	return 0, err
 }
 
+// InsertCheckpointHeader anchors an empty chain at a trusted checkpoint header,
+// from which header sync can continue without the history preceding it. The
+// consensus engine seeds its state from the checkpoint.
+//
+// The total difficulty of the checkpoint cannot be derived without the history
+// preceding it, so it is trusted along with the header.
+func (bc *BlockChain) InsertCheckpointHeader(header *types.Header, td *big.Int) error {
+	engine, ok := bc.engine.(consensus.CheckpointEngine)
+	if !ok {
+		return errors.New("consensus engine does not support checkpoint sync")
+	}
+	if !bc.chainmu.TryLock() {
+		return errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	var (
+		hash   = header.Hash()
+		number = header.Number.Uint64()
+	)
+	if head := bc.CurrentHeader(); head.Number.Uint64() != 0 {
+		return fmt.Errorf("checkpoint sync requires an empty chain, head is #%d [%x..]", head.Number, head.Hash().Bytes()[:4])
+	}
+	if number == 0 {
+		return errors.New("checkpoint cannot be the genesis block")
+	}
+	// Every block since genesis adds a difficulty of at least one
+	lowest := new(big.Int).Add(bc.genesisBlock.Difficulty(), new(big.Int).SetUint64(number-1))
+	lowest.Add(lowest, header.Difficulty)
+	if td == nil || td.Cmp(lowest) < 0 {
+		return fmt.Errorf("invalid checkpoint #%d [%x..] total difficulty %v, lowest possible %v", number, hash.Bytes()[:4], td, lowest)
+	}
+	if err := engine.SeedCheckpoint(header); err != nil {
+		return fmt.Errorf("invalid checkpoint #%d [%x..]: %w", number, hash.Bytes()[:4], err)
+	}
+
+	batch := bc.db.NewBatch()
+	rawdb.WriteTd(batch, hash, number, td)
+	rawdb.WriteHeader(batch, header)
+	rawdb.WriteCanonicalHash(batch, hash, number)
+	rawdb.WriteHeadHeaderHash(batch, hash)
+	rawdb.WriteSyncCheckpoint(batch, number)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write checkpoint header", "err", err)
+	}
+	bc.hc.SetCurrentHeader(header)
+
+	log.Info("Anchored chain at sync checkpoint", "number", number, "hash", hash)
+	return nil
+}
+
 // SetBlockValidatorAndProcessorForTesting sets the current validator and processor.
 // This method can be used to force an invalid blockchain to be verified for tests.
 // This method is unsafe and should only be used before block import starts.
@@ -315,6 +315,10 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 				b.header.Difficulty = big.NewInt(0)
 			}
 		}
+		// Start aliasing legacy balances if this is the fork block
+		if config.LegacyAliasBlock != nil && config.LegacyAliasBlock.Cmp(b.header.Number) == 0 {
+			statedb.ActivateLegacyAliasing()
+		}
 
 		// Execute any user modifications to the block
 		if gen != nil {
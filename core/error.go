@@ -98,6 +98,14 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 
+	// ErrKeyRotated is returned if a transaction is signed by the key an account
+	// derives its address from after the account was rebound to another key.
+	ErrKeyRotated = errors.New("sender key has been rotated")
+
+	// ErrKeyNotBound is returned if a rotated key transaction is signed by a key
+	// that is not bound to its sender.
+	ErrKeyNotBound = errors.New("signing key not bound to sender")
+
 	// ErrBlobFeeCapTooLow is returned if the transaction fee cap is less than the
 	// blob gas fee of the block.
 	ErrBlobFeeCapTooLow = errors.New("max fee per blob gas less than block blob gas fee")
@@ -110,7 +110,7 @@ func ReadGenesis(db kvdb.Database) (*Genesis, error) {
 }
 
 // hashAlloc computes the state root according to the genesis specification.
-func hashAlloc(ga *types.GenesisAlloc, isVerkle bool) (common.Hash, error) {
+func hashAlloc(ga *types.GenesisAlloc, isVerkle bool, legacyAlias bool) (common.Hash, error) {
 	// If a genesis-time verkle trie is requested, create a trie config
 	// with the verkle trie enabled so that the tree can be initialized
 	// as such.
@@ -128,6 +128,9 @@ func hashAlloc(ga *types.GenesisAlloc, isVerkle bool) (common.Hash, error) {
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if legacyAlias {
+		statedb.ActivateLegacyAliasing()
+	}
 	for addr, account := range *ga {
 		if account.Balance != nil {
 			statedb.AddBalance(addr, uint256.MustFromBig(account.Balance))
@@ -144,11 +147,14 @@ func hashAlloc(ga *types.GenesisAlloc, isVerkle bool) (common.Hash, error) {
 // flushAlloc is very similar with hash, but the main difference is all the generated
 // states will be persisted into the given database. Also, the genesis state
 // specification will be flushed as well.
-func flushAlloc(ga *types.GenesisAlloc, db kvdb.Database, triedb *triedb.Database, blockhash common.Hash) error {
+func flushAlloc(ga *types.GenesisAlloc, db kvdb.Database, triedb *triedb.Database, blockhash common.Hash, legacyAlias bool) error {
 	statedb, err := state.New(types.EmptyRootHash, state.NewDatabaseWithNodeDB(db, triedb), nil)
 	if err != nil {
 		return err
 	}
+	if legacyAlias {
+		statedb.ActivateLegacyAliasing()
+	}
 	for addr, account := range *ga {
 		if account.Balance != nil {
 			statedb.AddBalance(addr, uint256.MustFromBig(account.Balance))
@@ -285,6 +291,7 @@ func SetupGenesisBlockWithOverride(db kvdb.Database, triedb *triedb.Database, ge
 	// Get the existing chain configuration.
 	newcfg := genesis.configOrDefault(stored)
 	applyOverrides(newcfg)
+	newcfg.SetZetaStartTime(header.Time)
 	if err := newcfg.CheckConfigForkOrder(); err != nil {
 		return newcfg, common.Hash{}, err
 	}
@@ -377,9 +384,15 @@ func (g *Genesis) IsVerkle() bool {
 	return g.Config.IsVerkle(new(big.Int).SetUint64(g.Number), g.Timestamp)
 }
 
+// IsLegacyAlias indicates whether the balances of ECDSA accounts and their 20
+// byte forms are already aliased at genesis time.
+func (g *Genesis) IsLegacyAlias() bool {
+	return g.Config.IsLegacyAlias(new(big.Int).SetUint64(g.Number))
+}
+
 // ToBlock returns the genesis block according to genesis specification.
 func (g *Genesis) ToBlock() *types.Block {
-	root, err := hashAlloc(&g.Alloc, g.IsVerkle())
+	root, err := hashAlloc(&g.Alloc, g.IsVerkle(), g.IsLegacyAlias())
 	if err != nil {
 		panic(err)
 	}
@@ -447,6 +460,7 @@ func (g *Genesis) Commit(db kvdb.Database, triedb *triedb.Database) (*types.Bloc
 	if config == nil {
 		config = params.MainnetChainConfig
 	}
+	config.SetZetaStartTime(g.Timestamp)
 	if err := config.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
@@ -456,7 +470,7 @@ func (g *Genesis) Commit(db kvdb.Database, triedb *triedb.Database) (*types.Bloc
 	// All the checks has passed, flushAlloc the states derived from the genesis
 	// specification as well as the specification itself into the provided
 	// database.
-	if err := flushAlloc(&g.Alloc, db, triedb, block.Hash()); err != nil {
+	if err := flushAlloc(&g.Alloc, db, triedb, block.Hash(), g.IsLegacyAlias()); err != nil {
 		return nil, err
 	}
 	rawdb.WriteTd(db, block.Hash(), block.NumberU64(), block.Difficulty())
@@ -553,7 +567,7 @@ func DefaultGenesisBlock() *Genesis {
 
 // DefaultAetherForgeGenesisBlock returns the AetherForge network genesis block.
 func DefaultAetherForgeGenesisBlock() *Genesis {
-	emptyMKS := make([]byte, 2656) // MKS contains secp256k1, Dilithium5, and SPHINCS256 public keys. Unused on Aether testnets.
+	emptyMKS := make([]byte, params.MKSSize) // MKS contains the Dilithium5 and SPHINCS+-256 public keys. Unused on Aether testnets.
 	v := ValidatorMKS{common.HexToAddress("00000000000045737501bcbd63b65e5c3829e1e6f6a88d5abf577d16b2194054"), emptyMKS}
 	vals := make([]ValidatorMKS, 0, 1)
 	vals = append(vals, v)
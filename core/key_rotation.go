@@ -0,0 +1,158 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/core/vm"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/params"
+)
+
+// The public keys bound to rotated accounts are stored in the key registry
+// account. For every rotated account the registry holds, starting at the slot
+// keyed by the Keccak256 hash of the address:
+//
+//	base:     Keccak256 hash of the bound key
+//	base + 1: length of the bound key
+//	base + 2: the bound key, left aligned in 32 byte words
+//
+// Accounts that were never rotated, or rotated back to the key their address
+// derives from, have no entry.
+
+// keySlot returns the registry slot at the given offset from the entry of addr.
+func keySlot(addr common.Address, offset uint64) common.Hash {
+	base := new(big.Int).SetBytes(crypto.Keccak256(addr[:]))
+	return common.BigToHash(base.Add(base, new(big.Int).SetUint64(offset)))
+}
+
+// BoundKeyHash returns the hash of the public key bound to the account, or the
+// zero hash if the account is controlled by the key its address derives from.
+func BoundKeyHash(db vm.StateDB, addr common.Address) common.Hash {
+	return db.GetState(params.KeyRegistryAddress, keySlot(addr, 0))
+}
+
+// BoundKey returns the public key bound to the account, or nil if the account
+// is controlled by the key its address derives from.
+func BoundKey(db vm.StateDB, addr common.Address) []byte {
+	if BoundKeyHash(db, addr) == (common.Hash{}) {
+		return nil
+	}
+	size := db.GetState(params.KeyRegistryAddress, keySlot(addr, 1)).Big().Uint64()
+	key := make([]byte, 0, size+common.HashLength)
+	for i := uint64(0); uint64(len(key)) < size; i++ {
+		word := db.GetState(params.KeyRegistryAddress, keySlot(addr, 2+i))
+		key = append(key, word[:]...)
+	}
+	return key[:size]
+}
+
+// CheckSenderKey checks that the sender of a transaction is controlled by the
+// key that signed it. Transactions recovering their sender from the signature,
+// whose key is nil, are only valid for accounts that were never rotated; rotated
+// key transactions must carry the key bound to their sender, or the key its
+// address derives from.
+func CheckSenderKey(db vm.StateDB, from common.Address, key []byte) error {
+	bound := BoundKeyHash(db, from)
+	if key == nil {
+		if bound != (common.Hash{}) {
+			return ErrKeyRotated
+		}
+		return nil
+	}
+	if bound == (common.Hash{}) {
+		if addr, err := types.PublicKeyToAddress(key); err != nil || addr != from {
+			return ErrKeyNotBound
+		}
+		return nil
+	}
+	if crypto.Keccak256Hash(key) != bound {
+		return ErrKeyNotBound
+	}
+	return nil
+}
+
+// SenderSignatureCost returns the cost of the signature a transaction sent from
+// the account will carry, for estimating the gas of unsigned transactions.
+// Rotated accounts sign with their bound key, post-quantum accounts with the
+// scheme of their address. Multisig signatures cannot be predicted from the
+// sender alone and are not accounted for.
+func SenderSignatureCost(db vm.StateDB, addr common.Address) types.SignatureCost {
+	if key := BoundKey(db, addr); key != nil {
+		return types.KeySignatureCost(key)
+	}
+	return types.AddressSignatureCost(addr)
+}
+
+// setBoundKey binds the account to the given key, or unbinds it if the key is
+// nil, clearing any words left over from a longer previous key.
+func setBoundKey(db vm.StateDB, addr common.Address, key []byte) {
+	registry := params.KeyRegistryAddress
+
+	// The registry holds no code, keep it from being removed as an empty account
+	if db.GetNonce(registry) == 0 {
+		db.SetNonce(registry, 1)
+	}
+	oldWords := toWordSize(db.GetState(registry, keySlot(addr, 1)).Big().Uint64())
+	newWords := toWordSize(uint64(len(key)))
+	if key == nil {
+		db.SetState(registry, keySlot(addr, 0), common.Hash{})
+	} else {
+		db.SetState(registry, keySlot(addr, 0), crypto.Keccak256Hash(key))
+	}
+	db.SetState(registry, keySlot(addr, 1), common.BigToHash(new(big.Int).SetUint64(uint64(len(key)))))
+	for i := uint64(0); i < newWords; i++ {
+		var word common.Hash
+		copy(word[:], key[i*common.HashLength:])
+		db.SetState(registry, keySlot(addr, 2+i), word)
+	}
+	for i := newWords; i < oldWords; i++ {
+		db.SetState(registry, keySlot(addr, 2+i), common.Hash{})
+	}
+}
+
+// rotateKey executes a transaction to the key registry, rebinding the address
+// of its sender to the public key in the payload. Binding the key the address
+// derives from restores the account to its original key. Like failed EVM calls,
+// failed rotations consume gas but are no consensus errors.
+func (st *StateTransition) rotateKey(value *uint256.Int) ([]byte, uint64, error) {
+	gas := st.gasRemaining
+	if gas < params.KeyRotationGas {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	gas -= params.KeyRotationGas
+
+	if !value.IsZero() {
+		return nil, gas, types.ErrInvalidKeyRotation
+	}
+	key, addr, err := types.VerifyKeyRotation(st.evm.ChainConfig().ChainID, st.msg.From, st.msg.Data)
+	if err != nil {
+		return nil, gas, err
+	}
+	if addr == st.msg.From {
+		key = nil
+	}
+	words := toWordSize(uint64(len(key)))
+	if gas < words*params.KeyRotationWordGas {
+		return nil, 0, vm.ErrOutOfGas
+	}
+	gas -= words * params.KeyRotationWordGas
+
+	setBoundKey(st.state, st.msg.From, key)
+	return nil, gas, nil
+}
@@ -255,6 +255,33 @@ func WriteLastPivotNumber(db kvdb.KeyValueWriter, pivot uint64) {
 	}
 }
 
+// ReadSyncCheckpoint retrieves the number of the trusted checkpoint header the
+// chain was synced from. If the node synced from genesis, it will be nil.
+func ReadSyncCheckpoint(db kvdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(syncCheckpointKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var number uint64
+	if err := rlp.DecodeBytes(data, &number); err != nil {
+		log.Error("Invalid sync checkpoint number in database", "err", err)
+		return nil
+	}
+	return &number
+}
+
+// WriteSyncCheckpoint stores the number of the trusted checkpoint header the
+// chain was synced from.
+func WriteSyncCheckpoint(db kvdb.KeyValueWriter, number uint64) {
+	enc, err := rlp.EncodeToBytes(number)
+	if err != nil {
+		log.Crit("Failed to encode sync checkpoint number", "err", err)
+	}
+	if err := db.Put(syncCheckpointKey, enc); err != nil {
+		log.Crit("Failed to store sync checkpoint number", "err", err)
+	}
+}
+
 // ReadTxIndexTail retrieves the number of oldest indexed block
 // whose transaction indices has been indexed.
 func ReadTxIndexTail(db kvdb.KeyValueReader) *uint64 {
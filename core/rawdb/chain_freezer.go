@@ -111,6 +111,17 @@ func (f *chainFreezer) freeze(db kvdb.KeyValueStore) {
 				return
 			}
 		}
+		// Chains synced from a checkpoint lack the history preceding it, start
+		// freezing past the checkpoint, which stays in the active database
+		// like the genesis block does.
+		if checkpoint := ReadSyncCheckpoint(nfdb); checkpoint != nil && f.frozen.Load() == 0 {
+			if err := f.resetTail(*checkpoint + 1); err != nil {
+				log.Error("Failed to start freezer at sync checkpoint", "number", *checkpoint, "err", err)
+				backoff = true
+				continue
+			}
+			log.Info("Started freezer at sync checkpoint", "number", *checkpoint)
+		}
 		// Retrieve the freezing threshold.
 		hash := ReadHeadBlockHash(nfdb)
 		if hash == (common.Hash{}) {
@@ -311,6 +311,28 @@ func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 	return old, nil
 }
 
+// resetTail moves the tail of an empty freezer to the given item, so that it
+// starts out from it instead of from zero.
+func (f *Freezer) resetTail(tail uint64) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	if f.frozen.Load() != 0 {
+		return errors.New("tail reset of non-empty freezer")
+	}
+	for _, table := range f.tables {
+		if err := table.resetTail(tail); err != nil {
+			return err
+		}
+	}
+	f.frozen.Store(tail)
+	f.tail.Store(tail)
+	return nil
+}
+
 // Sync flushes all data tables to disk.
 func (f *Freezer) Sync() error {
 	var errs []error
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sync"
@@ -578,6 +579,45 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	return nil
 }
 
+// resetTail moves the tail of an empty table to the given item, so that the
+// table starts out from it instead of from zero. The items before the tail are
+// never stored.
+func (t *freezerTable) resetTail(tail uint64) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.items.Load() != 0 {
+		return errors.New("tail reset of non-empty table")
+	}
+	if tail > math.MaxUint32 {
+		return fmt.Errorf("tail %d out of range", tail)
+	}
+	// Commit the virtual tail first, the index then carries the actual one
+	if err := writeMetadata(t.meta, newMetadata(tail)); err != nil {
+		return err
+	}
+	if err := t.meta.Sync(); err != nil {
+		return err
+	}
+	if err := truncateFreezerFile(t.index, 0); err != nil {
+		return err
+	}
+	tailIndex := indexEntry{
+		filenum: t.tailId,
+		offset:  uint32(tail),
+	}
+	if _, err := t.index.Write(tailIndex.append(nil)); err != nil {
+		return err
+	}
+	if err := t.index.Sync(); err != nil {
+		return err
+	}
+	t.itemOffset.Store(tail)
+	t.itemHidden.Store(tail)
+	t.items.Store(tail)
+	return nil
+}
+
 // Close closes all opened files.
 func (t *freezerTable) Close() error {
 	t.lock.Lock()
@@ -45,6 +45,9 @@ var (
 	// lastPivotKey tracks the last pivot block used by fast sync (to reenable on sethead).
 	lastPivotKey = []byte("LastPivot")
 
+	// syncCheckpointKey tracks the trusted checkpoint header the chain was synced from.
+	syncCheckpointKey = []byte("SyncCheckpoint")
+
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
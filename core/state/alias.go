@@ -0,0 +1,97 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/holiman/uint256"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/params"
+)
+
+// ECDSA accounts live at 32 byte addresses made of the zero scheme prefix and
+// the last 26 bytes of the key hash. Funds sent to the 20 byte form of the same
+// key (the last 20 bytes of the address, zero padded) land in a separate legacy
+// account that no key can spend from. The state aliases the two as follows:
+//
+//   - a 20 byte address reports no balance of its own, its funds are included
+//     in the balance of the 32 byte account;
+//   - only balances are aliased, nonces, code and storage always belong to the
+//     exact address.
+//
+// Until the legacy alias fork, the balance of every 32 byte address includes the
+// funds of its last 20 bytes, whatever its scheme, while balance changes only
+// apply to the exact address. From the fork on, only ECDSA accounts include the
+// funds of their 20 byte form, and their first balance change sweeps those funds
+// into them, leaving the canonical account their sole holder.
+//
+// The trie, the snapshots and the proofs store both accounts separately, so any
+// view derived from them reports the legacy part explicitly. The fork is recorded
+// in the state itself by marking the alias account, so that any holder of a state
+// agrees on the rules it was built with.
+
+// LegacyAliasing reports whether the legacy alias fork rules apply to this state.
+func (s *StateDB) LegacyAliasing() bool {
+	return s.GetNonce(params.LegacyAliasAddress) != 0
+}
+
+// ActivateLegacyAliasing switches the state to the legacy alias fork rules. It is
+// applied once, at the start of the legacy alias fork block.
+func (s *StateDB) ActivateLegacyAliasing() {
+	// The marker holds no code, keep it from being removed as an empty account
+	if s.GetNonce(params.LegacyAliasAddress) == 0 {
+		s.SetNonce(params.LegacyAliasAddress, 1)
+	}
+}
+
+// LegacyAccount returns the 20 byte address whose funds are included in the
+// balance of addr, or false if there is none.
+func (s *StateDB) LegacyAccount(addr common.Address) (common.Address, bool) {
+	if s.LegacyAliasing() {
+		return types.LegacyAddress(addr)
+	}
+	return types.TruncatedAddress(addr)
+}
+
+// GetLegacyBalance retrieves the funds held by the 20 byte form of addr, which
+// are included in the balance of addr.
+func (s *StateDB) GetLegacyBalance(addr common.Address) *uint256.Int {
+	legacy, ok := s.LegacyAccount(addr)
+	if !ok {
+		return common.U2560
+	}
+	if obj := s.getStateObject(legacy); obj != nil {
+		return obj.Balance()
+	}
+	return common.U2560
+}
+
+// sweepLegacy moves the funds held by the 20 byte form of addr into addr. It is
+// a no-op before the legacy alias fork.
+func (s *StateDB) sweepLegacy(addr common.Address) {
+	if !s.LegacyAliasing() {
+		return
+	}
+	legacy, ok := types.LegacyAddress(addr)
+	if !ok {
+		return
+	}
+	obj := s.getStateObject(legacy)
+	if obj == nil || obj.Balance().IsZero() {
+		return
+	}
+	amount := new(uint256.Int).Set(obj.Balance())
+	obj.SetBalance(new(uint256.Int))
+	s.getOrNewStateObject(addr).AddBalance(amount)
+}
@@ -47,6 +47,7 @@ type DumpCollector interface {
 // DumpAccount represents an account in the state.
 type DumpAccount struct {
 	Balance     string                 `json:"balance"`
+	Legacy      string                 `json:"legacyBalance,omitempty"` // Funds of the 20 byte form aliased to the account
 	Nonce       uint64                 `json:"nonce"`
 	Root        hexutil.Bytes          `json:"root"`
 	CodeHash    hexutil.Bytes          `json:"codeHash"`
@@ -90,6 +91,7 @@ type iterativeDump struct {
 func (d iterativeDump) OnAccount(addr *common.Address, account DumpAccount) {
 	dumpAccount := &DumpAccount{
 		Balance:     account.Balance,
+		Legacy:      account.Legacy,
 		Nonce:       account.Nonce,
 		Root:        account.Root,
 		CodeHash:    account.CodeHash,
@@ -156,6 +158,10 @@ func (s *StateDB) DumpToCollector(c DumpCollector, conf *DumpConfig) (nextKey []
 			addr = common.BytesToAddress(addrBytes)
 			address = &addr
 			account.Address = address
+
+			if legacy := s.GetLegacyBalance(addr); !legacy.IsZero() {
+				account.Legacy = legacy.String()
+			}
 		}
 		obj := newObject(s, addr, &data)
 		if !conf.SkipCode {
@@ -269,50 +269,29 @@ func (s *StateDB) Exist(addr common.Address) bool {
 }
 
 // Empty returns whether the state object is either non-existent
-// or empty according to the EIP161 specification (balance = nonce = code = 0)
+// or empty according to the EIP161 specification (balance = nonce = code = 0).
+// From the legacy alias fork on, the funds of the 20 byte form of an ECDSA
+// account keep it from being empty.
 func (s *StateDB) Empty(addr common.Address) bool {
 	so := s.getStateObject(addr)
-	return so == nil || so.empty()
-}
-
-func isAddressWithZeroPrefix(addr common.Address) bool {
-	// Check if the first 12 bytes are all zeros
-	for i := 0; i < 12; i++ {
-		if addr[i] != 0 {
-			return false
-		}
+	if !s.LegacyAliasing() {
+		return so == nil || so.empty()
 	}
-	return true
+	return (so == nil || so.empty()) && s.GetLegacyBalance(addr).IsZero()
 }
 
+// GetBalance retrieves the balance from the given address or 0 if object not
+// found. The balance of a 32 byte address includes the funds of its 20 byte
+// form, which reports no balance of its own.
 func (s *StateDB) GetBalance(addr common.Address) *uint256.Int {
-	totalBalance := new(uint256.Int)
-
-	fullStateObject := s.getStateObject(addr)
-
-	// Get balance of queried address
-	if fullStateObject = s.getStateObject(addr); fullStateObject != nil {
-		totalBalance.Add(totalBalance, fullStateObject.Balance())
-	}
-
-	// Create address with first 12 bytes zeroed
-	var zeroAddr common.Address
-	copy(zeroAddr[12:], addr[12:])
-
-	// Get the zero-prefixed balance and add it to the ECDSA26 address
-	if zeroAddr != addr {
-		if stateObj := s.getStateObject(zeroAddr); stateObj != nil {
-			totalBalance.Add(totalBalance, stateObj.Balance()) // Add the ECDSA20 balance
-		}
+	if types.IsLegacyAddress(addr) {
+		return common.U2560
 	}
-
-	if isAddressWithZeroPrefix(addr) {
-		if fullStateObject != nil {
-			totalBalance.Sub(totalBalance, fullStateObject.Balance()) // Subtract the ECDSA26 balance
-		}
+	balance := new(uint256.Int)
+	if stateObject := s.getStateObject(addr); stateObject != nil {
+		balance.Set(stateObject.Balance())
 	}
-
-	return totalBalance
+	return balance.Add(balance, s.GetLegacyBalance(addr))
 }
 
 // GetNonce retrieves the nonce from the given address or 0 if object not found
@@ -401,6 +380,8 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
+	s.sweepLegacy(addr)
+
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -409,13 +390,18 @@ func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
 
 // SubBalance subtracts amount from the account associated with addr.
 func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int) {
+	s.sweepLegacy(addr)
+
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SubBalance(amount)
 	}
 }
 
+// SetBalance sets the balance of the account associated with addr.
 func (s *StateDB) SetBalance(addr common.Address, amount *uint256.Int) {
+	s.sweepLegacy(addr)
+
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetBalance(amount)
@@ -470,6 +456,8 @@ func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common
 // The account's state object is still available until the state is committed,
 // getStateObject will return a non-nil account after SelfDestruct.
 func (s *StateDB) SelfDestruct(addr common.Address) {
+	s.sweepLegacy(addr)
+
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
 		return
@@ -70,6 +70,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
 	)
+	// Start aliasing legacy balances if this is the fork block
+	if p.config.LegacyAliasBlock != nil && p.config.LegacyAliasBlock.Cmp(blockNumber) == 0 {
+		statedb.ActivateLegacyAliasing()
+	}
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
@@ -99,8 +103,8 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 }
 
 func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
-	// Enforce minimum gas price of 1 zeta, which varies by blockNumber:
-	minZeta := zeta.CalculateZetaValue(blockNumber)
+	// Enforce minimum gas price of 1 zeta, which varies by block:
+	minZeta := zeta.Value(config, blockNumber, evm.Context.Time)
 	if msg.GasPrice == nil || msg.GasPrice.Cmp(minZeta) < 0 {
 		return nil, fmt.Errorf(
 			"transaction gas price %s is below the required minimum of 1 zeta (1 zeta = %s wei)",
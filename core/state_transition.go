@@ -64,7 +64,9 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation bool, isHomestead, isEIP2028, isEIP3860 bool) (uint64, error) {
+// Post-quantum, multisig and rotated key transactions are additionally charged
+// for the public keys and signatures they carry and for verifying them.
+func IntrinsicGas(data []byte, accessList types.AccessList, sigCost types.SignatureCost, isContractCreation bool, isHomestead, isEIP2028, isEIP3860 bool) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if isContractCreation && isHomestead {
@@ -110,6 +112,16 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
 		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
 	}
+	if sigCost.Size > 0 {
+		if (math.MaxUint64-gas)/params.TxSignatureByteGas < sigCost.Size {
+			return 0, ErrGasUintOverflow
+		}
+		gas += sigCost.Size * params.TxSignatureByteGas
+	}
+	if math.MaxUint64-gas < sigCost.VerifyGas {
+		return 0, ErrGasUintOverflow
+	}
+	gas += sigCost.VerifyGas
 	return gas, nil
 }
 
@@ -138,6 +150,15 @@ type Message struct {
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
 
+	// SenderKey is the public key a rotated key transaction is signed with, to
+	// be checked against the key bound to the sender. It is nil for transactions
+	// recovering their sender from the signature.
+	SenderKey []byte
+
+	// SignatureCost describes the public keys and signatures the transaction
+	// carries beyond its ECDSA values, charged for by the intrinsic gas.
+	SignatureCost types.SignatureCost
+
 	// When SkipAccountChecks is true, the message nonce is not checked against the
 	// account nonce in state. It also disables checking that the sender is an EOA.
 	// This field will be set to true for operations like RPC eth_call.
@@ -164,6 +185,9 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 	if baseFee != nil {
 		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
 	}
+	msg.SenderKey, _ = tx.RotatedKey()
+	msg.SignatureCost = tx.SignatureCost()
+
 	var err error
 	msg.From, err = types.Sender(s, tx)
 	return msg, err
@@ -290,6 +314,12 @@ func (st *StateTransition) preCheck() error {
 			return fmt.Errorf("%w: address %v, codehash: %s", ErrSenderNoEOA,
 				msg.From.Hex(), codeHash)
 		}
+		// Make sure the transaction is signed by the key controlling the sender
+		if st.evm.ChainConfig().IsKeyRotation(st.evm.Context.BlockNumber) {
+			if err := CheckSenderKey(st.state, msg.From, msg.SenderKey); err != nil {
+				return fmt.Errorf("%w: address %v", err, msg.From.Hex())
+			}
+		}
 	}
 	// Make sure that transaction gasFeeCap is greater than the baseFee (post london)
 	if st.evm.ChainConfig().IsLondon(st.evm.Context.BlockNumber) {
@@ -371,7 +401,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	gas, err := IntrinsicGas(msg.Data, msg.AccessList, msg.SignatureCost, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
 	if err != nil {
 		return nil, err
 	}
@@ -408,7 +438,11 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
-		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, value)
+		if rules.IsKeyRotation && st.to() == params.KeyRegistryAddress {
+			ret, st.gasRemaining, vmerr = st.rotateKey(value)
+		} else {
+			ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, value)
+		}
 	}
 
 	var gasRefund uint64
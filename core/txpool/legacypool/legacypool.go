@@ -292,7 +292,7 @@ func New(config Config, chain BlockChain) *LegacyPool {
 // pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.PostQuantumTxType, types.MultisigTxType, types.RotatedKeyTxType:
 		return true
 	default:
 		return false
@@ -607,8 +607,8 @@ func (pool *LegacyPool) local() map[common.Address]types.Transactions {
 // This check is meant as an early check which only needs to be performed once,
 // and does not require the pool mutex to be held.
 func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) error {
-	currentBlock := pool.currentHead.Load().Number
-	minGasPrice := zeta.CalculateZetaValue(currentBlock)
+	head := pool.currentHead.Load()
+	minGasPrice := zeta.Value(pool.chainconfig, head.Number, head.Time)
 
 	// Compare transaction gas price with minimum required (1 zeta)
 	if tx.GasPrice().Cmp(minGasPrice) < 0 {
@@ -621,7 +621,10 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType,
+			1<<types.DynamicFeeTxType |
+			1<<types.PostQuantumTxType |
+			1<<types.MultisigTxType |
+			1<<types.RotatedKeyTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
 	}
@@ -724,8 +727,8 @@ func (pool *LegacyPool) add(tx *types.Transaction, local bool) (replaced bool, e
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
-		fmt.Printf("todo: use blockNumber instead of big.NewInt(0)")
-		if !isLocal && pool.priced.Underpriced(tx, big.NewInt(0)) {
+		head := pool.currentHead.Load()
+		if !isLocal && pool.priced.Underpriced(tx, zeta.Value(pool.chainconfig, head.Number, head.Time)) {
 			log.Trace("Discarding underpriced transaction", "hash", hash, "gasTipCap", tx.GasTipCap(), "gasFeeCap", tx.GasFeeCap())
 			return false, txpool.ErrUnderpriced
 		}
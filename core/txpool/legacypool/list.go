@@ -24,7 +24,6 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/core/types"
-	"github.com/ixios-io/ixiosSpark/zeta"
 	"golang.org/x/exp/slices"
 )
 
@@ -575,9 +574,7 @@ func (l *pricedList) Removed(count int) {
 }
 
 // Underpriced checks whether a transaction has a gas price less than zeta
-func (l *pricedList) Underpriced(tx *types.Transaction, blockNumber *big.Int) bool {
-	zetaThreshold := zeta.CalculateZetaValue(blockNumber)
-
+func (l *pricedList) Underpriced(tx *types.Transaction, zetaThreshold *big.Int) bool {
 	// If the transaction's gas price is less than the threshold, transaction is underpriced.
 	if tx.GasPrice().Cmp(zetaThreshold) < 0 {
 		return false
@@ -98,6 +98,17 @@ func encodeForDerive(list DerivableList, i int, buf *bytes.Buffer) []byte {
 	return common.CopyBytes(buf.Bytes())
 }
 
+// InclusionProof proves a transaction or receipt to be part of a block. It holds
+// the Merkle proof of its consensus encoding in the trie the block header commits
+// to in TxHash or ReceiptHash, keyed by its index as in DeriveSha.
+type InclusionProof struct {
+	BlockHash   common.Hash
+	BlockNumber uint64
+	Index       uint64
+	Value       []byte   // Consensus encoding of the transaction or receipt
+	Proof       [][]byte // Trie nodes on the path from the root to the value
+}
+
 // DeriveSha creates the tree hashes of transactions, receipts, and withdrawals in a block header.
 func DeriveSha(list DerivableList, hasher TrieHasher) common.Hash {
 	hasher.Reset()
@@ -15,9 +15,14 @@ package types
 
 import (
 	"bytes"
+	"errors"
 
 	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
+	"github.com/ixios-io/ixiosSpark/crypto/falcon"
 	"github.com/ixios-io/ixiosSpark/params"
+	"github.com/ixios-io/ixiosSpark/rlp"
 )
 
 // Signature scheme identifiers as byte arrays for direct comparison
@@ -28,6 +33,7 @@ var (
 	SigTypeDilith3   = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x03} // Dilithium-3
 	SigTypeDilith5   = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x05} // Dilithium-5
 	SigTypeFalcon512 = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x06} // Falcon512
+	SigTypeMultisig  = []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x07} // Multisig
 )
 
 // GetSignatureType returns the signature type bytes from an address
@@ -43,7 +49,8 @@ func IsValidSignatureType(addr common.Address) bool {
 		bytes.Equal(prefix, SigTypeDilith2) ||
 		bytes.Equal(prefix, SigTypeDilith3) ||
 		bytes.Equal(prefix, SigTypeDilith5) ||
-		bytes.Equal(prefix, SigTypeFalcon512)
+		bytes.Equal(prefix, SigTypeFalcon512) ||
+		bytes.Equal(prefix, SigTypeMultisig)
 }
 
 // IsDilithiumAddress returns true if the address uses any Dilithium signature scheme
@@ -53,3 +60,361 @@ func IsDilithiumAddress(addr common.Address) bool {
 		bytes.Equal(prefix, SigTypeDilith3) ||
 		bytes.Equal(prefix, SigTypeDilith5)
 }
+
+// IsFalconAddress returns true if the address uses the Falcon-512 signature scheme
+func IsFalconAddress(addr common.Address) bool {
+	return bytes.Equal(GetSignatureType(addr), SigTypeFalcon512)
+}
+
+// legacyPrefixLength is the number of leading zero bytes of a legacy address.
+const legacyPrefixLength = common.AddressLength - 20
+
+// IsLegacyAddress returns true if the address is a 20 byte address zero padded
+// to 32 bytes
+func IsLegacyAddress(addr common.Address) bool {
+	for _, b := range addr[:legacyPrefixLength] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TruncatedAddress returns the last 20 bytes of an address zero padded to 32
+// bytes, or false if the address is already in its 20 byte form
+func TruncatedAddress(addr common.Address) (common.Address, bool) {
+	if IsLegacyAddress(addr) {
+		return common.Address{}, false
+	}
+	var legacy common.Address
+	copy(legacy[legacyPrefixLength:], addr[legacyPrefixLength:])
+	return legacy, true
+}
+
+// LegacyAddress returns the 20 byte form of an ECDSA address, or false if the
+// address is not an ECDSA address or already in its 20 byte form
+func LegacyAddress(addr common.Address) (common.Address, bool) {
+	if !bytes.Equal(GetSignatureType(addr), SigTypeECDSA2) {
+		return common.Address{}, false
+	}
+	return TruncatedAddress(addr)
+}
+
+var (
+	// ErrUnknownQuantumScheme is returned if a public key does not belong to any
+	// supported post-quantum signature scheme.
+	ErrUnknownQuantumScheme = errors.New("unknown post-quantum signature scheme")
+
+	// ErrInvalidQuantumSig is returned if a post-quantum signature does not
+	// verify against the supplied public key.
+	ErrInvalidQuantumSig = errors.New("invalid post-quantum signature")
+)
+
+// quantumScheme describes a post-quantum signature scheme that is able to
+// authorise transactions sent from addresses carrying its prefix.
+type quantumScheme struct {
+	prefix        []byte
+	publicKeySize int
+	signatureSize int    // Length of the longest signature of the scheme
+	verifyGas     uint64 // Intrinsic gas charged per signature verification
+	verify        func(pub, msg, sig []byte) bool
+}
+
+// quantumSchemes lists the supported post-quantum schemes. Public key sizes are
+// distinct across schemes, so a key alone identifies its scheme.
+var quantumSchemes = []quantumScheme{
+	newDilithiumScheme(SigTypeDilith2, dilithium.Dilithium2, params.Dilithium2VerifyGas),
+	newDilithiumScheme(SigTypeDilith3, dilithium.Dilithium3, params.Dilithium3VerifyGas),
+	newDilithiumScheme(SigTypeDilith5, dilithium.Dilithium5, params.Dilithium5VerifyGas),
+	{
+		prefix:        SigTypeFalcon512,
+		publicKeySize: falcon.PublicKeySize,
+		signatureSize: falcon.MaxSignatureSize,
+		verifyGas:     params.Falcon512VerifyGas,
+		verify: func(pub, msg, sig []byte) bool {
+			key, err := falcon.UnmarshalPublicKey(pub)
+			if err != nil {
+				return false
+			}
+			return falcon.Verify(key, msg, sig)
+		},
+	},
+}
+
+// newDilithiumScheme wraps a Dilithium parameter set as a quantumScheme.
+func newDilithiumScheme(prefix []byte, p *dilithium.Params, verifyGas uint64) quantumScheme {
+	return quantumScheme{
+		prefix:        prefix,
+		publicKeySize: p.PublicKeySize,
+		signatureSize: p.SignatureSize,
+		verifyGas:     verifyGas,
+		verify: func(pub, msg, sig []byte) bool {
+			key, err := dilithium.UnmarshalPublicKey(p, pub)
+			if err != nil {
+				return false
+			}
+			return dilithium.Verify(key, msg, sig)
+		},
+	}
+}
+
+// quantumSchemeByPublicKey returns the scheme a public key belongs to.
+func quantumSchemeByPublicKey(pub []byte) *quantumScheme {
+	for i := range quantumSchemes {
+		if quantumSchemes[i].publicKeySize == len(pub) {
+			return &quantumSchemes[i]
+		}
+	}
+	return nil
+}
+
+// quantumSchemeByAddress returns the scheme whose prefix an address carries.
+func quantumSchemeByAddress(addr common.Address) *quantumScheme {
+	prefix := GetSignatureType(addr)
+	for i := range quantumSchemes {
+		if bytes.Equal(prefix, quantumSchemes[i].prefix) {
+			return &quantumSchemes[i]
+		}
+	}
+	return nil
+}
+
+// IsQuantumAddress returns true if the address uses any post-quantum signature scheme.
+func IsQuantumAddress(addr common.Address) bool {
+	return quantumSchemeByAddress(addr) != nil
+}
+
+// QuantumPubkeyToAddress derives the address owned by a post-quantum public key.
+// Like ECDSA addresses it holds the trailing 26 bytes of the Keccak256 hash of
+// the key, but the leading 6 bytes carry the scheme prefix instead of zeros.
+func QuantumPubkeyToAddress(pub []byte) (common.Address, error) {
+	scheme := quantumSchemeByPublicKey(pub)
+	if scheme == nil {
+		return common.Address{}, ErrUnknownQuantumScheme
+	}
+	var addr common.Address
+	copy(addr[:], scheme.prefix)
+	copy(addr[params.SignaturePrefixLength:], crypto.Keccak256(pub)[params.SignaturePrefixLength:])
+	return addr, nil
+}
+
+// VerifyQuantumSignature checks that sig is a valid signature of msg made by
+// the post-quantum public key pub, and returns the address owned by the key.
+func VerifyQuantumSignature(pub, msg, sig []byte) (common.Address, error) {
+	scheme := quantumSchemeByPublicKey(pub)
+	if scheme == nil {
+		return common.Address{}, ErrUnknownQuantumScheme
+	}
+	if !scheme.verify(pub, msg, sig) {
+		return common.Address{}, ErrInvalidQuantumSig
+	}
+	return QuantumPubkeyToAddress(pub)
+}
+
+// MaxMultisigKeys is the maximum number of public keys of a multisig account.
+const MaxMultisigKeys = 16
+
+// compressedPubkeyLength is the length of a compressed secp256k1 public key, the
+// form in which multisig accounts and rotated keys hold their ECDSA keys.
+const compressedPubkeyLength = 33
+
+var (
+	// ErrInvalidMultisigKeys is returned if the threshold or public keys of a
+	// multisig account are malformed.
+	ErrInvalidMultisigKeys = errors.New("invalid multisig keys")
+
+	// ErrInvalidMultisigSig is returned if a multisig transaction carries a
+	// signature that does not verify against its public key.
+	ErrInvalidMultisigSig = errors.New("invalid multisig signature")
+
+	// ErrMultisigThreshold is returned if a multisig transaction carries more or
+	// fewer signatures than the threshold of its account.
+	ErrMultisigThreshold = errors.New("multisig threshold not met")
+)
+
+// IsMultisigAddress returns true if the address is a multisig account.
+func IsMultisigAddress(addr common.Address) bool {
+	return bytes.Equal(GetSignatureType(addr), SigTypeMultisig)
+}
+
+// validateMultisigKeys checks that the threshold and public keys describe a
+// multisig account: between 1 and MaxMultisigKeys keys, each either a compressed
+// secp256k1 key or a post-quantum key, in strictly ascending byte order so that
+// every account has exactly one key set, and a threshold no larger than the
+// number of keys.
+func validateMultisigKeys(threshold uint64, pubs [][]byte) error {
+	if len(pubs) == 0 || len(pubs) > MaxMultisigKeys {
+		return ErrInvalidMultisigKeys
+	}
+	if threshold == 0 || threshold > uint64(len(pubs)) {
+		return ErrInvalidMultisigKeys
+	}
+	for i, pub := range pubs {
+		if i > 0 && bytes.Compare(pubs[i-1], pub) >= 0 {
+			return ErrInvalidMultisigKeys
+		}
+		if _, err := PublicKeyToAddress(pub); err != nil {
+			return ErrInvalidMultisigKeys
+		}
+	}
+	return nil
+}
+
+// MultisigPubkeysToAddress derives the address of the multisig account of the
+// given threshold and public keys. It holds the trailing 26 bytes of the
+// Keccak256 hash of the RLP encoded threshold and keys behind the multisig
+// prefix.
+func MultisigPubkeysToAddress(threshold uint64, pubs [][]byte) (common.Address, error) {
+	if err := validateMultisigKeys(threshold, pubs); err != nil {
+		return common.Address{}, err
+	}
+	enc, err := rlp.EncodeToBytes([]interface{}{threshold, pubs})
+	if err != nil {
+		return common.Address{}, err
+	}
+	var addr common.Address
+	copy(addr[:], SigTypeMultisig)
+	copy(addr[params.SignaturePrefixLength:], crypto.Keccak256(enc)[params.SignaturePrefixLength:])
+	return addr, nil
+}
+
+// VerifyMultisigSignatures checks that exactly threshold of the signatures are
+// set and that they are valid signatures of msg made by the public key at the
+// same position, and returns the address of the multisig account. The slots of
+// the keys that did not sign are left empty. Surplus signatures are rejected,
+// otherwise dropping them would yield another valid encoding of the same
+// transaction.
+func VerifyMultisigSignatures(threshold uint64, pubs, sigs [][]byte, msg []byte) (common.Address, error) {
+	addr, err := MultisigPubkeysToAddress(threshold, pubs)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(sigs) != len(pubs) {
+		return common.Address{}, ErrInvalidMultisigSig
+	}
+	var signed uint64
+	for _, sig := range sigs {
+		if len(sig) > 0 {
+			signed++
+		}
+	}
+	if signed != threshold {
+		return common.Address{}, ErrMultisigThreshold
+	}
+	for i, sig := range sigs {
+		if len(sig) > 0 && !verifyKeySignature(pubs[i], msg, sig) {
+			return common.Address{}, ErrInvalidMultisigSig
+		}
+	}
+	return addr, nil
+}
+
+// PublicKeyToAddress derives the address owned by a public key of any supported
+// scheme: a compressed secp256k1 key or a post-quantum key.
+func PublicKeyToAddress(pub []byte) (common.Address, error) {
+	if len(pub) == compressedPubkeyLength {
+		key, err := crypto.DecompressPubkey(pub)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return crypto.PubkeyToAddress(*key), nil
+	}
+	return QuantumPubkeyToAddress(pub)
+}
+
+// verifyKeySignature checks a signature made by a public key of any supported
+// scheme. ECDSA signatures are given in the [R || S || V] format produced by
+// wallets, with V being the recovery id of the key, 0 or 1. The recovery id is
+// checked too, so that a signature has exactly one valid encoding and can not
+// be altered into another valid one.
+func verifyKeySignature(pub, msg, sig []byte) bool {
+	if len(pub) == compressedPubkeyLength {
+		if len(sig) != crypto.SignatureLength || sig[crypto.RecoveryIDOffset] > 1 {
+			return false
+		}
+		if !crypto.VerifySignature(pub, msg, sig[:crypto.RecoveryIDOffset]) {
+			return false
+		}
+		recovered, err := crypto.SigToPub(msg, sig)
+		return err == nil && bytes.Equal(crypto.CompressPubkey(recovered), pub)
+	}
+	scheme := quantumSchemeByPublicKey(pub)
+	return scheme != nil && scheme.verify(pub, msg, sig)
+}
+
+// SignatureCost describes the public keys and signatures a transaction carries
+// in place of its ECDSA values, as charged for by the intrinsic gas.
+type SignatureCost struct {
+	Size      uint64 // Total length of the carried public keys and signatures
+	VerifyGas uint64 // Gas charged for verifying the carried signatures
+}
+
+// add accounts for a public key and its signature. Empty signature slots of
+// multisig transactions are not verified, so only their key is charged.
+func (c *SignatureCost) add(pub, sig []byte) {
+	c.Size += uint64(len(pub) + len(sig))
+	if len(sig) == 0 {
+		return
+	}
+	if len(pub) == compressedPubkeyLength {
+		c.VerifyGas += params.EcrecoverGas
+	} else if scheme := quantumSchemeByPublicKey(pub); scheme != nil {
+		c.VerifyGas += scheme.verifyGas
+	}
+}
+
+// SignatureCost returns the cost of the public keys and signatures carried by
+// the transaction. It is zero for transactions recovering their sender from
+// ECDSA values, which are covered by the base transaction gas.
+func (tx *Transaction) SignatureCost() SignatureCost {
+	var cost SignatureCost
+	switch tx.Type() {
+	case PostQuantumTxType:
+		cost.add(tx.QuantumSignature())
+	case RotatedKeyTxType:
+		cost.add(tx.RotatedKey())
+	case MultisigTxType:
+		_, pubs, sigs := tx.MultisigSignatures()
+		for i, pub := range pubs {
+			var sig []byte
+			if i < len(sigs) {
+				sig = sigs[i]
+			}
+			cost.add(pub, sig)
+		}
+		for _, sig := range sigs[min(len(pubs), len(sigs)):] {
+			cost.Size += uint64(len(sig))
+		}
+	}
+	return cost
+}
+
+// KeySignatureCost returns the cost of a rotated key transaction signed with the
+// given public key, assuming the longest signature of its scheme. It is meant
+// for estimating the gas of transactions that are not signed yet.
+func KeySignatureCost(pub []byte) SignatureCost {
+	cost := SignatureCost{Size: uint64(len(pub))}
+	if len(pub) == compressedPubkeyLength {
+		cost.Size += crypto.SignatureLength
+		cost.VerifyGas = params.EcrecoverGas
+	} else if scheme := quantumSchemeByPublicKey(pub); scheme != nil {
+		cost.Size += uint64(scheme.signatureSize)
+		cost.VerifyGas = scheme.verifyGas
+	}
+	return cost
+}
+
+// AddressSignatureCost returns the cost of a transaction sent from the given
+// address, assuming the longest signature of the scheme the address belongs
+// to. It is zero for ECDSA addresses. It is meant for estimating the gas of
+// transactions that are not signed yet.
+func AddressSignatureCost(addr common.Address) SignatureCost {
+	scheme := quantumSchemeByAddress(addr)
+	if scheme == nil {
+		return SignatureCost{}
+	}
+	return SignatureCost{
+		Size:      uint64(scheme.publicKeySize + scheme.signatureSize),
+		VerifyGas: scheme.verifyGas,
+	}
+}
@@ -0,0 +1,168 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/rlp"
+)
+
+// TestVerifyKeySignatureRecoveryID checks that an ECDSA key signature only
+// verifies with the recovery id of its key, so it can not be re-encoded.
+func TestVerifyKeySignatureRecoveryID(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	pub := crypto.CompressPubkey(&key.PublicKey)
+	msg := crypto.Keccak256([]byte("ixios"))
+
+	sig, err := crypto.Sign(msg, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifyKeySignature(pub, msg, sig) {
+		t.Fatal("valid signature rejected")
+	}
+	for _, v := range []byte{1 - sig[crypto.RecoveryIDOffset], 2, 3, 27, 28} {
+		tampered := common.CopyBytes(sig)
+		tampered[crypto.RecoveryIDOffset] = v
+		if verifyKeySignature(pub, msg, tampered) {
+			t.Errorf("signature with recovery id %d accepted", v)
+		}
+	}
+	if verifyKeySignature(pub, msg, sig[:crypto.RecoveryIDOffset]) {
+		t.Error("signature without recovery id accepted")
+	}
+}
+
+// newMultisigKeys creates n ECDSA keys ordered by their compressed public keys,
+// as a multisig account requires.
+func newMultisigKeys(n int) ([]*ecdsa.PrivateKey, [][]byte) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(crypto.CompressPubkey(&keys[i].PublicKey), crypto.CompressPubkey(&keys[j].PublicKey)) < 0
+	})
+	pubs := make([][]byte, n)
+	for i, key := range keys {
+		pubs[i] = crypto.CompressPubkey(&key.PublicKey)
+	}
+	return keys, pubs
+}
+
+// TestVerifyMultisigSignatures checks that a multisig transaction must carry
+// exactly threshold signatures, so that a relayer can not drop surplus ones to
+// create a different valid encoding of the same transaction.
+func TestVerifyMultisigSignatures(t *testing.T) {
+	keys, pubs := newMultisigKeys(3)
+	msg := crypto.Keccak256([]byte("ixios"))
+	want, err := MultisigPubkeysToAddress(2, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(signers ...int) [][]byte {
+		sigs := make([][]byte, len(pubs))
+		for _, i := range signers {
+			sigs[i], _ = crypto.Sign(msg, keys[i])
+		}
+		return sigs
+	}
+	for _, signers := range [][]int{{0, 1}, {0, 2}, {1, 2}} {
+		addr, err := VerifyMultisigSignatures(2, pubs, sign(signers...), msg)
+		if err != nil {
+			t.Fatalf("signers %v: %v", signers, err)
+		}
+		if addr != want {
+			t.Fatalf("signers %v: address mismatch: have %x, want %x", signers, addr, want)
+		}
+	}
+	for _, signers := range [][]int{{}, {1}, {0, 1, 2}} {
+		if _, err := VerifyMultisigSignatures(2, pubs, sign(signers...), msg); !errors.Is(err, ErrMultisigThreshold) {
+			t.Errorf("signers %v: have error %v, want %v", signers, err, ErrMultisigThreshold)
+		}
+	}
+	sigs := sign(0, 1)
+	sigs[1][crypto.RecoveryIDOffset] ^= 1
+	if _, err := VerifyMultisigSignatures(2, pubs, sigs, msg); !errors.Is(err, ErrInvalidMultisigSig) {
+		t.Errorf("tampered recovery id: have error %v, want %v", err, ErrInvalidMultisigSig)
+	}
+	if _, err := VerifyMultisigSignatures(2, pubs, sign(0, 1)[:2], msg); !errors.Is(err, ErrInvalidMultisigSig) {
+		t.Errorf("missing slot: have error %v, want %v", err, ErrInvalidMultisigSig)
+	}
+}
+
+// TestRotatedKeyRecoveryID checks that rotated key transactions and rotation
+// proofs signed by an ECDSA key are rejected once their recovery id is altered.
+func TestRotatedKeyRecoveryID(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		pub     = crypto.CompressPubkey(&key.PublicKey)
+		chainID = big.NewInt(1)
+		signer  = NewKeyRotationSigner(chainID)
+		from    = common.HexToAddress("0x000000000000aabbccddeeff00112233445566778899aabbccddeeff00112233")
+	)
+	tx := NewTx(&RotatedKeyTx{
+		ChainID:   chainID,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       21000,
+		To:        &from,
+		Value:     big.NewInt(1),
+		From:      from,
+		PublicKey: pub,
+	})
+	h := signer.Hash(tx)
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := crypto.Sign(KeyRotationHash(chainID, from).Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []byte{sig[crypto.RecoveryIDOffset], 1 - sig[crypto.RecoveryIDOffset], 2, 27} {
+		tampered := common.CopyBytes(sig)
+		tampered[crypto.RecoveryIDOffset] = v
+		signed, err := tx.WithRotatedKeySignature(signer, tampered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := Sender(signer, signed)
+		if valid := v == sig[crypto.RecoveryIDOffset]; valid != (err == nil) {
+			t.Errorf("transaction with recovery id %d: have error %v, want valid %v", v, err, valid)
+		} else if valid && sender != from {
+			t.Errorf("sender mismatch: have %x, want %x", sender, from)
+		}
+	}
+	for _, v := range []byte{proof[crypto.RecoveryIDOffset], 1 - proof[crypto.RecoveryIDOffset], 2, 27} {
+		tampered := common.CopyBytes(proof)
+		tampered[crypto.RecoveryIDOffset] = v
+		data, err := rlp.EncodeToBytes(&KeyRotation{PublicKey: pub, Proof: tampered})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, err = VerifyKeyRotation(chainID, from, data)
+		if valid := v == proof[crypto.RecoveryIDOffset]; valid != (err == nil) {
+			t.Errorf("rotation proof with recovery id %d: have error %v, want valid %v", v, err, valid)
+		}
+	}
+}
@@ -46,6 +46,10 @@ const (
 	AccessListTxType = 0x01
 	DynamicFeeTxType = 0x02
 	BlobTxType       = 0x03
+
+	PostQuantumTxType = 0x04
+	MultisigTxType    = 0x05
+	RotatedKeyTxType  = 0x06
 )
 
 // Transaction is an Ixios transaction.
@@ -68,7 +72,8 @@ func NewTx(inner TxData) *Transaction {
 
 // TxData is the underlying data of a transaction.
 //
-// This is implemented by DynamicFeeTx, LegacyTx and AccessListTx.
+// This is implemented by DynamicFeeTx, LegacyTx, AccessListTx, BlobTx, PostQuantumTx,
+// MultisigTx and RotatedKeyTx.
 type TxData interface {
 	txType() byte // returns the type ID
 	copy() TxData // creates a deep copy and initializes all fields
@@ -203,6 +208,12 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(DynamicFeeTx)
 	case BlobTxType:
 		inner = new(BlobTx)
+	case PostQuantumTxType:
+		inner = new(PostQuantumTx)
+	case MultisigTxType:
+		inner = new(MultisigTx)
+	case RotatedKeyTxType:
+		inner = new(RotatedKeyTx)
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -323,6 +334,37 @@ func (tx *Transaction) RawSignatureValues() (v, r, s *big.Int) {
 	return tx.inner.rawSignatureValues()
 }
 
+// QuantumSignature returns the post-quantum public key and signature of the
+// transaction, or nils if it is not a post-quantum transaction.
+// The return values should not be modified by the caller.
+func (tx *Transaction) QuantumSignature() (pub, sig []byte) {
+	if inner, ok := tx.inner.(*PostQuantumTx); ok {
+		return inner.PublicKey, inner.Signature
+	}
+	return nil, nil
+}
+
+// MultisigSignatures returns the threshold, public keys and signature slots of
+// a multisig transaction, or zero and nils if it is not a multisig transaction.
+// The return values should not be modified by the caller.
+func (tx *Transaction) MultisigSignatures() (threshold uint64, pubs, sigs [][]byte) {
+	if inner, ok := tx.inner.(*MultisigTx); ok {
+		return inner.Threshold, inner.PublicKeys, inner.Signatures
+	}
+	return 0, nil, nil
+}
+
+// RotatedKey returns the public key and signature of a rotated key transaction,
+// or nils if it is not a rotated key transaction. The public key is the key the
+// sender's address must be bound to.
+// The return values should not be modified by the caller.
+func (tx *Transaction) RotatedKey() (pub, sig []byte) {
+	if inner, ok := tx.inner.(*RotatedKeyTx); ok {
+		return inner.PublicKey, inner.Signature
+	}
+	return nil, nil
+}
+
 // GasFeeCapCmp compares the fee cap of two transactions.
 func (tx *Transaction) GasFeeCapCmp(other *Transaction) int {
 	return tx.inner.gasFeeCap().Cmp(other.inner.gasFeeCap())
@@ -515,6 +557,58 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	return &Transaction{inner: cpy, time: tx.time}, nil
 }
 
+// WithQuantumSignature returns a new post-quantum transaction carrying the given
+// public key and signature over signer.Hash(tx).
+func (tx *Transaction) WithQuantumSignature(signer Signer, pub, sig []byte) (*Transaction, error) {
+	if tx.Type() != PostQuantumTxType {
+		return nil, ErrInvalidTxType
+	}
+	if len(pub) == 0 || len(sig) == 0 {
+		return nil, ErrInvalidQuantumSig
+	}
+	cpy := tx.inner.copy().(*PostQuantumTx)
+	cpy.ChainID = signer.ChainID()
+	cpy.PublicKey = common.CopyBytes(pub)
+	cpy.Signature = common.CopyBytes(sig)
+	return &Transaction{inner: cpy, time: tx.time}, nil
+}
+
+// WithMultisigSignature returns a new multisig transaction carrying the given
+// signature over signer.Hash(tx) in the slot of the public key at index. The
+// keys of the account sign in turn, each adding its signature to the result of
+// the previous one, until exactly the threshold of them signed.
+func (tx *Transaction) WithMultisigSignature(signer Signer, index int, sig []byte) (*Transaction, error) {
+	if tx.Type() != MultisigTxType {
+		return nil, ErrInvalidTxType
+	}
+	cpy := tx.inner.copy().(*MultisigTx)
+	if index < 0 || index >= len(cpy.PublicKeys) || len(sig) == 0 {
+		return nil, ErrInvalidMultisigSig
+	}
+	if len(cpy.Signatures) != len(cpy.PublicKeys) {
+		cpy.Signatures = make([][]byte, len(cpy.PublicKeys))
+	}
+	cpy.ChainID = signer.ChainID()
+	cpy.Signatures[index] = common.CopyBytes(sig)
+	return &Transaction{inner: cpy, time: tx.time}, nil
+}
+
+// WithRotatedKeySignature returns a new rotated key transaction carrying the
+// given public key and signature over signer.Hash(tx). The key must have been
+// set on the transaction before hashing, as the hash commits to it.
+func (tx *Transaction) WithRotatedKeySignature(signer Signer, sig []byte) (*Transaction, error) {
+	if tx.Type() != RotatedKeyTxType {
+		return nil, ErrInvalidTxType
+	}
+	cpy := tx.inner.copy().(*RotatedKeyTx)
+	if len(cpy.PublicKey) == 0 || len(sig) == 0 {
+		return nil, ErrInvalidRotatedKeySig
+	}
+	cpy.ChainID = signer.ChainID()
+	cpy.Signature = common.CopyBytes(sig)
+	return &Transaction{inner: cpy, time: tx.time}, nil
+}
+
 // Transactions implements DerivableList for transactions.
 type Transactions []*Transaction
 
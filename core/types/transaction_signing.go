@@ -21,6 +21,8 @@ import (
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
+	"github.com/ixios-io/ixiosSpark/crypto/falcon"
 	"github.com/ixios-io/ixiosSpark/params"
 )
 
@@ -50,6 +52,17 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 	default:
 		signer = FrontierSigner{}
 	}
+	// The Ixios transaction types are layered on top of the Ethereum ones, each
+	// accepted from its own fork on
+	if config.IsPostQuantum(blockNumber) {
+		signer = quantumSigner{signer, config.ChainID}
+	}
+	if config.IsMultisig(blockNumber) {
+		signer = multisigSigner{signer, config.ChainID}
+	}
+	if config.IsKeyRotation(blockNumber) {
+		signer = keyRotationSigner{signer, config.ChainID}
+	}
 	return signer
 }
 
@@ -61,21 +74,32 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 // Use this in transaction-handling code where the current block number is unknown. If you
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config *params.ChainConfig) Signer {
-	if config.ChainID != nil {
-		if config.CancunTime != nil {
-			return NewCancunSigner(config.ChainID)
-		}
-		if config.LondonBlock != nil {
-			return NewLondonSigner(config.ChainID)
-		}
-		if config.BerlinBlock != nil {
-			return NewEIP2930Signer(config.ChainID)
-		}
-		if config.EIP155Block != nil {
-			return NewEIP155Signer(config.ChainID)
-		}
+	if config.ChainID == nil {
+		return HomesteadSigner{}
+	}
+	var signer Signer
+	switch {
+	case config.CancunTime != nil:
+		signer = NewCancunSigner(config.ChainID)
+	case config.LondonBlock != nil:
+		signer = NewLondonSigner(config.ChainID)
+	case config.BerlinBlock != nil:
+		signer = NewEIP2930Signer(config.ChainID)
+	case config.EIP155Block != nil:
+		signer = NewEIP155Signer(config.ChainID)
+	default:
+		signer = HomesteadSigner{}
+	}
+	if config.PostQuantumBlock != nil {
+		signer = quantumSigner{signer, config.ChainID}
+	}
+	if config.MultisigBlock != nil {
+		signer = multisigSigner{signer, config.ChainID}
+	}
+	if config.KeyRotationBlock != nil {
+		signer = keyRotationSigner{signer, config.ChainID}
 	}
-	return HomesteadSigner{}
+	return signer
 }
 
 // LatestSignerForChainID returns the 'most permissive' Signer available. Specifically,
@@ -89,7 +113,7 @@ func LatestSignerForChainID(chainID *big.Int) Signer {
 	if chainID == nil {
 		return HomesteadSigner{}
 	}
-	return NewCancunSigner(chainID)
+	return NewKeyRotationSigner(chainID)
 }
 
 // SignTx signs the transaction using the given signer and private key.
@@ -114,6 +138,28 @@ func SignNewTx(prv *ecdsa.PrivateKey, s Signer, txdata TxData) (*Transaction, er
 	return tx.WithSignature(s, sig)
 }
 
+// SignDilithiumTx signs a post-quantum transaction using the given signer and
+// Dilithium private key.
+func SignDilithiumTx(tx *Transaction, s Signer, prv *dilithium.PrivateKey) (*Transaction, error) {
+	h := s.Hash(tx)
+	sig, err := dilithium.Sign(prv, h[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithQuantumSignature(s, prv.Public().Bytes(), sig)
+}
+
+// SignFalconTx signs a post-quantum transaction using the given signer and
+// Falcon-512 private key.
+func SignFalconTx(tx *Transaction, s Signer, prv *falcon.PrivateKey) (*Transaction, error) {
+	h := s.Hash(tx)
+	sig, err := falcon.Sign(prv, h[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithQuantumSignature(s, prv.Public().Bytes(), sig)
+}
+
 // MustSignNewTx creates a transaction and signs it.
 // This panics if the transaction cannot be signed.
 func MustSignNewTx(prv *ecdsa.PrivateKey, s Signer, txdata TxData) *Transaction {
@@ -173,6 +219,230 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+// keyRotationSigner accepts rotated key transactions on top of the signer of the
+// transaction types enabled before the key rotation fork.
+type keyRotationSigner struct {
+	Signer
+	chainId *big.Int
+}
+
+// NewKeyRotationSigner returns a signer that accepts
+// - rotated key transactions signed by the key bound to the sender
+// - multisig transactions signed by a threshold of the account's keys
+// - post-quantum transactions signed with an explicit public key
+// - EIP-4844 blob transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewKeyRotationSigner(chainId *big.Int) Signer {
+	return keyRotationSigner{NewMultisigSigner(chainId), chainId}
+}
+
+// Sender returns the sender named by a rotated key transaction once its
+// signature verifies against the carried key. Whether the key is bound to the
+// sender depends on the state and is checked when the transaction executes.
+func (s keyRotationSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != RotatedKeyTxType {
+		return s.Signer.Sender(tx)
+	}
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.chainId)
+	}
+	pub, sig := tx.RotatedKey()
+	h := s.Hash(tx)
+	if !verifyKeySignature(pub, h[:], sig) {
+		return common.Address{}, ErrInvalidRotatedKeySig
+	}
+	return tx.inner.(*RotatedKeyTx).From, nil
+}
+
+func (s keyRotationSigner) ChainID() *big.Int {
+	return s.chainId
+}
+
+func (s keyRotationSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(keyRotationSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0 && x.Signer.Equal(s.Signer)
+}
+
+// SignatureValues always fails for rotated key transactions, which must be
+// signed through Transaction.WithRotatedKeySignature instead.
+func (s keyRotationSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.Type() == RotatedKeyTxType {
+		return nil, nil, nil, ErrInvalidTxType
+	}
+	return s.Signer.SignatureValues(tx, sig)
+}
+
+// Hash returns the hash to be signed by the key bound to the sender. It covers
+// the sender and the key.
+// It does not uniquely identify the transaction.
+func (s keyRotationSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != RotatedKeyTxType {
+		return s.Signer.Hash(tx)
+	}
+	inner := tx.inner.(*RotatedKeyTx)
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			inner.From,
+			inner.PublicKey,
+		})
+}
+
+// multisigSigner accepts multisig transactions on top of the signer of the
+// transaction types enabled before the multisig fork.
+type multisigSigner struct {
+	Signer
+	chainId *big.Int
+}
+
+// NewMultisigSigner returns a signer that accepts
+// - multisig transactions signed by a threshold of the account's keys
+// - post-quantum transactions signed with an explicit public key
+// - EIP-4844 blob transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewMultisigSigner(chainId *big.Int) Signer {
+	return multisigSigner{NewQuantumSigner(chainId), chainId}
+}
+
+func (s multisigSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != MultisigTxType {
+		return s.Signer.Sender(tx)
+	}
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.chainId)
+	}
+	threshold, pubs, sigs := tx.MultisigSignatures()
+	h := s.Hash(tx)
+	return VerifyMultisigSignatures(threshold, pubs, sigs, h[:])
+}
+
+func (s multisigSigner) ChainID() *big.Int {
+	return s.chainId
+}
+
+func (s multisigSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(multisigSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0 && x.Signer.Equal(s.Signer)
+}
+
+// SignatureValues always fails for multisig transactions, which must be signed
+// through Transaction.WithMultisigSignature instead.
+func (s multisigSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.Type() == MultisigTxType {
+		return nil, nil, nil, ErrInvalidTxType
+	}
+	return s.Signer.SignatureValues(tx, sig)
+}
+
+// Hash returns the hash to be signed by each key of the sender. It covers the
+// threshold and public keys of the account, but not the signatures.
+// It does not uniquely identify the transaction.
+func (s multisigSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != MultisigTxType {
+		return s.Signer.Hash(tx)
+	}
+	threshold, pubs, _ := tx.MultisigSignatures()
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+			threshold,
+			pubs,
+		})
+}
+
+// quantumSigner accepts post-quantum transactions on top of the signer of the
+// transaction types enabled before the post-quantum fork.
+type quantumSigner struct {
+	Signer
+	chainId *big.Int
+}
+
+// NewQuantumSigner returns a signer that accepts
+// - post-quantum transactions signed with an explicit public key
+// - EIP-4844 blob transactions
+// - EIP-1559 dynamic fee transactions
+// - EIP-2930 access list transactions,
+// - EIP-155 replay protected transactions, and
+// - legacy Homestead transactions.
+func NewQuantumSigner(chainId *big.Int) Signer {
+	return quantumSigner{NewCancunSigner(chainId), chainId}
+}
+
+func (s quantumSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != PostQuantumTxType {
+		return s.Signer.Sender(tx)
+	}
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.chainId)
+	}
+	pub, sig := tx.QuantumSignature()
+	h := s.Hash(tx)
+	return VerifyQuantumSignature(pub, h[:], sig)
+}
+
+func (s quantumSigner) ChainID() *big.Int {
+	return s.chainId
+}
+
+func (s quantumSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(quantumSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0 && x.Signer.Equal(s.Signer)
+}
+
+// SignatureValues always fails for post-quantum transactions, which must be
+// signed through Transaction.WithQuantumSignature instead.
+func (s quantumSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.Type() == PostQuantumTxType {
+		return nil, nil, nil, ErrInvalidTxType
+	}
+	return s.Signer.SignatureValues(tx, sig)
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s quantumSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != PostQuantumTxType {
+		return s.Signer.Hash(tx)
+	}
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Value(),
+			tx.Data(),
+			tx.AccessList(),
+		})
+}
+
 type cancunSigner struct{ londonSigner }
 
 // NewCancunSigner returns a signer that accepts
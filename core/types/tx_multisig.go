@@ -0,0 +1,124 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/rlp"
+)
+
+// MultisigTx represents a dynamic fee transaction sent from a multisig account.
+// The sender address commits to a threshold and a set of public keys, which may
+// mix ECDSA and post-quantum schemes. The transaction carries the keys and one
+// signature slot per key, left empty by the keys that did not sign.
+type MultisigTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  *big.Int // a.k.a. maxFeePerGas
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// Signature values
+	Threshold  uint64   `json:"threshold" gencodec:"required"`
+	PublicKeys [][]byte `json:"publicKeys" gencodec:"required"`
+	Signatures [][]byte `json:"signatures" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *MultisigTx) copy() TxData {
+	cpy := &MultisigTx{
+		Nonce:     tx.Nonce,
+		To:        copyAddressPtr(tx.To),
+		Data:      common.CopyBytes(tx.Data),
+		Gas:       tx.Gas,
+		Threshold: tx.Threshold,
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		PublicKeys: make([][]byte, len(tx.PublicKeys)),
+		Signatures: make([][]byte, len(tx.Signatures)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	for i, pub := range tx.PublicKeys {
+		cpy.PublicKeys[i] = common.CopyBytes(pub)
+	}
+	for i, sig := range tx.Signatures {
+		cpy.Signatures[i] = common.CopyBytes(sig)
+	}
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *MultisigTx) txType() byte           { return MultisigTxType }
+func (tx *MultisigTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *MultisigTx) accessList() AccessList { return tx.AccessList }
+func (tx *MultisigTx) data() []byte           { return tx.Data }
+func (tx *MultisigTx) gas() uint64            { return tx.Gas }
+func (tx *MultisigTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *MultisigTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *MultisigTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *MultisigTx) value() *big.Int        { return tx.Value }
+func (tx *MultisigTx) nonce() uint64          { return tx.Nonce }
+func (tx *MultisigTx) to() *common.Address    { return tx.To }
+
+func (tx *MultisigTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+// rawSignatureValues returns zero V, R, S values, as multisig transactions carry
+// their authorisation in the PublicKeys and Signatures fields instead.
+func (tx *MultisigTx) rawSignatureValues() (v, r, s *big.Int) {
+	return new(big.Int), new(big.Int), new(big.Int)
+}
+
+func (tx *MultisigTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID = chainID
+}
+
+func (tx *MultisigTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *MultisigTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
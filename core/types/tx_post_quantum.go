@@ -0,0 +1,116 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/rlp"
+)
+
+// PostQuantumTx represents a dynamic fee transaction authorised by a post-quantum
+// signature. Unlike ECDSA, post-quantum schemes do not support public key
+// recovery, so the transaction carries the sender's public key explicitly and
+// the sender address is derived from it.
+type PostQuantumTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  *big.Int // a.k.a. maxFeePerGas
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// Signature values
+	PublicKey []byte `json:"publicKey" gencodec:"required"`
+	Signature []byte `json:"signature" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *PostQuantumTx) copy() TxData {
+	cpy := &PostQuantumTx{
+		Nonce:     tx.Nonce,
+		To:        copyAddressPtr(tx.To),
+		Data:      common.CopyBytes(tx.Data),
+		Gas:       tx.Gas,
+		PublicKey: common.CopyBytes(tx.PublicKey),
+		Signature: common.CopyBytes(tx.Signature),
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *PostQuantumTx) txType() byte           { return PostQuantumTxType }
+func (tx *PostQuantumTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *PostQuantumTx) accessList() AccessList { return tx.AccessList }
+func (tx *PostQuantumTx) data() []byte           { return tx.Data }
+func (tx *PostQuantumTx) gas() uint64            { return tx.Gas }
+func (tx *PostQuantumTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *PostQuantumTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *PostQuantumTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *PostQuantumTx) value() *big.Int        { return tx.Value }
+func (tx *PostQuantumTx) nonce() uint64          { return tx.Nonce }
+func (tx *PostQuantumTx) to() *common.Address    { return tx.To }
+
+func (tx *PostQuantumTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+// rawSignatureValues returns zero V, R, S values, as post-quantum transactions
+// carry their authorisation in the PublicKey and Signature fields instead.
+func (tx *PostQuantumTx) rawSignatureValues() (v, r, s *big.Int) {
+	return new(big.Int), new(big.Int), new(big.Int)
+}
+
+func (tx *PostQuantumTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID = chainID
+}
+
+func (tx *PostQuantumTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *PostQuantumTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
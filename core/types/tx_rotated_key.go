@@ -0,0 +1,163 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/rlp"
+)
+
+var (
+	// ErrInvalidRotatedKeySig is returned if a rotated key transaction carries a
+	// signature that does not verify against its public key.
+	ErrInvalidRotatedKeySig = errors.New("invalid rotated key signature")
+
+	// ErrInvalidKeyRotation is returned if the payload of a key rotation is
+	// malformed or its proof of possession does not verify.
+	ErrInvalidKeyRotation = errors.New("invalid key rotation")
+)
+
+// RotatedKeyTx represents a dynamic fee transaction sent from an account whose
+// address has been rebound to a new public key. As the address no longer
+// derives from the key, the transaction names its sender explicitly and carries
+// the key, which may be a compressed secp256k1 key or a post-quantum key. That
+// the key is the one bound to the sender is checked against the state.
+type RotatedKeyTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  *big.Int // a.k.a. maxFeePerGas
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+	From       common.Address
+
+	// Signature values
+	PublicKey []byte `json:"publicKey" gencodec:"required"`
+	Signature []byte `json:"signature" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *RotatedKeyTx) copy() TxData {
+	cpy := &RotatedKeyTx{
+		Nonce:     tx.Nonce,
+		To:        copyAddressPtr(tx.To),
+		Data:      common.CopyBytes(tx.Data),
+		Gas:       tx.Gas,
+		From:      tx.From,
+		PublicKey: common.CopyBytes(tx.PublicKey),
+		Signature: common.CopyBytes(tx.Signature),
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *RotatedKeyTx) txType() byte           { return RotatedKeyTxType }
+func (tx *RotatedKeyTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *RotatedKeyTx) accessList() AccessList { return tx.AccessList }
+func (tx *RotatedKeyTx) data() []byte           { return tx.Data }
+func (tx *RotatedKeyTx) gas() uint64            { return tx.Gas }
+func (tx *RotatedKeyTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *RotatedKeyTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *RotatedKeyTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *RotatedKeyTx) value() *big.Int        { return tx.Value }
+func (tx *RotatedKeyTx) nonce() uint64          { return tx.Nonce }
+func (tx *RotatedKeyTx) to() *common.Address    { return tx.To }
+
+func (tx *RotatedKeyTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+// rawSignatureValues returns zero V, R, S values, as rotated key transactions
+// carry their authorisation in the PublicKey and Signature fields instead.
+func (tx *RotatedKeyTx) rawSignatureValues() (v, r, s *big.Int) {
+	return new(big.Int), new(big.Int), new(big.Int)
+}
+
+func (tx *RotatedKeyTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID = chainID
+}
+
+func (tx *RotatedKeyTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *RotatedKeyTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
+
+// KeyRotation is the payload of a transaction to the key registry, rebinding
+// the address of its sender to a new public key. The proof is a signature of
+// KeyRotationHash made by the new key, so that only keys held by the sender
+// can be bound.
+type KeyRotation struct {
+	PublicKey []byte
+	Proof     []byte
+}
+
+// KeyRotationHash returns the hash the new key signs to prove its possession
+// when rebinding the given account.
+func KeyRotationHash(chainID *big.Int, account common.Address) common.Hash {
+	return rlpHash([]interface{}{"ixios key rotation", chainID, account})
+}
+
+// VerifyKeyRotation decodes the payload of a key rotation and checks its proof
+// of possession, returning the new public key and the address it derives.
+func VerifyKeyRotation(chainID *big.Int, account common.Address, data []byte) ([]byte, common.Address, error) {
+	var rot KeyRotation
+	if err := rlp.DecodeBytes(data, &rot); err != nil {
+		return nil, common.Address{}, ErrInvalidKeyRotation
+	}
+	addr, err := PublicKeyToAddress(rot.PublicKey)
+	if err != nil {
+		return nil, common.Address{}, ErrInvalidKeyRotation
+	}
+	h := KeyRotationHash(chainID, account)
+	if !verifyKeySignature(rot.PublicKey, h[:], rot.Proof) {
+		return nil, common.Address{}, ErrInvalidKeyRotation
+	}
+	return rot.PublicKey, addr, nil
+}
@@ -23,16 +23,17 @@ import (
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/params"
 )
 
 const pubKeyHexSize = 132 // e.g., "0x" + 130 hex chars => 132 ASCII bytes
-const mksDataSize = 2656
+const mksDataSize = params.MKSSize
 const readChunkSize = pubKeyHexSize + mksDataSize // = 2,788
 const chunkSize = mksDataSize + common.AddressLength
 
 type ValidatorMKS struct {
 	Address common.Address // 32 bytes: first 6 bytes are zero, last 26 bytes are keccak(...)
-	MKSData []byte         // 5,348 bytes
+	MKSData []byte         // params.MKSSize bytes of MKS public keys
 }
 
 // validatorsDat is the raw binary data with all validators combined.
@@ -40,6 +41,12 @@ type ValidatorMKS struct {
 //go:embed data/validators.dat
 var validatorsDat []byte
 
+// EmbeddedValidators returns the validators of the mainnet genesis along with
+// their MKS public keys, as embedded in the binary.
+func EmbeddedValidators() ([]ValidatorMKS, error) {
+	return parseEmbeddedValidators()
+}
+
 // parseEmbeddedValidators splits validatorsDat into a slice of ValidatorMKS objects.
 //
 // The on-disk format is:
@@ -22,10 +22,13 @@ import (
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/math"
+	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/crypto/blake2b"
 	"github.com/ixios-io/ixiosSpark/crypto/bls12381"
 	"github.com/ixios-io/ixiosSpark/crypto/bn256"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
+	"github.com/ixios-io/ixiosSpark/crypto/falcon"
 	"github.com/ixios-io/ixiosSpark/crypto/kzg4844"
 	"github.com/ixios-io/ixiosSpark/params"
 	"golang.org/x/crypto/ripemd160"
@@ -104,6 +107,16 @@ var PrecompiledContractsCancun = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{0x0a}): &kzgPointEvaluation{},
 }
 
+// PrecompiledContractsPostQuantum contains the signature verification contracts
+// added by the post-quantum fork on top of the set of the active Ethereum forks.
+// They sit at 0x01XX, where XX is the address prefix of the signature scheme.
+var PrecompiledContractsPostQuantum = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{0x01, 0x02}): &dilithiumVerify{params: dilithium.Dilithium2, gas: params.Dilithium2VerifyGas},
+	common.BytesToAddress([]byte{0x01, 0x03}): &dilithiumVerify{params: dilithium.Dilithium3, gas: params.Dilithium3VerifyGas},
+	common.BytesToAddress([]byte{0x01, 0x05}): &dilithiumVerify{params: dilithium.Dilithium5, gas: params.Dilithium5VerifyGas},
+	common.BytesToAddress([]byte{0x01, 0x06}): &falconVerify{},
+}
+
 // PrecompiledContractsBLS contains the set of pre-compiled Ixios
 // contracts specified in EIP-2537. These are exported for testing purposes.
 var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
@@ -119,11 +132,12 @@ var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
 }
 
 var (
-	PrecompiledAddressesCancun    []common.Address
-	PrecompiledAddressesBerlin    []common.Address
-	PrecompiledAddressesIstanbul  []common.Address
-	PrecompiledAddressesByzantium []common.Address
-	PrecompiledAddressesHomestead []common.Address
+	PrecompiledAddressesPostQuantum []common.Address
+	PrecompiledAddressesCancun      []common.Address
+	PrecompiledAddressesBerlin      []common.Address
+	PrecompiledAddressesIstanbul    []common.Address
+	PrecompiledAddressesByzantium   []common.Address
+	PrecompiledAddressesHomestead   []common.Address
 )
 
 func init() {
@@ -142,22 +156,30 @@ func init() {
 	for k := range PrecompiledContractsCancun {
 		PrecompiledAddressesCancun = append(PrecompiledAddressesCancun, k)
 	}
+	for k := range PrecompiledContractsPostQuantum {
+		PrecompiledAddressesPostQuantum = append(PrecompiledAddressesPostQuantum, k)
+	}
 }
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	var addrs []common.Address
 	switch {
 	case rules.IsCancun:
-		return PrecompiledAddressesCancun
+		addrs = PrecompiledAddressesCancun
 	case rules.IsBerlin:
-		return PrecompiledAddressesBerlin
+		addrs = PrecompiledAddressesBerlin
 	case rules.IsIstanbul:
-		return PrecompiledAddressesIstanbul
+		addrs = PrecompiledAddressesIstanbul
 	case rules.IsByzantium:
-		return PrecompiledAddressesByzantium
+		addrs = PrecompiledAddressesByzantium
 	default:
-		return PrecompiledAddressesHomestead
+		addrs = PrecompiledAddressesHomestead
 	}
+	if rules.IsPostQuantum {
+		addrs = append(append([]common.Address{}, addrs...), PrecompiledAddressesPostQuantum...)
+	}
+	return addrs
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
@@ -1145,3 +1167,72 @@ func kZGToVersionedHash(kzg kzg4844.Commitment) common.Hash {
 
 	return h
 }
+
+// dilithiumVerify implements Dilithium signature verification as a native
+// contract for a single parameter set.
+//
+// The input is the 32 byte message digest, followed by the packed public key and
+// the packed signature. On success the 32 byte address owned by the public key
+// is returned, otherwise the output is empty.
+type dilithiumVerify struct {
+	params *dilithium.Params
+	gas    uint64
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *dilithiumVerify) RequiredGas(input []byte) uint64 {
+	return c.gas
+}
+
+func (c *dilithiumVerify) Run(input []byte) ([]byte, error) {
+	if len(input) != 32+c.params.PublicKeySize+c.params.SignatureSize {
+		return nil, nil
+	}
+	var (
+		digest = input[:32]
+		pub    = input[32 : 32+c.params.PublicKeySize]
+		sig    = input[32+c.params.PublicKeySize:]
+	)
+	key, err := dilithium.UnmarshalPublicKey(c.params, pub)
+	if err != nil || !dilithium.Verify(key, digest, sig) {
+		return nil, nil
+	}
+	addr, err := types.QuantumPubkeyToAddress(pub)
+	if err != nil {
+		return nil, nil
+	}
+	return addr.Bytes(), nil
+}
+
+// falconVerify implements Falcon-512 signature verification as a native contract.
+//
+// The input is the 32 byte message digest, followed by the encoded public key and
+// the compressed signature, which takes up the remainder of the input. On success
+// the 32 byte address owned by the public key is returned, otherwise the output
+// is empty.
+type falconVerify struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *falconVerify) RequiredGas(input []byte) uint64 {
+	return params.Falcon512VerifyGas
+}
+
+func (c *falconVerify) Run(input []byte) ([]byte, error) {
+	if len(input) <= 32+falcon.PublicKeySize || len(input) > 32+falcon.PublicKeySize+falcon.MaxSignatureSize {
+		return nil, nil
+	}
+	var (
+		digest = input[:32]
+		pub    = input[32 : 32+falcon.PublicKeySize]
+		sig    = input[32+falcon.PublicKeySize:]
+	)
+	key, err := falcon.UnmarshalPublicKey(pub)
+	if err != nil || !falcon.Verify(key, digest, sig) {
+		return nil, nil
+	}
+	addr, err := types.QuantumPubkeyToAddress(pub)
+	if err != nil {
+		return nil, nil
+	}
+	return addr.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/params"
+)
+
+// precompiledTest defines the input/output pairs for precompiled contract tests.
+type precompiledTest struct {
+	Input, Expected string
+	Gas             uint64
+	Name            string
+	NoBenchmark     bool // Benchmark primarily the worst-cases
+}
+
+func testPrecompiled(addr string, test precompiledTest, t *testing.T) {
+	p := PrecompiledContractsPostQuantum[common.HexToAddress(addr)]
+	in := common.Hex2Bytes(test.Input)
+	gas := p.RequiredGas(in)
+	t.Run(fmt.Sprintf("%s-Gas=%d", test.Name, gas), func(t *testing.T) {
+		if res, _, err := RunPrecompiledContract(p, in, gas); err != nil {
+			t.Error(err)
+		} else if common.Bytes2Hex(res) != test.Expected {
+			t.Errorf("Expected %v, got %v", test.Expected, common.Bytes2Hex(res))
+		}
+		if expGas := test.Gas; expGas != gas {
+			t.Errorf("%v: gas wrong, expected %d, got %d", test.Name, expGas, gas)
+		}
+		// Verify that the precompile did not touch the input buffer
+		exp := common.Hex2Bytes(test.Input)
+		if !bytes.Equal(in, exp) {
+			t.Errorf("Precompiled %v modified input data", addr)
+		}
+	})
+}
+
+func testPrecompiledOOG(addr string, test precompiledTest, t *testing.T) {
+	p := PrecompiledContractsPostQuantum[common.HexToAddress(addr)]
+	in := common.Hex2Bytes(test.Input)
+	gas := p.RequiredGas(in) - 1
+
+	t.Run(fmt.Sprintf("%s-Gas=%d", test.Name, gas), func(t *testing.T) {
+		_, _, err := RunPrecompiledContract(p, in, gas)
+		if err != ErrOutOfGas {
+			t.Errorf("Expected error [out of gas], got [%v]", err)
+		}
+	})
+}
+
+func testJson(name, addr string, t *testing.T) {
+	tests, err := loadJson(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		testPrecompiled(addr, test, t)
+	}
+}
+
+func loadJson(name string) ([]precompiledTest, error) {
+	data, err := os.ReadFile(fmt.Sprintf("testdata/precompiles/%v.json", name))
+	if err != nil {
+		return nil, err
+	}
+	var testcases []precompiledTest
+	err = json.Unmarshal(data, &testcases)
+	return testcases, err
+}
+
+func TestPrecompiledDilithium2Verify(t *testing.T) { testJson("dilithium2Verify", "0102", t) }
+func TestPrecompiledDilithium3Verify(t *testing.T) { testJson("dilithium3Verify", "0103", t) }
+func TestPrecompiledDilithium5Verify(t *testing.T) { testJson("dilithium5Verify", "0105", t) }
+func TestPrecompiledFalcon512Verify(t *testing.T)  { testJson("falcon512Verify", "0106", t) }
+
+func TestPrecompiledDilithium5VerifyOOG(t *testing.T) {
+	tests, err := loadJson("dilithium5Verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPrecompiledOOG("0105", tests[0], t)
+}
+
+// TestActivePrecompilesPostQuantum checks that the post-quantum fork adds its
+// verification contracts to the set of the active Ethereum forks instead of
+// replacing it.
+func TestActivePrecompilesPostQuantum(t *testing.T) {
+	for _, rules := range []params.Rules{
+		{IsByzantium: true, IsPostQuantum: true},
+		{IsByzantium: true, IsIstanbul: true, IsBerlin: true, IsPostQuantum: true},
+		{IsByzantium: true, IsIstanbul: true, IsBerlin: true, IsCancun: true, IsPostQuantum: true},
+	} {
+		base := rules
+		base.IsPostQuantum = false
+
+		active := ActivePrecompiles(rules)
+		if len(active) != len(ActivePrecompiles(base))+len(PrecompiledContractsPostQuantum) {
+			t.Fatalf("rules %+v: have %d precompiles, want %d", rules, len(active), len(ActivePrecompiles(base))+len(PrecompiledContractsPostQuantum))
+		}
+		evm := &EVM{chainRules: rules}
+		for _, addr := range ActivePrecompiles(base) {
+			if _, ok := evm.precompile(addr); !ok || !slices.Contains(active, addr) {
+				t.Errorf("rules %+v: precompile %x of the active forks missing", rules, addr)
+			}
+		}
+		for addr := range PrecompiledContractsPostQuantum {
+			if _, ok := evm.precompile(addr); !ok || !slices.Contains(active, addr) {
+				t.Errorf("rules %+v: post-quantum precompile %x missing", rules, addr)
+			}
+		}
+		if _, ok := (&EVM{chainRules: base}).precompile(common.BytesToAddress([]byte{0x01, 0x05})); ok {
+			t.Errorf("rules %+v: post-quantum precompile active before the fork", base)
+		}
+	}
+}
@@ -54,6 +54,9 @@ func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 		precompiles = PrecompiledContractsHomestead
 	}
 	p, ok := precompiles[addr]
+	if !ok && evm.chainRules.IsPostQuantum {
+		p, ok = PrecompiledContractsPostQuantum[addr]
+	}
 	return p, ok
 }
 
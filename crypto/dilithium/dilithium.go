@@ -13,4 +13,149 @@
 // A copy of the GNU Lesser General Public License should have been provided with this software.
 // If not, see <http://www.gnu.org/licenses/>.
 
+// Package dilithium implements the ML-DSA (CRYSTALS-Dilithium) post-quantum
+// digital signature scheme as standardised in FIPS 204, for the Dilithium2,
+// Dilithium3 and Dilithium5 parameter sets (ML-DSA-44, ML-DSA-65, ML-DSA-87).
+//
+// All operations on secret data are free of secret dependent branches and
+// memory accesses; only the number of rejection sampling rounds may leak.
 package dilithium
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidPublicKey is returned if a public key encoding has the wrong size.
+	ErrInvalidPublicKey = errors.New("dilithium: invalid public key")
+
+	// ErrInvalidPrivateKey is returned if a private key encoding has the wrong size.
+	ErrInvalidPrivateKey = errors.New("dilithium: invalid private key")
+
+	// ErrContextTooLong is returned if a context string exceeds MaxContextSize.
+	ErrContextTooLong = errors.New("dilithium: context string too long")
+)
+
+// PublicKey is an ML-DSA verification key.
+type PublicKey struct {
+	params *Params
+	packed []byte
+}
+
+// PrivateKey is an ML-DSA signing key along with its public key.
+type PrivateKey struct {
+	PublicKey
+	packed []byte
+}
+
+// GenerateKey creates a new key pair for the given parameter set, drawing the
+// seed from rand. If rand is nil, crypto/rand is used.
+func GenerateKey(p *Params, random io.Reader) (*PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	seed := make([]byte, SeedSize)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, err
+	}
+	return keygen(p, seed), nil
+}
+
+// NewKeyFromSeed deterministically derives a key pair from a 32 byte seed.
+func NewKeyFromSeed(p *Params, seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, fmt.Errorf("dilithium: invalid seed length %d, want %d", len(seed), SeedSize)
+	}
+	return keygen(p, seed), nil
+}
+
+// UnmarshalPublicKey decodes a packed public key of the given parameter set.
+func UnmarshalPublicKey(p *Params, b []byte) (*PublicKey, error) {
+	if len(b) != p.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{params: p, packed: append([]byte(nil), b...)}, nil
+}
+
+// UnmarshalPrivateKey decodes a packed private key of the given parameter set,
+// recomputing the matching public key.
+func UnmarshalPrivateKey(p *Params, b []byte) (*PrivateKey, error) {
+	if len(b) != p.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	packed := append([]byte(nil), b...)
+	rho, _, _, _, s1, s2 := unpackPrivateKey(p, packed)
+
+	t1, t0 := newPolyvec(p.K), newPolyvec(p.K)
+	computeT(p, expandMatrix(p, rho), s1, s2).power2round(t1, t0)
+
+	return &PrivateKey{
+		PublicKey: PublicKey{params: p, packed: packPublicKey(p, rho, t1)},
+		packed:    packed,
+	}, nil
+}
+
+// ParamsByPublicKeySize returns the parameter set whose public keys have the
+// given encoded length, or nil if none match.
+func ParamsByPublicKeySize(size int) *Params {
+	for _, p := range []*Params{Dilithium2, Dilithium3, Dilithium5} {
+		if p.PublicKeySize == size {
+			return p
+		}
+	}
+	return nil
+}
+
+// Params returns the parameter set of the key.
+func (pub *PublicKey) Params() *Params { return pub.params }
+
+// Bytes returns the packed encoding of the public key.
+func (pub *PublicKey) Bytes() []byte { return append([]byte(nil), pub.packed...) }
+
+// Equal reports whether pub and other encode the same key.
+func (pub *PublicKey) Equal(other *PublicKey) bool {
+	return pub.params == other.params && string(pub.packed) == string(other.packed)
+}
+
+// Public returns the public key corresponding to priv.
+func (priv *PrivateKey) Public() *PublicKey { return &priv.PublicKey }
+
+// Bytes returns the packed encoding of the private key.
+func (priv *PrivateKey) Bytes() []byte { return append([]byte(nil), priv.packed...) }
+
+// Sign creates a hedged signature over msg with an empty context string.
+func Sign(priv *PrivateKey, msg []byte) ([]byte, error) {
+	return SignWithContext(priv, rand.Reader, msg, nil)
+}
+
+// SignWithContext creates a signature over msg bound to the context string
+// ctx. The per-signature randomness is drawn from random; if random is nil
+// the deterministic variant of ML-DSA is used.
+func SignWithContext(priv *PrivateKey, random io.Reader, msg, ctx []byte) ([]byte, error) {
+	if len(ctx) > MaxContextSize {
+		return nil, ErrContextTooLong
+	}
+	rnd := make([]byte, rndSize)
+	if random != nil {
+		if _, err := io.ReadFull(random, rnd); err != nil {
+			return nil, err
+		}
+	}
+	return signInternal(priv, messagePrefix(ctx), msg, rnd), nil
+}
+
+// Verify checks a signature over msg created with an empty context string.
+func Verify(pub *PublicKey, msg, sig []byte) bool {
+	return VerifyWithContext(pub, msg, nil, sig)
+}
+
+// VerifyWithContext checks a signature over msg bound to the context string ctx.
+func VerifyWithContext(pub *PublicKey, msg, ctx, sig []byte) bool {
+	if len(ctx) > MaxContextSize {
+		return false
+	}
+	return verifyInternal(pub, messagePrefix(ctx), msg, sig)
+}
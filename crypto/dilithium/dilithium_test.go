@@ -0,0 +1,223 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package dilithium
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestSignInternalKAT checks key generation and deterministic signing against
+// the NIST ACVP ML-DSA.Sign_internal known answer tests for rejection cases.
+// The key hash is SHA2-256(pk || sk), the message is the M' input of
+// Sign_internal and the signature hash is SHA2-256(sig).
+//
+// https://pages.nist.gov/ACVP/draft-celi-acvp-ml-dsa.html#table-1
+func TestSignInternalKAT(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *Params
+		seed    string
+		keyHash string
+		msg     string
+		sigHash string
+	}{
+		{"ML-DSA-44/1", Dilithium2,
+			"5c624fcc1862452452d0c665840d8237f43108e5499edcdc108fbc49d596e4b7",
+			"ac825c59d8a4c453a2c4efea8395741ca404f3000e28d56b25d03bb402e5cb2f",
+			"951fdf5473a4cba6d9e5b5db7e79fb8173921ba5b13e9271401b8f907b8b7d5b",
+			"dcc71a421bc6ffafb7df0c7f6d018a19ada154d1e2ee360ed533cecd5dc980ad"},
+		{"ML-DSA-44/2", Dilithium2,
+			"836eabedb4d2cd9be6a4d957cf5ee6bf489304136864c55c2c5f01da5047d18b",
+			"e1ff40d96e3552fab531d1715084b7e38ccdbacc0a8af94c30959fb4c7f5a445",
+			"199a0ab735e9004163dd02d319a61cfe81638e3bf47bb1e90e90d6e3ea545247",
+			"a2608bc27e60541d27b6a14f460d54a48c0298dcc3f45999f29047a3135c4941"},
+		{"ML-DSA-44/3", Dilithium2,
+			"ca5a01e1ea6552cb5c9803462b94c2f1dc9d13bb17a6ace510d157056a2c6114",
+			"a4652dc4a271095268dd84a5b0744dfdbe2e642e4d41fbc4329c2fba534c0e13",
+			"8c8caca88fff52b9330510537b3701b3993f3726136a650f48f8604551550832",
+			"b4b142209137397dad504caed01d390adaf49973d8d2414fc3457fb7af775189"},
+		{"ML-DSA-44/4", Dilithium2,
+			"9c005f1550b4f31855c6b92f978736733f37791cb39dd182d7ba5732bdc2483e",
+			"2485aa99345f1b334d4d94b610fbffccb626cbfd4e9ff0e1f6fc35093c423544",
+			"b744343f30f7fee088998ba574e799f1bf3939c06c29bf9ac10f3588a57e21e2",
+			"5b80a60baa480b9d0c7d2c05b50928c4bf6808dda693642058a3eb77eaa768fc"},
+		{"ML-DSA-44/5", Dilithium2,
+			"4fab5485b009399e8ae6fc3d3eefbfe8e09796e4477aabd5eb1cc908fa734de3",
+			"cb56909a7cf3008a662dc635edcb79dc151ca7acbae17b544384abd91bbbc1e9",
+			"7cab0fdcf4bea5f039137478aa45c9c48ef96d906fc49f6e2f138111bf1b4a4e",
+			"6cc38d73d639682abc556dc6dcf436de24033091f34004f410fabc6887f77ab0"},
+		{"ML-DSA-65/1", Dilithium3,
+			"464756a985e5df03739d95dd309c1ed9c5b04254cc294e7e7eb9b9365ee15117",
+			"ae95ea0daa80199e7b4a74eb5a1b1dc6c3805bd01d2fa78d7c4fba8c255aa13d",
+			"491101bba044de6e44a63796c33cda051bb05a60725b87af4ba9db940c03ac09",
+			"8e08ea0c8db941685b9905a73b0b57bad3500b1f73490480b24375b41230cc04"},
+		{"ML-DSA-65/2", Dilithium3,
+			"235a48db4ca7916b884f424a8586efd517e87c64aecec0fce9a3cc212ba1522e",
+			"1ac58a909db4d7bc2473ab5e24af768279c76f86a82d448258e24eea4ea6b713",
+			"f8ce85cb2ec474ffbf5a3ffae029ce6f4526b8d597655067f97f438b81071e9b",
+			"ae9531a01738615b6d33c77b3ff618a86e101fdc4c8504681f0edfa64511ad63"},
+		{"ML-DSA-65/3", Dilithium3,
+			"e13131b705a760305feffebfe99082e2691a444bbefcc3edf67d909886200207",
+			"b422093f95cc489c52f4fa2b8973a2fddd44426d1d04d1aaeefc8715d417181f",
+			"cd365512c7e61bbaa130800b37f3bb46aaf1beef3742ea8a9010a6dd4576ed0b",
+			"3c55e604deca7b89a99305d7a391c35f66a17c1923f467675ec951c0948d21c9"},
+		{"ML-DSA-65/4", Dilithium3,
+			"0a4793e040a4bc0d0f37643d12c1ea1f10648724609936c76e0ec83e37209e92",
+			"622d26d536d4d66cd94956b33a74e2e830ed265d25c34ff7c3e5243403146adf",
+			"6d9c7a795e48d80a892cbf4d4558429787277e3806eb5d0bce1640eebbbf9aec",
+			"3b141110b9f56540b2d49aacde6399974a4eac40621e367e68d4504f294db21b"},
+		{"ML-DSA-65/5", Dilithium3,
+			"f865b889e5022d54babc81ca67e7eb39f1ac42f92cf5295c3da5c9667db1b924",
+			"45bc8edd1a620c46e973e346844270721824d97888bc174281852d98b7e8f4a3",
+			"047afaadbe020ed2d766da85317dede80be550545f0b21e3f555a990f8004258",
+			"56308a3578360c41356ba9c97d3240e01767fa76bbba9fd0cc6cfa9add088db9"},
+		{"ML-DSA-87/1", Dilithium5,
+			"0d58219132746be077dfe821e9f8fd87857b28ab91d6a567e312a73e2636032c",
+			"4d261270341a7ac6b66900ddc2b8ab34ab483c897410ddf3b2c072bdda416434",
+			"3aa49ef72d010aec19383ba1e83ec2dd3dcc207a96ffceb9ffa269e3e3d66400",
+			"5049dc39045618b903c71595b3a3e07a731f95d37304623acc98bcef4258b4ca"},
+		{"ML-DSA-87/2", Dilithium5,
+			"146c47ab9f88408eb76a813294d533b29d7e0fda75da5a4e7c69eb61efeebb78",
+			"05194438af855b79db8ccccb647d6ba5c7aaf901bbd09d3b29395f0ea431d164",
+			"82c44f998a8d24f056084d0e80ecfd8434493385a284c69974923c270d397782",
+			"cffc5988a351e14a3ee1282f042a143679c4503814296b27993949a7ff966f57"},
+		{"ML-DSA-87/3", Dilithium5,
+			"049d9b0b646a2ac7f50b63ce5e4bfe44c9b87634f4ff6c14c513e388b8a1f808",
+			"ac8fe6b2fe26591b129ea536a9a001c785d8acbdd9489f6e51469a156e9e635d",
+			"febc9f8ae159002be1a11d395959dd7fc20718135690cdaa2bcfb5801c02ab89",
+			"ff4006089bdf7337e868f86ddf48f239d2a52ea1d0f686e0103bf19c3b571db1"},
+		{"ML-DSA-87/4", Dilithium5,
+			"9823ddde446a8ea883dad3ac6477f79839fdc2d2def2416be0a8b71cfbc3f5c6",
+			"525010e307c4ea7667d54ee27007c219b01f4cf88dc3ab2de8e9aaa59440a884",
+			"f7592c97c1a96a2f4053588f5cdad4c50bf7c3752709854fa27779b445dd2ba2",
+			"fd7757602b83b0a67a314cd5bcc880e7ae47acdf4d6af98269028efb486838f7"},
+		{"ML-DSA-87/5", Dilithium5,
+			"ae213fe8589b414f53780d8b9b6837179967e13cb474c5ad365c043778d2bc90",
+			"d4988e91064e5df6d867434d1ded16dcd8533e39e420dc2b4eb9e40a84146f7d",
+			"19c1913ba76ff04596bb7cc80fd825a5aedef5d5ad61cedb5203e6d7edb18877",
+			"23fe743edd101970d499e7eb57a7aa245baf417e851b260c55dd525a445f08da"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priv, err := NewKeyFromSeed(tt.params, fromHex(tt.seed))
+			if err != nil {
+				t.Fatalf("failed to derive key: %v", err)
+			}
+			pub := priv.Public()
+			if have := sha256.Sum256(append(pub.Bytes(), priv.Bytes()...)); !bytes.Equal(have[:], fromHex(tt.keyHash)) {
+				t.Errorf("key hash mismatch: have %x, want %s", have, tt.keyHash)
+			}
+			msg := fromHex(tt.msg)
+			sig := signInternal(priv, nil, msg, make([]byte, rndSize))
+			if have := sha256.Sum256(sig); !bytes.Equal(have[:], fromHex(tt.sigHash)) {
+				t.Errorf("signature hash mismatch: have %x, want %s", have, tt.sigHash)
+			}
+			if !verifyInternal(pub, nil, msg, sig) {
+				t.Errorf("signature rejected")
+			}
+			if verifyInternal(pub, nil, make([]byte, len(msg)), sig) {
+				t.Errorf("signature accepted for the wrong message")
+			}
+		})
+	}
+}
+
+// TestAccumulated derives keys from a SHAKE-128 stream of seeds, signs the
+// empty message deterministically with each and checks the SHAKE-128 hash over
+// all public keys and signatures against the one produced by the Go standard
+// library crypto/mldsa package for the same inputs.
+func TestAccumulated(t *testing.T) {
+	tests := []struct {
+		params *Params
+		want   string
+	}{
+		{Dilithium2, "d51148e1f9f4fa1a723a6cf42e25f2a99eb5c1b378b3d2dbbd561b1203beeae4"},
+		{Dilithium3, "8358a1843220194417cadbc2651295cd8fc65125b5a5c1a239a16dc8b57ca199"},
+		{Dilithium5, "8c3ad714777622b8f21ce31bb35f71394f23bc0fcf3c78ace5d608990f3b061b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params.Name, func(t *testing.T) {
+			var (
+				seeds = sha3.NewShake128()
+				out   = sha3.NewShake128()
+				seed  = make([]byte, SeedSize)
+			)
+			for i := 0; i < 100; i++ {
+				seeds.Read(seed)
+				priv, err := NewKeyFromSeed(tt.params, seed)
+				if err != nil {
+					t.Fatalf("failed to derive key: %v", err)
+				}
+				sig, err := SignWithContext(priv, nil, nil, nil)
+				if err != nil {
+					t.Fatalf("failed to sign: %v", err)
+				}
+				out.Write(priv.Public().Bytes())
+				out.Write(sig)
+
+				pub, err := UnmarshalPublicKey(tt.params, priv.Public().Bytes())
+				if err != nil {
+					t.Fatalf("failed to decode public key: %v", err)
+				}
+				if !Verify(pub, nil, sig) {
+					t.Fatalf("signature rejected")
+				}
+			}
+			sum := make([]byte, 32)
+			out.Read(sum)
+			if have := hex.EncodeToString(sum); have != tt.want {
+				t.Errorf("accumulated hash mismatch: have %s, want %s", have, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignVerifyContext(t *testing.T) {
+	for _, params := range []*Params{Dilithium2, Dilithium3, Dilithium5} {
+		priv, err := GenerateKey(params, nil)
+		if err != nil {
+			t.Fatalf("%s: failed to generate key: %v", params.Name, err)
+		}
+		msg, ctx := []byte("message"), []byte("context")
+		sig, err := SignWithContext(priv, bytes.NewReader(make([]byte, rndSize)), msg, ctx)
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", params.Name, err)
+		}
+		if !VerifyWithContext(priv.Public(), msg, ctx, sig) {
+			t.Errorf("%s: signature rejected", params.Name)
+		}
+		if VerifyWithContext(priv.Public(), msg, nil, sig) {
+			t.Errorf("%s: signature accepted without its context", params.Name)
+		}
+		sig[0] ^= 1
+		if VerifyWithContext(priv.Public(), msg, ctx, sig) {
+			t.Errorf("%s: tampered signature accepted", params.Name)
+		}
+	}
+}
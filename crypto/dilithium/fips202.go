@@ -14,3 +14,39 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	shake128Rate = 168 // SHAKE128 block size in bytes
+	shake256Rate = 136 // SHAKE256 block size in bytes
+)
+
+// stream128 returns a SHAKE128 instance absorbing seed || nonce, where the
+// nonce is encoded as two little endian bytes.
+func stream128(seed []byte, nonce uint16) sha3.ShakeHash {
+	h := sha3.NewShake128()
+	h.Write(seed)
+	h.Write([]byte{byte(nonce), byte(nonce >> 8)})
+	return h
+}
+
+// stream256 returns a SHAKE256 instance absorbing seed || nonce, where the
+// nonce is encoded as two little endian bytes.
+func stream256(seed []byte, nonce uint16) sha3.ShakeHash {
+	h := sha3.NewShake256()
+	h.Write(seed)
+	h.Write([]byte{byte(nonce), byte(nonce >> 8)})
+	return h
+}
+
+// shake256 hashes the concatenation of the given inputs into out.
+func shake256(out []byte, data ...[]byte) {
+	h := sha3.NewShake256()
+	for _, b := range data {
+		h.Write(b)
+	}
+	h.Read(out)
+}
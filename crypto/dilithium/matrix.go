@@ -14,3 +14,25 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+// matrix is the K x L public matrix A, held in the NTT domain.
+type matrix []polyvec
+
+// expandMatrix derives the matrix A from the public seed rho.
+func expandMatrix(p *Params, rho []byte) matrix {
+	mat := make(matrix, p.K)
+	for i := range mat {
+		mat[i] = newPolyvec(p.L)
+		for j := range mat[i] {
+			mat[i][j].uniform(rho, uint16(i<<8+j))
+		}
+	}
+	return mat
+}
+
+// mulVec sets t = A*v in the NTT domain.
+func (mat matrix) mulVec(t, v polyvec) {
+	for i := range mat {
+		pointwiseAccMontgomery(&t[i], mat[i], v)
+	}
+}
@@ -14,3 +14,230 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+// packBits serialises the n values of a into buf using the given bit width,
+// least significant bit first. The values must already be non-negative and
+// fit in bits.
+func packBits(buf []byte, a *[n]uint32, bits int) {
+	var (
+		acc   uint64
+		width int
+		pos   int
+	)
+	for i := 0; i < n; i++ {
+		acc |= uint64(a[i]) << width
+		width += bits
+		for width >= 8 {
+			buf[pos] = byte(acc)
+			pos++
+			acc >>= 8
+			width -= 8
+		}
+	}
+}
+
+// unpackBits is the inverse of packBits.
+func unpackBits(a *[n]uint32, buf []byte, bits int) {
+	var (
+		acc   uint64
+		width int
+		pos   int
+		mask  = uint64(1)<<bits - 1
+	)
+	for i := 0; i < n; i++ {
+		for width < bits {
+			acc |= uint64(buf[pos]) << width
+			pos++
+			width += 8
+		}
+		a[i] = uint32(acc & mask)
+		acc >>= bits
+		width -= bits
+	}
+}
+
+// packOffset packs offset - a[i] for every coefficient.
+func (a *poly) packOffset(buf []byte, offset int32, bits int) {
+	var t [n]uint32
+	for i := range a {
+		t[i] = uint32(offset - a[i])
+	}
+	packBits(buf, &t, bits)
+}
+
+// unpackOffset sets a[i] = offset - v[i] for the packed values v.
+func (a *poly) unpackOffset(buf []byte, offset int32, bits int) {
+	var t [n]uint32
+	unpackBits(&t, buf, bits)
+	for i := range a {
+		a[i] = offset - int32(t[i])
+	}
+}
+
+func (a *poly) packEta(p *Params, buf []byte)   { a.packOffset(buf, int32(p.Eta), p.etaBits()) }
+func (a *poly) unpackEta(p *Params, buf []byte) { a.unpackOffset(buf, int32(p.Eta), p.etaBits()) }
+
+func (a *poly) packT0(buf []byte)   { a.packOffset(buf, 1<<(d-1), d) }
+func (a *poly) unpackT0(buf []byte) { a.unpackOffset(buf, 1<<(d-1), d) }
+
+func (a *poly) packZ(p *Params, buf []byte)   { a.packOffset(buf, int32(p.Gamma1), p.gamma1Bits()) }
+func (a *poly) unpackZ(p *Params, buf []byte) { a.unpackOffset(buf, int32(p.Gamma1), p.gamma1Bits()) }
+
+// packT1 packs the 10 bit high-order part of t.
+func (a *poly) packT1(buf []byte) {
+	var t [n]uint32
+	for i := range a {
+		t[i] = uint32(a[i])
+	}
+	packBits(buf, &t, 10)
+}
+
+// unpackT1 unpacks the 10 bit high-order part of t.
+func (a *poly) unpackT1(buf []byte) {
+	var t [n]uint32
+	unpackBits(&t, buf, 10)
+	for i := range a {
+		a[i] = int32(t[i])
+	}
+}
+
+// packW1 packs the high-order part of w with the parameter set's bit width.
+func (a *poly) packW1(p *Params, buf []byte) {
+	var t [n]uint32
+	for i := range a {
+		t[i] = uint32(a[i])
+	}
+	packBits(buf, &t, p.w1Bits())
+}
+
+// packPublicKey encodes rho || t1.
+func packPublicKey(p *Params, rho []byte, t1 polyvec) []byte {
+	buf := make([]byte, p.PublicKeySize)
+	copy(buf, rho[:SeedSize])
+	for i := range t1 {
+		t1[i].packT1(buf[SeedSize+i*polyT1PackedSize:])
+	}
+	return buf
+}
+
+// unpackPublicKey decodes rho || t1.
+func unpackPublicKey(p *Params, buf []byte) (rho []byte, t1 polyvec) {
+	rho = buf[:SeedSize]
+	t1 = newPolyvec(p.K)
+	for i := range t1 {
+		t1[i].unpackT1(buf[SeedSize+i*polyT1PackedSize:])
+	}
+	return rho, t1
+}
+
+// packPrivateKey encodes rho || key || tr || s1 || s2 || t0.
+func packPrivateKey(p *Params, rho, key, tr []byte, t0, s1, s2 polyvec) []byte {
+	buf := make([]byte, p.PrivateKeySize)
+	off := copy(buf, rho[:SeedSize])
+	off += copy(buf[off:], key[:SeedSize])
+	off += copy(buf[off:], tr[:trSize])
+
+	etaSize := p.polyEtaPackedSize()
+	for i := range s1 {
+		s1[i].packEta(p, buf[off:])
+		off += etaSize
+	}
+	for i := range s2 {
+		s2[i].packEta(p, buf[off:])
+		off += etaSize
+	}
+	for i := range t0 {
+		t0[i].packT0(buf[off:])
+		off += polyT0PackedSize
+	}
+	return buf
+}
+
+// unpackPrivateKey decodes rho || key || tr || s1 || s2 || t0.
+func unpackPrivateKey(p *Params, buf []byte) (rho, key, tr []byte, t0, s1, s2 polyvec) {
+	rho = buf[:SeedSize]
+	key = buf[SeedSize : 2*SeedSize]
+	tr = buf[2*SeedSize : 2*SeedSize+trSize]
+	off := 2*SeedSize + trSize
+
+	etaSize := p.polyEtaPackedSize()
+	s1 = newPolyvec(p.L)
+	for i := range s1 {
+		s1[i].unpackEta(p, buf[off:])
+		off += etaSize
+	}
+	s2 = newPolyvec(p.K)
+	for i := range s2 {
+		s2[i].unpackEta(p, buf[off:])
+		off += etaSize
+	}
+	t0 = newPolyvec(p.K)
+	for i := range t0 {
+		t0[i].unpackT0(buf[off:])
+		off += polyT0PackedSize
+	}
+	return rho, key, tr, t0, s1, s2
+}
+
+// packSignature encodes c~ || z || h into sig, which must be zeroed and of
+// the parameter set's signature size.
+func packSignature(p *Params, sig, ctilde []byte, z, h polyvec) {
+	off := copy(sig, ctilde[:p.Lambda])
+
+	zSize := p.polyZPackedSize()
+	for i := range z {
+		z[i].packZ(p, sig[off:])
+		off += zSize
+	}
+	// Encode the hint as the indices of the set coefficients, followed by
+	// the running count of indices after each polynomial
+	k := 0
+	for i := range h {
+		for j := 0; j < n; j++ {
+			if h[i][j] != 0 {
+				sig[off+k] = byte(j)
+				k++
+			}
+		}
+		sig[off+p.Omega+i] = byte(k)
+	}
+}
+
+// unpackSignature decodes c~ || z || h, rejecting hints that are not in
+// canonical form to make signatures strongly unforgeable.
+func unpackSignature(p *Params, sig []byte) (ctilde []byte, z, h polyvec, ok bool) {
+	ctilde = sig[:p.Lambda]
+	off := p.Lambda
+
+	zSize := p.polyZPackedSize()
+	z = newPolyvec(p.L)
+	for i := range z {
+		z[i].unpackZ(p, sig[off:])
+		off += zSize
+	}
+	h = newPolyvec(p.K)
+	hints := sig[off:]
+
+	k := 0
+	for i := range h {
+		limit := int(hints[p.Omega+i])
+		if limit < k || limit > p.Omega {
+			return nil, nil, nil, false
+		}
+		for j := k; j < limit; j++ {
+			// Coefficient indices must be strictly increasing
+			if j > k && hints[j] <= hints[j-1] {
+				return nil, nil, nil, false
+			}
+			h[i][hints[j]] = 1
+		}
+		k = limit
+	}
+	// Unused index slots must be zero
+	for j := k; j < p.Omega; j++ {
+		if hints[j] != 0 {
+			return nil, nil, nil, false
+		}
+	}
+	return ctilde, z, h, true
+}
@@ -15,15 +15,104 @@
 
 package dilithium
 
+// Constants shared by every ML-DSA parameter set (FIPS 204, section 4).
+const (
+	n = 256     // Number of coefficients in a polynomial
+	q = 8380417 // Modulus 2^23 - 2^13 + 1
+	d = 13      // Dropped bits from t
+
+	SeedSize = 32 // Size of the key generation seed (xi)
+	trSize   = 64 // Size of the public key hash tr
+	crhSize  = 64 // Size of mu and rho'
+	rndSize  = 32 // Size of the per-signature randomness
+
+	polyT1PackedSize = 320 // 256 coefficients of 10 bits
+	polyT0PackedSize = 416 // 256 coefficients of 13 bits
+
+	// MaxContextSize is the longest context string accepted by the signing
+	// and verification routines.
+	MaxContextSize = 255
+)
+
+// Params describes a single ML-DSA (Dilithium) parameter set.
 type Params struct {
-	N       int
-	Q       int
-	SeedLen int
-	L       int
-	K       int
-	Eta     int
-	Beta    int
-	Omega   int
-	Gamma1  int
-	Gamma2  int
+	Name    string // Human readable name of the parameter set
+	N       int    // Number of coefficients per polynomial
+	Q       int    // Modulus
+	SeedLen int    // Length of the public matrix seed rho
+	L       int    // Number of polynomials in the secret vector s1
+	K       int    // Number of polynomials in the secret vector s2 and t
+	Eta     int    // Bound on the secret key coefficients
+	Tau     int    // Number of +-1 coefficients in the challenge
+	Beta    int    // Tau * Eta
+	Omega   int    // Maximum number of hint bits in a signature
+	Gamma1  int    // Coefficient range of the masking vector y
+	Gamma2  int    // Low-order rounding range
+	Lambda  int    // Length of the commitment hash c~ in bytes
+
+	PublicKeySize  int // Length of a packed public key
+	PrivateKeySize int // Length of a packed private key
+	SignatureSize  int // Length of a packed signature
 }
+
+var (
+	// Dilithium2 is the ML-DSA-44 parameter set (NIST security category 2).
+	Dilithium2 = newParams("Dilithium2", 4, 4, 2, 39, 1<<17, (q-1)/88, 80, 32)
+
+	// Dilithium3 is the ML-DSA-65 parameter set (NIST security category 3).
+	Dilithium3 = newParams("Dilithium3", 6, 5, 4, 49, 1<<19, (q-1)/32, 55, 48)
+
+	// Dilithium5 is the ML-DSA-87 parameter set (NIST security category 5).
+	Dilithium5 = newParams("Dilithium5", 8, 7, 2, 60, 1<<19, (q-1)/32, 75, 64)
+)
+
+// newParams assembles a parameter set and derives the encoded object sizes.
+func newParams(name string, k, l, eta, tau, gamma1, gamma2, omega, lambda int) *Params {
+	p := &Params{
+		Name:    name,
+		N:       n,
+		Q:       q,
+		SeedLen: SeedSize,
+		L:       l,
+		K:       k,
+		Eta:     eta,
+		Tau:     tau,
+		Beta:    tau * eta,
+		Omega:   omega,
+		Gamma1:  gamma1,
+		Gamma2:  gamma2,
+		Lambda:  lambda,
+	}
+	p.PublicKeySize = SeedSize + k*polyT1PackedSize
+	p.PrivateKeySize = 2*SeedSize + trSize + (l+k)*p.polyEtaPackedSize() + k*polyT0PackedSize
+	p.SignatureSize = lambda + l*p.polyZPackedSize() + omega + k
+	return p
+}
+
+// etaBits returns the bit width of a packed secret key coefficient.
+func (p *Params) etaBits() int {
+	if p.Eta == 2 {
+		return 3
+	}
+	return 4
+}
+
+// gamma1Bits returns the bit width of a packed z coefficient.
+func (p *Params) gamma1Bits() int {
+	if p.Gamma1 == 1<<17 {
+		return 18
+	}
+	return 20
+}
+
+// w1Bits returns the bit width of a packed high-order w1 coefficient.
+func (p *Params) w1Bits() int {
+	if p.Gamma2 == (q-1)/88 {
+		return 6
+	}
+	return 4
+}
+
+func (p *Params) polyEtaPackedSize() int { return n * p.etaBits() / 8 }
+func (p *Params) polyZPackedSize() int   { return n * p.gamma1Bits() / 8 }
+func (p *Params) polyW1PackedSize() int  { return n * p.w1Bits() / 8 }
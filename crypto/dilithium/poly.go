@@ -14,3 +14,267 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+// poly is an element of the ring Z_q[X]/(X^n + 1).
+type poly [n]int32
+
+// reduce brings all coefficients into the range [-6283008, 6283008].
+func (a *poly) reduce() {
+	for i := range a {
+		a[i] = reduce32(a[i])
+	}
+}
+
+// caddq adds q to every negative coefficient.
+func (a *poly) caddq() {
+	for i := range a {
+		a[i] = caddq(a[i])
+	}
+}
+
+// add sets a = b + c without modular reduction.
+func (a *poly) add(b, c *poly) {
+	for i := range a {
+		a[i] = b[i] + c[i]
+	}
+}
+
+// sub sets a = b - c without modular reduction.
+func (a *poly) sub(b, c *poly) {
+	for i := range a {
+		a[i] = b[i] - c[i]
+	}
+}
+
+// shiftl multiplies every coefficient by 2^d without modular reduction.
+func (a *poly) shiftl() {
+	for i := range a {
+		a[i] <<= d
+	}
+}
+
+// ntt transforms the polynomial into the NTT domain.
+func (a *poly) ntt() {
+	ntt((*[n]int32)(a))
+}
+
+// invnttTomont transforms the polynomial back from the NTT domain and
+// multiplies by the Montgomery factor.
+func (a *poly) invnttTomont() {
+	invnttTomont((*[n]int32)(a))
+}
+
+// pointwiseMontgomery sets a to the pointwise product of b and c in the NTT
+// domain, scaled by 2^(-32).
+func (a *poly) pointwiseMontgomery(b, c *poly) {
+	for i := range a {
+		a[i] = montgomeryReduce(int64(b[i]) * int64(c[i]))
+	}
+}
+
+// power2round splits every standard representative coefficient a into
+// a1*2^d + a0 with -2^(d-1) < a0 <= 2^(d-1), storing a1 in a1 and a0 in a0.
+func (a *poly) power2round(a1, a0 *poly) {
+	for i := range a {
+		c := a[i]
+		a1[i] = (c + (1 << (d - 1)) - 1) >> d
+		a0[i] = c - (a1[i] << d)
+	}
+}
+
+// decompose splits the standard representative a into a1*2*gamma2 + a0 with
+// -gamma2 < a0 <= gamma2, except for the corner case a1 = (q-1)/(2*gamma2)
+// where a1 is set to zero and a0 is reduced by one. The computation does not
+// branch on the value of a.
+func decompose(p *Params, a int32) (a1, a0 int32) {
+	a1 = (a + 127) >> 7
+	if p.Gamma2 == (q-1)/32 {
+		a1 = (a1*1025 + (1 << 21)) >> 22
+		a1 &= 15
+	} else {
+		a1 = (a1*11275 + (1 << 23)) >> 24
+		a1 ^= ((43 - a1) >> 31) & a1
+	}
+	a0 = a - a1*2*int32(p.Gamma2)
+	a0 -= (((q-1)/2 - a0) >> 31) & q
+	return a1, a0
+}
+
+// decompose applies decompose to every coefficient.
+func (a *poly) decompose(p *Params, a1, a0 *poly) {
+	for i := range a {
+		a1[i], a0[i] = decompose(p, a[i])
+	}
+}
+
+// makeHint sets h to the hint bits indicating whether the low part a0
+// overflows into the high part a1, returning the number of set bits.
+func (h *poly) makeHint(p *Params, a0, a1 *poly) int {
+	var (
+		gamma2 = int32(p.Gamma2)
+		count  int
+	)
+	for i := range h {
+		if a0[i] > gamma2 || a0[i] < -gamma2 || (a0[i] == -gamma2 && a1[i] != 0) {
+			h[i] = 1
+			count++
+		} else {
+			h[i] = 0
+		}
+	}
+	return count
+}
+
+// useHint corrects the high bits of a according to the hint polynomial h.
+func (a *poly) useHint(p *Params, b, h *poly) {
+	for i := range a {
+		a1, a0 := decompose(p, b[i])
+		switch {
+		case h[i] == 0:
+			a[i] = a1
+		case p.Gamma2 == (q-1)/32:
+			if a0 > 0 {
+				a[i] = (a1 + 1) & 15
+			} else {
+				a[i] = (a1 - 1) & 15
+			}
+		default:
+			if a0 > 0 {
+				if a1 == 43 {
+					a[i] = 0
+				} else {
+					a[i] = a1 + 1
+				}
+			} else {
+				if a1 == 0 {
+					a[i] = 43
+				} else {
+					a[i] = a1 - 1
+				}
+			}
+		}
+	}
+}
+
+// exceedsNorm reports whether the infinity norm of a reduced polynomial is
+// at least bound. Only the index of a violating coefficient may leak through
+// timing, never its sign.
+func (a *poly) exceedsNorm(bound int32) bool {
+	if bound > (q-1)/8 {
+		return true
+	}
+	for i := range a {
+		t := a[i] >> 31
+		t = a[i] - (t & (2 * a[i]))
+		if t >= bound {
+			return true
+		}
+	}
+	return false
+}
+
+// uniform samples a polynomial with uniformly random coefficients in [0, q)
+// by rejection sampling on the SHAKE128 stream of seed || nonce.
+func (a *poly) uniform(seed []byte, nonce uint16) {
+	var (
+		h   = stream128(seed, nonce)
+		buf [shake128Rate]byte
+	)
+	for ctr := 0; ctr < n; {
+		h.Read(buf[:])
+		for pos := 0; ctr < n && pos+3 <= len(buf); pos += 3 {
+			t := uint32(buf[pos]) | uint32(buf[pos+1])<<8 | uint32(buf[pos+2])<<16
+			t &= 0x7fffff
+			if t < q {
+				a[ctr] = int32(t)
+				ctr++
+			}
+		}
+	}
+}
+
+// uniformEta samples a polynomial with coefficients in [-eta, eta] by
+// rejection sampling on the SHAKE256 stream of seed || nonce.
+func (a *poly) uniformEta(p *Params, seed []byte, nonce uint16) {
+	var (
+		h   = stream256(seed, nonce)
+		buf [shake256Rate]byte
+		eta = int32(p.Eta)
+	)
+	for ctr := 0; ctr < n; {
+		h.Read(buf[:])
+		for pos := 0; ctr < n && pos < len(buf); pos++ {
+			t0 := int32(buf[pos] & 0x0f)
+			t1 := int32(buf[pos] >> 4)
+			if eta == 2 {
+				if t0 < 15 {
+					t0 = t0 - (205*t0>>10)*5
+					a[ctr] = 2 - t0
+					ctr++
+				}
+				if t1 < 15 && ctr < n {
+					t1 = t1 - (205*t1>>10)*5
+					a[ctr] = 2 - t1
+					ctr++
+				}
+			} else {
+				if t0 < 9 {
+					a[ctr] = 4 - t0
+					ctr++
+				}
+				if t1 < 9 && ctr < n {
+					a[ctr] = 4 - t1
+					ctr++
+				}
+			}
+		}
+	}
+}
+
+// uniformGamma1 samples a polynomial with coefficients in (-gamma1, gamma1]
+// from the SHAKE256 stream of seed || nonce.
+func (a *poly) uniformGamma1(p *Params, seed []byte, nonce uint16) {
+	buf := make([]byte, p.polyZPackedSize())
+	stream256(seed, nonce).Read(buf)
+	a.unpackZ(p, buf)
+}
+
+// challenge derives the sparse challenge polynomial with exactly tau
+// coefficients in {-1, 1} from the commitment hash c~.
+func (c *poly) challenge(p *Params, seed []byte) {
+	var (
+		h   = sha3.NewShake256()
+		buf [shake256Rate]byte
+	)
+	h.Write(seed)
+	h.Read(buf[:])
+
+	var signs uint64
+	for i := 0; i < 8; i++ {
+		signs |= uint64(buf[i]) << (8 * i)
+	}
+	pos := 8
+
+	*c = poly{}
+	for i := n - p.Tau; i < n; i++ {
+		var b int
+		for {
+			if pos >= len(buf) {
+				h.Read(buf[:])
+				pos = 0
+			}
+			b = int(buf[pos])
+			pos++
+			if b <= i {
+				break
+			}
+		}
+		c[i] = c[b]
+		c[b] = 1 - 2*int32(signs&1)
+		signs >>= 1
+	}
+}
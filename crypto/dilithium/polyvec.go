@@ -14,3 +14,128 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+// polyvec is a vector of polynomials, of length L or K depending on use.
+type polyvec []poly
+
+// newPolyvec allocates a zero vector of the given length.
+func newPolyvec(length int) polyvec {
+	return make(polyvec, length)
+}
+
+// copy returns a deep copy of the vector.
+func (v polyvec) copy() polyvec {
+	cpy := make(polyvec, len(v))
+	copy(cpy, v)
+	return cpy
+}
+
+func (v polyvec) reduce() {
+	for i := range v {
+		v[i].reduce()
+	}
+}
+
+func (v polyvec) caddq() {
+	for i := range v {
+		v[i].caddq()
+	}
+}
+
+// add sets v = a + b.
+func (v polyvec) add(a, b polyvec) {
+	for i := range v {
+		v[i].add(&a[i], &b[i])
+	}
+}
+
+// sub sets v = a - b.
+func (v polyvec) sub(a, b polyvec) {
+	for i := range v {
+		v[i].sub(&a[i], &b[i])
+	}
+}
+
+func (v polyvec) shiftl() {
+	for i := range v {
+		v[i].shiftl()
+	}
+}
+
+func (v polyvec) ntt() {
+	for i := range v {
+		v[i].ntt()
+	}
+}
+
+func (v polyvec) invnttTomont() {
+	for i := range v {
+		v[i].invnttTomont()
+	}
+}
+
+// pointwisePolyMontgomery multiplies every element of a by the single
+// polynomial c in the NTT domain.
+func (v polyvec) pointwisePolyMontgomery(c *poly, a polyvec) {
+	for i := range v {
+		v[i].pointwiseMontgomery(c, &a[i])
+	}
+}
+
+// pointwiseAccMontgomery sets w to the inner product of u and v in the NTT
+// domain, scaled by 2^(-32).
+func pointwiseAccMontgomery(w *poly, u, v polyvec) {
+	var t poly
+	w.pointwiseMontgomery(&u[0], &v[0])
+	for i := 1; i < len(u); i++ {
+		t.pointwiseMontgomery(&u[i], &v[i])
+		w.add(w, &t)
+	}
+}
+
+// exceedsNorm reports whether any polynomial's infinity norm is at least bound.
+func (v polyvec) exceedsNorm(bound int32) bool {
+	for i := range v {
+		if v[i].exceedsNorm(bound) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v polyvec) power2round(v1, v0 polyvec) {
+	for i := range v {
+		v[i].power2round(&v1[i], &v0[i])
+	}
+}
+
+func (v polyvec) decompose(p *Params, v1, v0 polyvec) {
+	for i := range v {
+		v[i].decompose(p, &v1[i], &v0[i])
+	}
+}
+
+// makeHint sets h to the hint vector and returns the total number of set bits.
+func (h polyvec) makeHint(p *Params, v0, v1 polyvec) int {
+	count := 0
+	for i := range h {
+		count += h[i].makeHint(p, &v0[i], &v1[i])
+	}
+	return count
+}
+
+func (v polyvec) useHint(p *Params, u, h polyvec) {
+	for i := range v {
+		v[i].useHint(p, &u[i], &h[i])
+	}
+}
+
+// packW1 packs the high-order vector w1 into buf.
+func (v polyvec) packW1(p *Params) []byte {
+	size := p.polyW1PackedSize()
+	buf := make([]byte, len(v)*size)
+	for i := range v {
+		v[i].packW1(p, buf[i*size:])
+	}
+	return buf
+}
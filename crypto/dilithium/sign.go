@@ -14,3 +14,198 @@
 // If not, see <http://www.gnu.org/licenses/>.
 
 package dilithium
+
+import (
+	"crypto/subtle"
+)
+
+// keygen derives a key pair deterministically from the 32 byte seed xi, as
+// specified by ML-DSA.KeyGen_internal.
+func keygen(p *Params, xi []byte) *PrivateKey {
+	// Expand the seed into rho, rho' and K, binding the parameter set
+	seed := make([]byte, 2*SeedSize+crhSize)
+	shake256(seed, xi, []byte{byte(p.K), byte(p.L)})
+
+	var (
+		rho       = seed[:SeedSize]
+		rhoprime  = seed[SeedSize : SeedSize+crhSize]
+		key       = seed[SeedSize+crhSize:]
+		mat       = expandMatrix(p, rho)
+		s1        = newPolyvec(p.L)
+		s2        = newPolyvec(p.K)
+		t1, t0    = newPolyvec(p.K), newPolyvec(p.K)
+		tr        = make([]byte, trSize)
+		nonce     uint16
+		t         polyvec
+		publicKey []byte
+	)
+	for i := range s1 {
+		s1[i].uniformEta(p, rhoprime, nonce)
+		nonce++
+	}
+	for i := range s2 {
+		s2[i].uniformEta(p, rhoprime, nonce)
+		nonce++
+	}
+	// Compute t = A*s1 + s2 and split it into its high and low bits
+	t = computeT(p, mat, s1, s2)
+	t.power2round(t1, t0)
+
+	publicKey = packPublicKey(p, rho, t1)
+	shake256(tr, publicKey)
+
+	return &PrivateKey{
+		PublicKey: PublicKey{params: p, packed: publicKey},
+		packed:    packPrivateKey(p, rho, key, tr, t0, s1, s2),
+	}
+}
+
+// computeT calculates t = A*s1 + s2 with coefficients in standard
+// representation.
+func computeT(p *Params, mat matrix, s1, s2 polyvec) polyvec {
+	s1hat := s1.copy()
+	s1hat.ntt()
+
+	t := newPolyvec(p.K)
+	mat.mulVec(t, s1hat)
+	t.reduce()
+	t.invnttTomont()
+	t.add(t, s2)
+	t.caddq()
+	return t
+}
+
+// messagePrefix returns the domain separator prepended to the message in
+// pure ML-DSA mode: 0 || len(ctx) || ctx.
+func messagePrefix(ctx []byte) []byte {
+	return append([]byte{0, byte(len(ctx))}, ctx...)
+}
+
+// signInternal implements ML-DSA.Sign_internal over the prefixed message,
+// using rnd as the per-signature randomness.
+func signInternal(priv *PrivateKey, pre, msg, rnd []byte) []byte {
+	p := priv.params
+	rho, key, tr, t0, s1, s2 := unpackPrivateKey(p, priv.packed)
+
+	// Compute mu = CRH(tr || pre || msg) and rho' = CRH(key || rnd || mu)
+	var (
+		mu       = make([]byte, crhSize)
+		rhoprime = make([]byte, crhSize)
+	)
+	shake256(mu, tr, pre, msg)
+	shake256(rhoprime, key, rnd, mu)
+
+	mat := expandMatrix(p, rho)
+	s1.ntt()
+	s2.ntt()
+	t0.ntt()
+
+	var (
+		y      = newPolyvec(p.L)
+		z      = newPolyvec(p.L)
+		w      = newPolyvec(p.K)
+		w1, w0 = newPolyvec(p.K), newPolyvec(p.K)
+		h      = newPolyvec(p.K)
+		cp     poly
+		ctilde = make([]byte, p.Lambda)
+		gamma1 = int32(p.Gamma1)
+		gamma2 = int32(p.Gamma2)
+		beta   = int32(p.Beta)
+	)
+	for nonce := 0; ; nonce++ {
+		// Sample the masking vector y and commit to w1 = HighBits(A*y)
+		for i := range y {
+			y[i].uniformGamma1(p, rhoprime, uint16(p.L*nonce+i))
+		}
+		copy(z, y)
+		z.ntt()
+		mat.mulVec(w, z)
+		w.reduce()
+		w.invnttTomont()
+		w.caddq()
+		w.decompose(p, w1, w0)
+
+		shake256(ctilde, mu, w1.packW1(p))
+		cp.challenge(p, ctilde)
+		cp.ntt()
+
+		// Compute z = y + c*s1 and reject if it would leak the secret
+		z.pointwisePolyMontgomery(&cp, s1)
+		z.invnttTomont()
+		z.add(z, y)
+		z.reduce()
+		if z.exceedsNorm(gamma1 - beta) {
+			continue
+		}
+		// Check that subtracting c*s2 does not change the high bits of w
+		h.pointwisePolyMontgomery(&cp, s2)
+		h.invnttTomont()
+		w0.sub(w0, h)
+		w0.reduce()
+		if w0.exceedsNorm(gamma2 - beta) {
+			continue
+		}
+		// Compute the hints for the verifier to recover w1 without t0
+		h.pointwisePolyMontgomery(&cp, t0)
+		h.invnttTomont()
+		h.reduce()
+		if h.exceedsNorm(gamma2) {
+			continue
+		}
+		w0.add(w0, h)
+		if h.makeHint(p, w0, w1) > p.Omega {
+			continue
+		}
+		sig := make([]byte, p.SignatureSize)
+		packSignature(p, sig, ctilde, z, h)
+		return sig
+	}
+}
+
+// verifyInternal implements ML-DSA.Verify_internal over the prefixed message.
+func verifyInternal(pub *PublicKey, pre, msg, sig []byte) bool {
+	p := pub.params
+	if len(sig) != p.SignatureSize {
+		return false
+	}
+	rho, t1 := unpackPublicKey(p, pub.packed)
+	ctilde, z, h, ok := unpackSignature(p, sig)
+	if !ok {
+		return false
+	}
+	if z.exceedsNorm(int32(p.Gamma1 - p.Beta)) {
+		return false
+	}
+	// Compute mu = CRH(H(pk) || pre || msg)
+	var (
+		tr = make([]byte, trSize)
+		mu = make([]byte, crhSize)
+	)
+	shake256(tr, pub.packed)
+	shake256(mu, tr, pre, msg)
+
+	// Reconstruct w1 = UseHint(h, A*z - c*t1*2^d)
+	var cp poly
+	cp.challenge(p, ctilde)
+	cp.ntt()
+
+	mat := expandMatrix(p, rho)
+	z.ntt()
+	w1 := newPolyvec(p.K)
+	mat.mulVec(w1, z)
+
+	t1.shiftl()
+	t1.ntt()
+	t1.pointwisePolyMontgomery(&cp, t1)
+
+	w1.sub(w1, t1)
+	w1.reduce()
+	w1.invnttTomont()
+	w1.caddq()
+	w1.useHint(p, w1, h)
+
+	// The signature is valid if the commitment hash matches
+	c2 := make([]byte, p.Lambda)
+	shake256(c2, mu, w1.packW1(p))
+	return subtle.ConstantTimeCompare(ctilde, c2) == 1
+}
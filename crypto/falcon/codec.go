@@ -0,0 +1,236 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+// encodePublicKey packs h with 14 bits per coefficient, most significant
+// bit first, behind the public key header.
+func encodePublicKey(h *[n]uint32) []byte {
+	out := make([]byte, 1, PublicKeySize)
+	out[0] = publicKeyHeader
+
+	var acc uint32
+	var bits uint
+	for _, c := range h {
+		acc = acc<<14 | c
+		bits += 14
+		for bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+	return out
+}
+
+// decodePublicKey unpacks a public key, rejecting coefficients >= q.
+func decodePublicKey(b []byte) (*[n]uint32, bool) {
+	if len(b) != PublicKeySize || b[0] != publicKeyHeader {
+		return nil, false
+	}
+	var (
+		h    [n]uint32
+		acc  uint32
+		bits uint
+		i    int
+	)
+	for _, x := range b[1:] {
+		acc = acc<<8 | uint32(x)
+		bits += 8
+		if bits >= 14 {
+			bits -= 14
+			if h[i] = acc >> bits & 0x3fff; h[i] >= q {
+				return nil, false
+			}
+			i++
+		}
+	}
+	return &h, true
+}
+
+// encodeTrim appends the coefficients of a with a signed fixed width encoding.
+func encodeTrim(out []byte, a []int16, width uint) []byte {
+	var acc uint32
+	var bits uint
+	mask := uint32(1)<<width - 1
+	for _, c := range a {
+		acc = acc<<width | uint32(c)&mask
+		bits += width
+		for bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+	return out
+}
+
+// decodeTrim decodes n coefficients of the given signed width from b, rejecting
+// the most negative value.
+func decodeTrim(b []byte, width uint) ([]int16, bool) {
+	var (
+		a    = make([]int16, 0, n)
+		acc  uint32
+		bits uint
+	)
+	mask := uint32(1)<<width - 1
+	for _, x := range b {
+		acc = acc<<8 | uint32(x)
+		bits += 8
+		for bits >= width {
+			bits -= width
+			v := int32(acc >> bits & mask)
+			if v >= 1<<(width-1) {
+				v -= 1 << width
+			}
+			if v == -(1 << (width - 1)) {
+				return nil, false
+			}
+			a = append(a, int16(v))
+		}
+	}
+	return a, len(a) == n
+}
+
+// encodePrivateKey packs f, g and F behind the private key header.
+func encodePrivateKey(f, g, F []int16) []byte {
+	out := make([]byte, 1, PrivateKeySize)
+	out[0] = privateKeyHeader
+	out = encodeTrim(out, f, fgBits)
+	out = encodeTrim(out, g, fgBits)
+	return encodeTrim(out, F, bigFGBits)
+}
+
+// decodePrivateKey unpacks f, g and F from a private key encoding.
+func decodePrivateKey(b []byte) (f, g, F []int16, ok bool) {
+	if len(b) != PrivateKeySize || b[0] != privateKeyHeader {
+		return nil, nil, nil, false
+	}
+	fgLen := n * fgBits / 8
+	if f, ok = decodeTrim(b[1:1+fgLen], fgBits); !ok {
+		return nil, nil, nil, false
+	}
+	if g, ok = decodeTrim(b[1+fgLen:1+2*fgLen], fgBits); !ok {
+		return nil, nil, nil, false
+	}
+	if F, ok = decodeTrim(b[1+2*fgLen:], bigFGBits); !ok {
+		return nil, nil, nil, false
+	}
+	return f, g, F, true
+}
+
+// compress encodes s using the Falcon compressed format: per coefficient a
+// sign bit, the 7 low bits of the absolute value and the remaining high bits
+// in unary. It returns false if the encoding exceeds size bytes.
+func compress(s []int16, size int) ([]byte, bool) {
+	out := make([]byte, size)
+	var (
+		acc  uint32
+		bits uint
+		pos  int
+	)
+	flush := func() bool {
+		for bits >= 8 {
+			if pos >= size {
+				return false
+			}
+			bits -= 8
+			out[pos] = byte(acc >> bits)
+			pos++
+		}
+		return true
+	}
+	for _, c := range s {
+		if c < -2047 || c > 2047 {
+			return nil, false
+		}
+		var sign, v uint32
+		if c < 0 {
+			sign, v = 1, uint32(-c)
+		} else {
+			v = uint32(c)
+		}
+		acc = acc<<8 | sign<<7 | v&0x7f
+		bits += 8
+		if !flush() {
+			return nil, false
+		}
+		high := uint(v >> 7)
+		acc = acc<<(high+1) | 1
+		bits += high + 1
+		if !flush() {
+			return nil, false
+		}
+	}
+	if bits > 0 {
+		if pos >= size {
+			return nil, false
+		}
+		out[pos] = byte(acc << (8 - bits))
+		pos++
+	}
+	return out[:pos], true
+}
+
+// decompress decodes n coefficients from b, requiring the canonical encoding
+// to span the whole buffer with zero padding bits in the last byte.
+func decompress(b []byte) ([]int16, bool) {
+	var (
+		s    = make([]int16, n)
+		acc  uint32
+		bits uint
+		pos  int
+	)
+	for i := range s {
+		// Sign bit and low bits
+		if pos >= len(b) {
+			return nil, false
+		}
+		acc = acc<<8 | uint32(b[pos])
+		pos++
+		w := acc >> bits
+		sign, v := w&0x80, w&0x7f
+
+		// High bits in unary
+		for {
+			if bits == 0 {
+				if pos >= len(b) {
+					return nil, false
+				}
+				acc = acc<<8 | uint32(b[pos])
+				pos++
+				bits = 8
+			}
+			bits--
+			if acc>>bits&1 != 0 {
+				break
+			}
+			if v += 128; v > 2047 {
+				return nil, false
+			}
+		}
+		if sign != 0 && v == 0 {
+			return nil, false
+		}
+		if sign != 0 {
+			s[i] = -int16(v)
+		} else {
+			s[i] = int16(v)
+		}
+		acc &= 1<<bits - 1
+	}
+	if acc != 0 || pos != len(b) {
+		return nil, false
+	}
+	return s, true
+}
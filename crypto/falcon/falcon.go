@@ -0,0 +1,220 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+// Package falcon implements the Falcon-512 post-quantum digital signature
+// scheme (Falcon specification v1.2), using the compressed signature format.
+//
+// Signing relies on floating point arithmetic and is not constant time; keys
+// should only be used for signing on hosts where timing side channels are
+// not a concern. Verification uses integer arithmetic only.
+package falcon
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// ErrInvalidPublicKey is returned if a public key encoding is malformed.
+	ErrInvalidPublicKey = errors.New("falcon: invalid public key")
+
+	// ErrInvalidPrivateKey is returned if a private key encoding is malformed.
+	ErrInvalidPrivateKey = errors.New("falcon: invalid private key")
+)
+
+// seedSize is the length of the seed fed into the sampler PRNG.
+const seedSize = 56
+
+// PublicKey is a Falcon-512 verification key.
+type PublicKey struct {
+	h      *[n]uint32
+	packed []byte
+}
+
+// PrivateKey is a Falcon-512 signing key along with its public key.
+type PrivateKey struct {
+	PublicKey
+	f, g, F, G []int16
+
+	expandOnce sync.Once
+	expanded   *expandedKey
+}
+
+// GenerateKey creates a new key pair, drawing the sampler seed from rand. If
+// rand is nil, crypto/rand is used.
+func GenerateKey(random io.Reader) (*PrivateKey, error) {
+	s, err := newSampler(random)
+	if err != nil {
+		return nil, err
+	}
+	f, g, F, G := keygen(s)
+	return newPrivateKey(f, g, F, G)
+}
+
+// newSampler creates a sampler whose stream is seeded from random, falling
+// back to crypto/rand if random is nil.
+func newSampler(random io.Reader) (*sampler, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	seed := make([]byte, seedSize)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, err
+	}
+	h := sha3.NewShake256()
+	h.Write(seed)
+	return &sampler{rng: h}, nil
+}
+
+// newPrivateKey assembles a private key from its NTRU basis, deriving the
+// public key h = g/f mod q.
+func newPrivateKey(f, g, F, G []int16) (*PrivateKey, error) {
+	finv, ok := invertModQ(f)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	gq := toModQ(g)
+	ntt(gq)
+	for i := range gq {
+		gq[i] = gq[i] * finv[i] % q
+	}
+	invntt(gq)
+
+	return &PrivateKey{
+		PublicKey: PublicKey{h: gq, packed: encodePublicKey(gq)},
+		f:         f,
+		g:         g,
+		F:         F,
+		G:         G,
+	}, nil
+}
+
+// UnmarshalPublicKey decodes an encoded public key.
+func UnmarshalPublicKey(b []byte) (*PublicKey, error) {
+	h, ok := decodePublicKey(b)
+	if !ok {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{h: h, packed: append([]byte(nil), b...)}, nil
+}
+
+// UnmarshalPrivateKey decodes an encoded private key, recomputing G from the
+// NTRU equation fG - gF = q and the matching public key.
+func UnmarshalPrivateKey(b []byte) (*PrivateKey, error) {
+	f, g, F, ok := decodePrivateKey(b)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	finv, ok := invertModQ(f)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	// G = g*F/f mod q, which must be short for a valid key
+	gq, Fq := toModQ(g), toModQ(F)
+	ntt(gq)
+	ntt(Fq)
+	for i := range gq {
+		gq[i] = gq[i] * Fq[i] % q * finv[i] % q
+	}
+	invntt(gq)
+
+	G := make([]int16, n)
+	for i, c := range gq {
+		v := center(c)
+		if v < -(1<<(bigFGBits-1)-1) || v > 1<<(bigFGBits-1)-1 {
+			return nil, ErrInvalidPrivateKey
+		}
+		G[i] = int16(v)
+	}
+	return newPrivateKey(f, g, F, G)
+}
+
+// Bytes returns the encoding of the public key.
+func (pub *PublicKey) Bytes() []byte { return append([]byte(nil), pub.packed...) }
+
+// Equal reports whether pub and other encode the same key.
+func (pub *PublicKey) Equal(other *PublicKey) bool {
+	return string(pub.packed) == string(other.packed)
+}
+
+// Public returns the public key corresponding to priv.
+func (priv *PrivateKey) Public() *PublicKey { return &priv.PublicKey }
+
+// Bytes returns the encoding of the private key.
+func (priv *PrivateKey) Bytes() []byte { return encodePrivateKey(priv.f, priv.g, priv.F) }
+
+// expand returns the signing tree of the key, computing it on first use.
+func (priv *PrivateKey) expand() *expandedKey {
+	priv.expandOnce.Do(func() {
+		priv.expanded = expandKey(priv.f, priv.g, priv.F, priv.G)
+	})
+	return priv.expanded
+}
+
+// Sign creates a randomised signature over msg. The returned signature is
+// the header byte, the nonce and the compressed vector s1, and is at most
+// MaxSignatureSize bytes long.
+func Sign(priv *PrivateKey, msg []byte) ([]byte, error) {
+	nonce := make([]byte, NonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	s, err := newSampler(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		ek = priv.expand()
+		c  = hashToPoint(nonce, msg)
+	)
+	for {
+		s0, s1 := ek.samplePreimage(s, c)
+		if sqNorm(s0, s1) > sigBound {
+			continue
+		}
+		enc, ok := compress(s1, MaxSignatureSize-1-NonceSize)
+		if !ok {
+			continue
+		}
+		sig := make([]byte, 0, 1+NonceSize+len(enc))
+		sig = append(sig, signatureHeader)
+		sig = append(sig, nonce...)
+		return append(sig, enc...), nil
+	}
+}
+
+// Verify checks a signature over msg.
+func Verify(pub *PublicKey, msg, sig []byte) bool {
+	if len(sig) <= 1+NonceSize || len(sig) > MaxSignatureSize || sig[0] != signatureHeader {
+		return false
+	}
+	s1, ok := decompress(sig[1+NonceSize:])
+	if !ok {
+		return false
+	}
+	c := hashToPoint(sig[1:1+NonceSize], msg)
+
+	// s0 = c - s1*h mod q
+	s1h := mulModQ(toModQ(s1), pub.h)
+	s0 := make([]int16, n)
+	for i := range s0 {
+		s0[i] = int16(center((uint32(c[i]) + q - s1h[i]) % q))
+	}
+	return sqNorm(s0, s1) <= sigBound
+}
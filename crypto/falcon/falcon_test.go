@@ -0,0 +1,166 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func fromHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// testSeed returns the sampler seed of the i-th known answer test key.
+func testSeed(i int) []byte {
+	return bytes.Repeat([]byte{byte(i)}, seedSize)
+}
+
+// TestHashToPoint checks HashToPoint against values computed independently from
+// the Falcon specification, with the coefficient hash being SHA2-256 over the
+// big endian 16 bit coefficients.
+func TestHashToPoint(t *testing.T) {
+	c := hashToPoint(bytes.Repeat([]byte{0x11}, NonceSize), []byte("abc"))
+
+	want := []int16{8801, 8058, 1703, 8560, 5102, 5103, 6802, 187}
+	for i, v := range want {
+		if c[i] != v {
+			t.Fatalf("coefficient %d mismatch: have %d, want %d", i, c[i], v)
+		}
+	}
+	h := sha256.New()
+	for _, v := range c {
+		h.Write([]byte{byte(v >> 8), byte(v)})
+	}
+	if have, want := hex.EncodeToString(h.Sum(nil)), "2610db321990580c01a599dd42fbd951d7b0ac86199064fd27a1fb1a1ab94fbb"; have != want {
+		t.Fatalf("coefficient hash mismatch: have %s, want %s", have, want)
+	}
+}
+
+// TestKeyGenKAT checks that keys are derived identically from their seed on
+// every architecture, and that they solve the NTRU equation. The key hash is
+// SHA2-256(pk || sk).
+func TestKeyGenKAT(t *testing.T) {
+	tests := []string{
+		"c627196bd4064a1b5ef69a9bd7c3a57c16db1cd826c3fb9490bec76b5b2c4f46",
+		"2349c4a87c0e125c8caeffa1a3b401f1e33d269e8b7158198db04d37ce652541",
+		"9cc3be55216e5f20400215b00acd019c5b0efedc45425e5f75c853f168299f33",
+	}
+	for i, want := range tests {
+		priv, err := GenerateKey(bytes.NewReader(testSeed(i)))
+		if err != nil {
+			t.Fatalf("key %d: failed to generate: %v", i, err)
+		}
+		h := sha256.New()
+		h.Write(priv.Public().Bytes())
+		h.Write(priv.Bytes())
+		if have := hex.EncodeToString(h.Sum(nil)); have != want {
+			t.Errorf("key %d: hash mismatch: have %s, want %s", i, have, want)
+		}
+		// f*G - g*F = q
+		fG := bigPolyFromInts(priv.f).mul(bigPolyFromInts(priv.G))
+		gF := bigPolyFromInts(priv.g).mul(bigPolyFromInts(priv.F))
+		for j := range fG {
+			want := int64(0)
+			if j == 0 {
+				want = q
+			}
+			if d := new(big.Int).Sub(fG[j], gF[j]); !d.IsInt64() || d.Int64() != want {
+				t.Fatalf("key %d: NTRU equation violated at coefficient %d", i, j)
+			}
+		}
+	}
+}
+
+// TestVerifyKAT checks a fixed signature made by the first known answer test
+// key. The signature was checked independently of this package against the
+// verification equation of the Falcon specification.
+func TestVerifyKAT(t *testing.T) {
+	priv, err := GenerateKey(bytes.NewReader(testSeed(0)))
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pub, err := UnmarshalPublicKey(priv.Public().Bytes())
+	if err != nil {
+		t.Fatalf("failed to decode public key: %v", err)
+	}
+	msg := []byte("ixios falcon-512 known answer test")
+	sig := fromHex(
+		"39cf4c0e41d5568b6520e20882898f913e077cdefe45a181eb126ec9479c45fd2b7265807870b7b7f95399aa060aa55b" +
+			"ccecf3c89dd9d08083b594b06b50a37ef47437ef955e90c1a3702487212c2c5e1c6b0bb08530288d8b3d3881e8fe3528" +
+			"1d37eded755de6148052f3b7aac2b49017ece17b6dedf34585ac9549f7bd9ad210d0196ec43d0a8298f4a138c7cda26f" +
+			"b63d5de0b7bfa7b77115c1a453a8e580bb5c99847736af2cdb5e83530c5145c554470a0a65b2d786e5da701ed6c27942" +
+			"52a9af1a78e17b5de7e339deb99fba3dd7d1f6f7328d24ea3c47bca8b5eb86841ca62950d5fdb227a1e4c9cf9f069f7c" +
+			"760adaf9cafc475112c514a06cf0b47d7e8f15428963bc0ad627210a3eddf9c142ac623cdb56ae03707fa571f8c344b3" +
+			"e95f5d29953c9f6c3f26ff86ffd4b89308da83e18e4a76640a10c63a7feed5920c7f92ef390b1fd9bdd3d7d2237f794a" +
+			"efc76f1254af42d895fed4c9223b9a6d8b9a52f4e4c2a284ffd7bdc70272d2461319ebbad32b5b77e1a9afc83934afca" +
+			"ee81a8847150a075e027a25a47dd8f425b9060a09bfa0ba1bff964a5c9361233e3e24cb2d141fd926a514617351cc5a6" +
+			"ea66d716ca34cd0f86e970f469cd071da35055959a3103807b5a2bbe1c8ad822254ee3de8afc4e1e77f2c938b5034cd1" +
+			"acf7ac6ef1a95239ec29b159ff347a69c6da2c71040d02e79317f69255914a86d0428874e271e2ca1459cad55b94e932" +
+			"3cba29bd355f04cff9ca50a61214a149646c16bea40555e523ed027a466486f0884137092ad33181e0115cded696f121" +
+			"1c1290d5a8bb82cd9e972f19f85b963fed4a493f4625f15afa21c6206edc1d5c8f3accb890d89fa995f29f3d8b437222" +
+			"04ef392452914350b7e6231290270e5d59aebd76e2fbb167f76b85d2770820",
+	)
+	if !Verify(pub, msg, sig) {
+		t.Fatal("valid signature rejected")
+	}
+	if Verify(pub, []byte("ixios falcon-512 known answer tesT"), sig) {
+		t.Fatal("signature accepted for a different message")
+	}
+	tampered := bytes.Clone(sig)
+	tampered[len(tampered)/2] ^= 0x01
+	if Verify(pub, msg, tampered) {
+		t.Fatal("tampered signature accepted")
+	}
+	if Verify(pub, msg, sig[:len(sig)-1]) {
+		t.Fatal("truncated signature accepted")
+	}
+}
+
+// TestSignVerify checks that signatures of a decoded private key verify.
+func TestSignVerify(t *testing.T) {
+	priv, err := GenerateKey(bytes.NewReader(testSeed(1)))
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	dec, err := UnmarshalPrivateKey(priv.Bytes())
+	if err != nil {
+		t.Fatalf("failed to decode private key: %v", err)
+	}
+	if !dec.Public().Equal(priv.Public()) {
+		t.Fatal("decoded private key has a different public key")
+	}
+	for i := 0; i < 8; i++ {
+		msg := []byte{byte(i)}
+		sig, err := Sign(dec, msg)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		if len(sig) > MaxSignatureSize {
+			t.Fatalf("signature too long: %d bytes", len(sig))
+		}
+		if !Verify(priv.Public(), msg, sig) {
+			t.Fatalf("signature %d rejected", i)
+		}
+	}
+}
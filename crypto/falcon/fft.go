@@ -0,0 +1,239 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// The FFT representation of a real polynomial modulo x^m + 1 holds its values
+// at all m roots of x^m + 1. The roots are ordered so that the entries at 2i
+// and 2i+1 are the two square roots of the i-th root for degree m/2, which
+// makes splitting and merging in the FFT domain a single linear pass.
+
+// Go permits fusing a floating point multiplication and a following addition
+// into a single FMA instruction, which some architectures do and others don't.
+// Key generation must derive the same key from the same seed everywhere, so
+// every product feeding a sum is rounded by an explicit float64 conversion, and
+// complex products and quotients are spelled out instead of left to the compiler
+// and runtime. An explicit conversion forbids fusion, see the Go specification
+// on arithmetic operators.
+
+// cmul returns a * b, computed like the unfused complex multiplication of the
+// Go compiler.
+func cmul(a, b complex128) complex128 {
+	ar, ai, br, bi := real(a), imag(a), real(b), imag(b)
+	return complex(float64(ar*br)-float64(ai*bi), float64(ar*bi)+float64(ai*br))
+}
+
+// cdiv returns a / b for finite a and non-zero b, computed like the complex
+// division of the Go runtime (Smith's algorithm).
+func cdiv(a, b complex128) complex128 {
+	ar, ai, br, bi := real(a), imag(a), real(b), imag(b)
+	if math.Abs(br) >= math.Abs(bi) {
+		ratio := bi / br
+		denom := br + float64(ratio*bi)
+		return complex((ar+float64(ai*ratio))/denom, (ai-float64(ar*ratio))/denom)
+	}
+	ratio := br / bi
+	denom := bi + float64(ratio*br)
+	return complex((float64(ar*ratio)+ai)/denom, (float64(ai*ratio)-ar)/denom)
+}
+
+// roots holds the ordered roots of x^m + 1 for every power of two m <= n,
+// indexed by log2(m).
+var roots [logn + 1][]complex128
+
+func init() {
+	// The roots for degree m are exp(i*pi*j/m) for odd j, tracked by their
+	// numerators j to look them up in a single table of cosines
+	cosines := cosineTable()
+
+	angles := []int{1, -1}
+	for l := 1; l <= logn; l++ {
+		if l > 1 {
+			next := make([]int, 2*len(angles))
+			for i, j := range angles {
+				next[2*i] = j
+				next[2*i+1] = j + 1<<l
+			}
+			angles = next
+		}
+		roots[l] = make([]complex128, len(angles))
+		for i, j := range angles {
+			roots[l][i] = unitRoot(cosines, j<<(logn-l))
+		}
+	}
+}
+
+// cosineTable returns cos(pi*k/n) for 0 <= k <= n/2. The cosines are evaluated
+// from their Taylor series instead of by the math package, whose results are
+// not guaranteed to match across architectures.
+func cosineTable() []float64 {
+	table := make([]float64, n/2+1)
+	for k := range table {
+		x := float64(math.Pi*float64(k)) / n
+		x2 := x * x
+
+		c := 1.0
+		for m := 12; m > 0; m-- {
+			c = 1 - float64(x2*c)/float64((2*m-1)*(2*m))
+		}
+		table[k] = c
+	}
+	return table
+}
+
+// unitRoot returns exp(i*pi*k/n), given the cosines of the first quadrant.
+func unitRoot(cosines []float64, k int) complex128 {
+	k = (k%(2*n) + 2*n) % (2 * n)
+
+	neg := k > n
+	if neg {
+		k = 2*n - k
+	}
+	var re, im float64
+	if k <= n/2 {
+		re, im = cosines[k], cosines[n/2-k]
+	} else {
+		re, im = -cosines[n-k], cosines[k-n/2]
+	}
+	if neg {
+		im = -im
+	}
+	return complex(re, im)
+}
+
+// rootsOf returns the ordered roots of x^m + 1.
+func rootsOf(m int) []complex128 {
+	l := 0
+	for 1<<l < m {
+		l++
+	}
+	return roots[l]
+}
+
+// fft computes the FFT representation of a polynomial of degree m >= 2.
+func fft(f []float64) []complex128 {
+	m := len(f)
+	if m == 2 {
+		return []complex128{complex(f[0], f[1]), complex(f[0], -f[1])}
+	}
+	f0, f1 := make([]float64, m/2), make([]float64, m/2)
+	for i := 0; i < m/2; i++ {
+		f0[i], f1[i] = f[2*i], f[2*i+1]
+	}
+	return mergeFFT(fft(f0), fft(f1))
+}
+
+// ifft is the inverse of fft.
+func ifft(F []complex128) []float64 {
+	m := len(F)
+	if m == 2 {
+		return []float64{real(F[0]), imag(F[0])}
+	}
+	F0, F1 := splitFFT(F)
+	f0, f1 := ifft(F0), ifft(F1)
+
+	f := make([]float64, m)
+	for i := 0; i < m/2; i++ {
+		f[2*i], f[2*i+1] = f0[i], f1[i]
+	}
+	return f
+}
+
+// splitFFT computes the FFT representations of f0 and f1 such that
+// f(x) = f0(x^2) + x*f1(x^2), given the FFT representation of f.
+func splitFFT(F []complex128) ([]complex128, []complex128) {
+	m := len(F)
+	w := rootsOf(m)
+
+	F0, F1 := make([]complex128, m/2), make([]complex128, m/2)
+	for i := 0; i < m/2; i++ {
+		F0[i] = cmul(0.5, F[2*i]+F[2*i+1])
+		F1[i] = cmul(cmul(0.5, F[2*i]-F[2*i+1]), cmplx.Conj(w[2*i]))
+	}
+	return F0, F1
+}
+
+// mergeFFT is the inverse of splitFFT.
+func mergeFFT(F0, F1 []complex128) []complex128 {
+	m := 2 * len(F0)
+	w := rootsOf(m)
+
+	F := make([]complex128, m)
+	for i := 0; i < m/2; i++ {
+		t := cmul(w[2*i], F1[i])
+		F[2*i] = F0[i] + t
+		F[2*i+1] = F0[i] - t
+	}
+	return F
+}
+
+// fftAdd returns a + b in the FFT domain.
+func fftAdd(a, b []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = a[i] + b[i]
+	}
+	return r
+}
+
+// fftSub returns a - b in the FFT domain.
+func fftSub(a, b []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = a[i] - b[i]
+	}
+	return r
+}
+
+// fftMul returns a * b in the FFT domain.
+func fftMul(a, b []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = cmul(a[i], b[i])
+	}
+	return r
+}
+
+// fftDiv returns a / b in the FFT domain.
+func fftDiv(a, b []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = cdiv(a[i], b[i])
+	}
+	return r
+}
+
+// fftAdj returns the Hermitian adjoint of a, i.e. a(1/x), in the FFT domain.
+func fftAdj(a []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = cmplx.Conj(a[i])
+	}
+	return r
+}
+
+// fftNeg returns -a in the FFT domain.
+func fftNeg(a []complex128) []complex128 {
+	r := make([]complex128, len(a))
+	for i := range a {
+		r[i] = -a[i]
+	}
+	return r
+}
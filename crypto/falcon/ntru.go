@@ -0,0 +1,272 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+import (
+	"math"
+	"math/big"
+)
+
+// Key generation follows the NTRUGen algorithm of the Falcon specification:
+// short polynomials f and g are sampled until they admit a short completion
+// (F, G) of the NTRU equation f*G - g*F = q over Z[x]/(x^n + 1).
+
+// bigPoly is a polynomial modulo x^m + 1 with arbitrary precision coefficients.
+type bigPoly []*big.Int
+
+func newBigPoly(m int) bigPoly {
+	p := make(bigPoly, m)
+	for i := range p {
+		p[i] = new(big.Int)
+	}
+	return p
+}
+
+func bigPolyFromInts(a []int16) bigPoly {
+	p := make(bigPoly, len(a))
+	for i, c := range a {
+		p[i] = big.NewInt(int64(c))
+	}
+	return p
+}
+
+// mul returns a * b modulo x^m + 1.
+func (a bigPoly) mul(b bigPoly) bigPoly {
+	m := len(a)
+	r := newBigPoly(m)
+	t := new(big.Int)
+	for i := 0; i < m; i++ {
+		if a[i].Sign() == 0 {
+			continue
+		}
+		for j := 0; j < m; j++ {
+			t.Mul(a[i], b[j])
+			if k := i + j; k < m {
+				r[k].Add(r[k], t)
+			} else {
+				r[k-m].Sub(r[k-m], t)
+			}
+		}
+	}
+	return r
+}
+
+// fieldNorm maps a polynomial modulo x^m + 1 to its field norm modulo
+// x^(m/2) + 1, i.e. a0(x)^2 - x*a1(x)^2 where a(x) = a0(x^2) + x*a1(x^2).
+func (a bigPoly) fieldNorm() bigPoly {
+	h := len(a) / 2
+	ae, ao := make(bigPoly, h), make(bigPoly, h)
+	for i := 0; i < h; i++ {
+		ae[i], ao[i] = a[2*i], a[2*i+1]
+	}
+	ae, ao = ae.mul(ae), ao.mul(ao)
+	for i := 0; i < h-1; i++ {
+		ae[i+1].Sub(ae[i+1], ao[i])
+	}
+	ae[0].Add(ae[0], ao[h-1])
+	return ae
+}
+
+// lift maps a(x) modulo x^(m/2) + 1 to a(x^2) modulo x^m + 1.
+func (a bigPoly) lift() bigPoly {
+	r := newBigPoly(2 * len(a))
+	for i, c := range a {
+		r[2*i].Set(c)
+	}
+	return r
+}
+
+// conjugate returns a(-x).
+func (a bigPoly) conjugate() bigPoly {
+	r := make(bigPoly, len(a))
+	for i, c := range a {
+		r[i] = new(big.Int).Set(c)
+		if i%2 == 1 {
+			r[i].Neg(r[i])
+		}
+	}
+	return r
+}
+
+// maxBits returns the byte aligned bit length of the largest coefficient of
+// any of the given polynomials.
+func maxBits(polys ...bigPoly) int {
+	size := 0
+	for _, p := range polys {
+		for _, c := range p {
+			if b := (c.BitLen() + 7) / 8 * 8; b > size {
+				size = b
+			}
+		}
+	}
+	return size
+}
+
+// approxFFT computes the FFT representation of a after dropping its lowest
+// shift bits, keeping 53 bits of precision.
+func (a bigPoly) approxFFT(shift int) []complex128 {
+	f := make([]float64, len(a))
+	t := new(big.Int)
+	for i, c := range a {
+		f[i] = float64(t.Rsh(c, uint(shift)).Int64())
+	}
+	return fft(f)
+}
+
+// reduce size reduces (F, G) against (f, g) using Babai's round-off.
+func reduce(f, g, F, G bigPoly) {
+	size := max(53, maxBits(f, g))
+	fa, ga := f.approxFFT(size-53), g.approxFFT(size-53)
+	den := fftAdd(fftMul(fa, fftAdj(fa)), fftMul(ga, fftAdj(ga)))
+
+	for {
+		Size := max(53, maxBits(F, G))
+		if Size < size {
+			return
+		}
+		Fa, Ga := F.approxFFT(Size-53), G.approxFFT(Size-53)
+		num := fftAdd(fftMul(Fa, fftAdj(fa)), fftMul(Ga, fftAdj(ga)))
+		kf := ifft(fftDiv(num, den))
+
+		k, zero := make(bigPoly, len(kf)), true
+		for i, c := range kf {
+			k[i] = big.NewInt(int64(math.RoundToEven(c)))
+			zero = zero && k[i].Sign() == 0
+		}
+		if zero {
+			return
+		}
+		fk, gk := f.mul(k), g.mul(k)
+		for i := range F {
+			F[i].Sub(F[i], fk[i].Lsh(fk[i], uint(Size-size)))
+			G[i].Sub(G[i], gk[i].Lsh(gk[i], uint(Size-size)))
+		}
+	}
+}
+
+// ntruSolve finds (F, G) with f*G - g*F = q, returning false if none exists.
+func ntruSolve(f, g bigPoly) (bigPoly, bigPoly, bool) {
+	if len(f) == 1 {
+		u, v := new(big.Int), new(big.Int)
+		d := new(big.Int).GCD(u, v, f[0], g[0])
+		if d.Cmp(big.NewInt(1)) != 0 {
+			return nil, nil, false
+		}
+		Q := big.NewInt(q)
+		return bigPoly{v.Mul(v, Q).Neg(v)}, bigPoly{u.Mul(u, Q)}, true
+	}
+	Fp, Gp, ok := ntruSolve(f.fieldNorm(), g.fieldNorm())
+	if !ok {
+		return nil, nil, false
+	}
+	F := Fp.lift().mul(g.conjugate())
+	G := Gp.lift().mul(f.conjugate())
+	reduce(f, g, F, G)
+	return F, G, true
+}
+
+// genPoly samples a key polynomial whose coefficients follow a discrete
+// Gaussian with standard deviation 1.17*sqrt(q/2n).
+func genPoly(s *sampler) []int16 {
+	f := make([]int16, n)
+	for i := range f {
+		sum := 0
+		for j := 0; j < keygenSamples; j++ {
+			sum += s.sampleZ(0, sigmaKeygen, sigmaKeygen-0.001)
+		}
+		f[i] = int16(sum)
+	}
+	return f
+}
+
+// gsNorm returns the Gram-Schmidt norm of the NTRU basis generated by f and g.
+func gsNorm(f, g []int16) float64 {
+	var sqfg float64
+	for i := range f {
+		sqfg += float64(float64(f[i])*float64(f[i])) + float64(float64(g[i])*float64(g[i]))
+	}
+	ff, gf := fft(toFloats(f)), fft(toFloats(g))
+	ffgg := fftAdd(fftMul(ff, fftAdj(ff)), fftMul(gf, fftAdj(gf)))
+
+	var sqFG float64
+	for _, p := range [][]complex128{fftDiv(fftAdj(gf), ffgg), fftDiv(fftAdj(ff), ffgg)} {
+		for _, c := range ifft(p) {
+			sqFG += float64(c * c)
+		}
+	}
+	return math.Sqrt(math.Max(sqfg, q*q*sqFG))
+}
+
+// fitsBits reports whether every coefficient fits a signed encoding of the
+// given bit width, excluding the most negative value.
+func fitsBits(a []int16, width uint) bool {
+	bound := int16(1)<<(width-1) - 1
+	for _, c := range a {
+		if c < -bound || c > bound {
+			return false
+		}
+	}
+	return true
+}
+
+// keygen runs NTRUGen with the given sampler, returning f, g, F and G.
+func keygen(s *sampler) (f, g, F, G []int16) {
+	for {
+		f, g = genPoly(s), genPoly(s)
+		if !fitsBits(f, fgBits) || !fitsBits(g, fgBits) {
+			continue
+		}
+		if gsNorm(f, g) > 1.17*math.Sqrt(q) {
+			continue
+		}
+		if _, ok := invertModQ(f); !ok {
+			continue
+		}
+		bF, bG, ok := ntruSolve(bigPolyFromInts(f), bigPolyFromInts(g))
+		if !ok {
+			continue
+		}
+		if F, ok = bigToInts(bF, bigFGBits); !ok {
+			continue
+		}
+		if G, ok = bigToInts(bG, bigFGBits); !ok {
+			continue
+		}
+		return f, g, F, G
+	}
+}
+
+// bigToInts converts a polynomial to small coefficients, returning false if
+// they do not fit a signed encoding of the given bit width.
+func bigToInts(a bigPoly, width uint) ([]int16, bool) {
+	r := make([]int16, len(a))
+	for i, c := range a {
+		if !c.IsInt64() || c.Int64() < -(1<<(width-1)-1) || c.Int64() > 1<<(width-1)-1 {
+			return nil, false
+		}
+		r[i] = int16(c.Int64())
+	}
+	return r, true
+}
+
+// toFloats converts small integer coefficients to floating point.
+func toFloats(a []int16) []float64 {
+	r := make([]float64, len(a))
+	for i, c := range a {
+		r[i] = float64(c)
+	}
+	return r
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+// psi is a primitive 1024-th root of unity modulo q, i.e. 11^12 where 11
+// generates the multiplicative group of Z_q.
+const psi = 10302
+
+var (
+	zetas    [n]uint32 // Powers of psi in bit-reversed order
+	invZetas [n]uint32 // Powers of psi^-1 in bit-reversed order
+	nInv     uint32    // Inverse of n modulo q
+)
+
+func init() {
+	psiInv := modInv(psi)
+	for i := 0; i < n; i++ {
+		br := bitrev(uint32(i))
+		zetas[i] = modExp(psi, br)
+		invZetas[i] = modExp(psiInv, br)
+	}
+	nInv = modInv(n)
+}
+
+// bitrev reverses the lowest logn bits of x.
+func bitrev(x uint32) uint32 {
+	var r uint32
+	for i := 0; i < logn; i++ {
+		r = r<<1 | x&1
+		x >>= 1
+	}
+	return r
+}
+
+// modExp computes b^e modulo q.
+func modExp(b, e uint32) uint32 {
+	r := uint32(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			r = r * b % q
+		}
+		b = b * b % q
+	}
+	return r
+}
+
+// modInv computes the inverse of a non-zero element modulo q.
+func modInv(a uint32) uint32 {
+	return modExp(a, q-2)
+}
+
+// ntt transforms a polynomial with coefficients in [0, q) in place into the
+// negacyclic number theoretic transform domain.
+func ntt(a *[n]uint32) {
+	k := 0
+	for length := n / 2; length >= 1; length >>= 1 {
+		for start := 0; start < n; start += 2 * length {
+			k++
+			z := zetas[k]
+			for j := start; j < start+length; j++ {
+				t := z * a[j+length] % q
+				a[j+length] = (a[j] + q - t) % q
+				a[j] = (a[j] + t) % q
+			}
+		}
+	}
+}
+
+// invntt is the inverse of ntt, returning coefficients in [0, q).
+func invntt(a *[n]uint32) {
+	for length := 1; length < n; length <<= 1 {
+		for start := 0; start < n; start += 2 * length {
+			z := invZetas[n/(2*length)+start/(2*length)]
+			for j := start; j < start+length; j++ {
+				t, u := a[j], a[j+length]
+				a[j] = (t + u) % q
+				a[j+length] = (t + q - u) * z % q
+			}
+		}
+	}
+	for i := range a {
+		a[i] = a[i] * nInv % q
+	}
+}
+
+// toModQ maps signed coefficients into [0, q).
+func toModQ(a []int16) *[n]uint32 {
+	var r [n]uint32
+	for i, c := range a {
+		v := int32(c) % q
+		if v < 0 {
+			v += q
+		}
+		r[i] = uint32(v)
+	}
+	return &r
+}
+
+// center maps a coefficient in [0, q) to its representative in (-q/2, q/2].
+func center(a uint32) int32 {
+	v := int32(a)
+	if v > q/2 {
+		v -= q
+	}
+	return v
+}
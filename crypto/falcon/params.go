@@ -0,0 +1,52 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+// Falcon-512 parameters (Falcon specification v1.2, section 3.13).
+const (
+	logn = 9         // Log2 of the ring degree
+	n    = 1 << logn // Ring degree
+	q    = 12289     // Modulus
+
+	// NonceSize is the length of the random salt hashed together with the message.
+	NonceSize = 40
+
+	// PublicKeySize is the length of an encoded public key: a header byte
+	// followed by 512 coefficients of 14 bits.
+	PublicKeySize = 1 + n*14/8
+
+	// PrivateKeySize is the length of an encoded private key: a header byte
+	// followed by f and g with 6 bits and F with 8 bits per coefficient.
+	PrivateKeySize = 1 + 2*n*6/8 + n*8/8
+
+	// MaxSignatureSize is the longest compressed signature produced or accepted.
+	MaxSignatureSize = 752
+
+	publicKeyHeader  = 0x00 + logn
+	privateKeyHeader = 0x50 + logn
+	signatureHeader  = 0x30 + logn
+
+	sigBound = 34034726 // Squared norm bound of the signature vector (floor(beta^2))
+
+	sigma         = 165.7366171829776  // Standard deviation of the signature sampler
+	sigmaMin      = 1.2778336969128337 // Lower bound on the leaf standard deviations
+	sigmaMax      = 1.8205             // Standard deviation of the base sampler
+	sigmaKeygen   = 1.43300980528773   // Standard deviation of the key generation sampler
+	keygenSamples = 4096 / n           // Number of samples summed per key coefficient
+
+	fgBits    = 6 // Bit width of an encoded f or g coefficient
+	bigFGBits = 8 // Bit width of an encoded F coefficient
+)
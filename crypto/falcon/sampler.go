@@ -0,0 +1,139 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// rcdt is the reverse cumulative distribution table of the half-Gaussian base
+// sampler with standard deviation sigmaMax, scaled by 2^72 and split into the
+// high 8 and low 64 bits of every entry.
+var rcdt = [18][2]uint64{
+	{163, 0xf7f42ed3ac391802},
+	{84, 0xd32b181f3f7ddb82},
+	{34, 0x7dcdd0934829c1ff},
+	{10, 0xd1754377c7994ae4},
+	{2, 0x95846caef33f1f6f},
+	{0, 0x774ac754ed74bd5f},
+	{0, 0x1024dd542b776ae4},
+	{0, 0x01a1ffdc65ad63da},
+	{0, 0x001f80d88a7b6428},
+	{0, 0x0001c3fdb2040c69},
+	{0, 0x000012cf24d031fb},
+	{0, 0x000000949f8b091f},
+	{0, 0x00000003665da998},
+	{0, 0x000000000ebf6ebb},
+	{0, 0x00000000002f5d7e},
+	{0, 0x0000000000007098},
+	{0, 0x00000000000000c6},
+	{0, 0x0000000000000001},
+}
+
+// expCoeffs are the coefficients of the polynomial approximating exp(-x) on
+// [0, ln 2), scaled by 2^63, in Horner order.
+var expCoeffs = [13]uint64{
+	0x00000004741183A3, 0x00000036548CFC06, 0x0000024FDCBF140A,
+	0x0000171D939DE045, 0x0000D00CF58F6F84, 0x000680681CF796E3,
+	0x002D82D8305B0FEA, 0x011111110E066FD0, 0x0555555555070F00,
+	0x155555555581FF00, 0x400000000002B400, 0x7FFFFFFFFFFF4800,
+	0x8000000000000000,
+}
+
+// sampler draws integers from discrete Gaussian distributions, fed by a
+// SHAKE256 based pseudo random stream.
+type sampler struct {
+	rng io.Reader
+	buf [9]byte
+}
+
+// byte reads a single random byte.
+func (s *sampler) byte() uint8 {
+	io.ReadFull(s.rng, s.buf[:1])
+	return s.buf[0]
+}
+
+// base samples z0 >= 0 from the half-Gaussian with standard deviation sigmaMax.
+func (s *sampler) base() int {
+	io.ReadFull(s.rng, s.buf[:])
+	lo, hi := binary.LittleEndian.Uint64(s.buf[:8]), uint64(s.buf[8])
+
+	z0 := 0
+	for _, e := range rcdt {
+		// Constant time 72 bit comparison (hi, lo) < e
+		_, borrow := bits.Sub64(lo, e[1], 0)
+		_, borrow = bits.Sub64(hi, e[0], borrow)
+		z0 += int(borrow)
+	}
+	return z0
+}
+
+// approxExp returns ccs * exp(-x) scaled by 2^63, for 0 <= x < ln 2 and
+// 0 < ccs <= 1.
+func approxExp(x, ccs float64) uint64 {
+	y := expCoeffs[0]
+	z := uint64(float64(x*(1<<63))) << 1
+	for _, c := range expCoeffs[1:] {
+		hi, _ := bits.Mul64(z, y)
+		y = c - hi
+	}
+	z = uint64(float64(ccs*(1<<63))) << 1
+	y, _ = bits.Mul64(z, y)
+	return y
+}
+
+// berExp returns true with probability ccs * exp(-x), for x >= 0.
+func (s *sampler) berExp(x, ccs float64) bool {
+	k := int(x / math.Ln2)
+	r := x - float64(float64(k)*math.Ln2)
+	if k > 63 {
+		k = 63
+	}
+	z := ((approxExp(r, ccs) << 1) - 1) >> uint(k)
+
+	var w int
+	for i := 64; i > 0; {
+		i -= 8
+		w = int(s.byte()) - int(z>>uint(i)&0xff)
+		if w != 0 {
+			break
+		}
+	}
+	return w < 0
+}
+
+// sampleZ samples an integer from the discrete Gaussian centred at mu with
+// standard deviation sigmaPrime, where sigmaMin <= sigmaPrime <= sigmaMax.
+func (s *sampler) sampleZ(mu, sigmaPrime, sigmaMin float64) int {
+	fl := math.Floor(mu)
+	r := mu - fl
+	dss := 1 / (2 * sigmaPrime * sigmaPrime)
+	ccs := sigmaMin / sigmaPrime
+
+	for {
+		z0 := s.base()
+		b := int(s.byte() & 1)
+		z := b + (2*b-1)*z0
+
+		x := float64((float64(z)-r)*(float64(z)-r)*dss) - float64(z0*z0)/(2*sigmaMax*sigmaMax)
+		if s.berExp(x, ccs) {
+			return z + int(fl)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 The IxiosSpark Authors
+// This file is part of the IxiosSpark library, which builds upon the source code of the go-ethereum library.
+//
+// This software is open source, available for redistribution and modification
+// under the GNU Lesser General Public License (LGPL) v3, as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (under your choice) any later version.
+//
+// Distributed in the hope of being useful, this software comes with no warranties of
+// any kind, not even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.
+// For more details, review the GNU Lesser General Public License.
+//
+// A copy of the GNU Lesser General Public License should have been provided with this software.
+// If not, see <http://www.gnu.org/licenses/>.
+
+package falcon
+
+import (
+	"math"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ffNode is a node of the Falcon LDL tree. Inner nodes hold the off-diagonal
+// factor of the LDL decomposition, leaves the standard deviation used to
+// sample the corresponding coordinate.
+type ffNode struct {
+	l10         []complex128
+	left, right *ffNode
+	sigma       float64
+}
+
+// expandedKey is the FFT domain form of a private key used for signing.
+type expandedKey struct {
+	b00, b01, b10, b11 []complex128 // Basis [[g, -f], [G, -F]]
+	tree               *ffNode
+}
+
+// expandKey computes the signing basis and LDL tree of a private key.
+func expandKey(f, g, F, G []int16) *expandedKey {
+	ek := &expandedKey{
+		b00: fft(toFloats(g)),
+		b01: fftNeg(fft(toFloats(f))),
+		b10: fft(toFloats(G)),
+		b11: fftNeg(fft(toFloats(F))),
+	}
+	// Gram matrix of the basis, G = B * B^*
+	g00 := fftAdd(fftMul(ek.b00, fftAdj(ek.b00)), fftMul(ek.b01, fftAdj(ek.b01)))
+	g10 := fftAdd(fftMul(ek.b10, fftAdj(ek.b00)), fftMul(ek.b11, fftAdj(ek.b01)))
+	g11 := fftAdd(fftMul(ek.b10, fftAdj(ek.b10)), fftMul(ek.b11, fftAdj(ek.b11)))
+
+	ek.tree = ffLDL(g00, g10, g11)
+	return ek
+}
+
+// ffLDL builds the LDL tree of the self-adjoint 2x2 matrix [[g00, g10^*], [g10, g11]].
+func ffLDL(g00, g10, g11 []complex128) *ffNode {
+	l10 := fftDiv(g10, g00)
+	d00 := g00
+	d11 := fftSub(g11, fftMul(fftMul(l10, fftAdj(l10)), g00))
+
+	node := &ffNode{l10: l10}
+	if len(g00) > 2 {
+		a0, a1 := splitFFT(d00)
+		b0, b1 := splitFFT(d11)
+		node.left = ffLDL(a0, fftAdj(a1), a0)
+		node.right = ffLDL(b0, fftAdj(b1), b0)
+	} else {
+		node.left = &ffNode{sigma: sigma / math.Sqrt(real(d00[0]))}
+		node.right = &ffNode{sigma: sigma / math.Sqrt(real(d11[0]))}
+	}
+	return node
+}
+
+// ffSampling samples z close to t using the LDL tree.
+func ffSampling(s *sampler, t0, t1 []complex128, node *ffNode) ([]complex128, []complex128) {
+	if node.left == nil {
+		z0 := s.sampleZ(real(t0[0]), node.sigma, sigmaMin)
+		z1 := s.sampleZ(real(t1[0]), node.sigma, sigmaMin)
+		return []complex128{complex(float64(z0), 0)}, []complex128{complex(float64(z1), 0)}
+	}
+	a, b := splitFFT(t1)
+	z1 := mergeFFT(ffSampling(s, a, b, node.right))
+
+	t0b := fftAdd(t0, fftMul(fftSub(t1, z1), node.l10))
+	a, b = splitFFT(t0b)
+	z0 := mergeFFT(ffSampling(s, a, b, node.left))
+	return z0, z1
+}
+
+// samplePreimage samples a short vector (s0, s1) with s0 + s1*h = c mod q.
+func (ek *expandedKey) samplePreimage(s *sampler, c []int16) ([]int16, []int16) {
+	cf := fft(toFloats(c))
+	t0, t1 := make([]complex128, n), make([]complex128, n)
+	for i := range cf {
+		t0[i] = cdiv(cmul(cf[i], ek.b11[i]), q)
+		t1[i] = cdiv(cmul(-cf[i], ek.b01[i]), q)
+	}
+	z0, z1 := ffSampling(s, t0, t1, ek.tree)
+
+	v0 := ifft(fftAdd(fftMul(z0, ek.b00), fftMul(z1, ek.b10)))
+	v1 := ifft(fftAdd(fftMul(z0, ek.b01), fftMul(z1, ek.b11)))
+
+	s0, s1 := make([]int16, n), make([]int16, n)
+	for i := 0; i < n; i++ {
+		s0[i] = c[i] - int16(math.RoundToEven(v0[i]))
+		s1[i] = -int16(math.RoundToEven(v1[i]))
+	}
+	return s0, s1
+}
+
+// hashToPoint hashes the nonce and message to a polynomial modulo q.
+func hashToPoint(nonce, msg []byte) []int16 {
+	h := sha3.NewShake256()
+	h.Write(nonce)
+	h.Write(msg)
+
+	c := make([]int16, n)
+	var buf [2]byte
+	for i := 0; i < n; {
+		h.Read(buf[:])
+		if v := uint32(buf[0])<<8 | uint32(buf[1]); v < 5*q {
+			c[i] = int16(v % q)
+			i++
+		}
+	}
+	return c
+}
+
+// sqNorm returns the squared Euclidean norm of the given vectors.
+func sqNorm(vs ...[]int16) int64 {
+	var norm int64
+	for _, v := range vs {
+		for _, c := range v {
+			norm += int64(c) * int64(c)
+		}
+	}
+	return norm
+}
+
+// invertModQ returns the NTT representation of the inverse of f modulo q,
+// or false if f is not invertible.
+func invertModQ(f []int16) (*[n]uint32, bool) {
+	a := toModQ(f)
+	ntt(a)
+	for i, c := range a {
+		if c == 0 {
+			return nil, false
+		}
+		a[i] = modInv(c)
+	}
+	return a, true
+}
+
+// mulModQ returns a * b modulo (q, x^n + 1) for coefficients in [0, q).
+func mulModQ(a, b *[n]uint32) *[n]uint32 {
+	x, y := *a, *b
+	ntt(&x)
+	ntt(&y)
+	for i := range x {
+		x[i] = x[i] * y[i] % q
+	}
+	invntt(&x)
+	return &x
+}
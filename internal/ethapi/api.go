@@ -43,6 +43,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/rlp"
 	"github.com/ixios-io/ixiosSpark/rpc"
 	"github.com/ixios-io/ixiosSpark/trie"
+	"github.com/ixios-io/ixiosSpark/zeta"
 )
 
 // estimateGasErrorRatio is the amount of overestimation eth_estimateGas is
@@ -153,6 +154,39 @@ func (s *IxiosAPI) Syncing() (interface{}, error) {
 	}, nil
 }
 
+// ZetaAPI provides access to the minimum gas price schedule of the network.
+type ZetaAPI struct {
+	b Backend
+}
+
+// NewZetaAPI creates a new zeta API.
+func NewZetaAPI(b Backend) *ZetaAPI {
+	return &ZetaAPI{b}
+}
+
+// Zeta returns the value of 1 zeta, the minimum gas price, in wei at the given
+// block. Explicit block numbers may lie in the future as long as the schedule
+// active there counts epochs in blocks, allowing wallets to look up upcoming
+// values of the schedule.
+func (s *ZetaAPI) Zeta(ctx context.Context, number rpc.BlockNumber) (*hexutil.Big, error) {
+	header, err := s.b.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	config := s.b.ChainConfig()
+	if header == nil {
+		if number < 0 {
+			return nil, nil
+		}
+		num := big.NewInt(number.Int64())
+		if config.ZetaConfigAt(num).IsTimeBased() {
+			return nil, errors.New("zeta of future blocks is unknown with time based epochs")
+		}
+		return (*hexutil.Big)(zeta.Value(config, num, 0)), nil
+	}
+	return (*hexutil.Big)(zeta.Value(config, header.Number, header.Time)), nil
+}
+
 // TxPoolAPI offers and API for the transaction pool. It only operates on data that is non-confidential.
 type TxPoolAPI struct {
 	b Backend
@@ -357,12 +391,17 @@ func (s *PersonalAccountAPI) DeriveAccount(url string, path string, pin *bool) (
 }
 
 // NewAccount will create a new account and returns the address for the new account.
-func (s *PersonalAccountAPI) NewAccount(password string) (common.AddressEIP55, error) {
+// The optional scheme selects the signature scheme of the new key, ECDSA if unset.
+func (s *PersonalAccountAPI) NewAccount(password string, scheme *string) (common.AddressEIP55, error) {
 	ks, err := fetchKeystore(s.am)
 	if err != nil {
 		return common.AddressEIP55{}, err
 	}
-	acc, err := ks.NewAccount(password)
+	keyScheme := keystore.SchemeECDSA
+	if scheme != nil {
+		keyScheme = *scheme
+	}
+	acc, err := ks.NewSchemeAccount(password, keyScheme)
 	if err == nil {
 		addrEIP55 := common.AddressEIP55(acc.Address)
 		log.Info("Your new key was generated", "address", addrEIP55.String())
@@ -538,7 +577,9 @@ func (s *PersonalAccountAPI) Sign(ctx context.Context, data hexutil.Bytes, addr
 		log.Warn("Failed data sign attempt", "address", addr, "err", err)
 		return nil, err
 	}
-	signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	if len(signature) == crypto.SignatureLength {
+		signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
 	return signature, nil
 }
 
@@ -568,6 +609,15 @@ func (s *PersonalAccountAPI) EcRecover(ctx context.Context, data, sig hexutil.By
 	return crypto.PubkeyToAddress(*rpk), nil
 }
 
+// QuantumRecover returns the address owned by the post-quantum public key that
+// produced the given signature over data. Post-quantum schemes do not support
+// key recovery, so the public key must be supplied alongside the signature. The
+// signed hash is that of personal_sign:
+// hash = keccak256("\x19Ixios Signed Message:\n"${message length}${message})
+func (s *PersonalAccountAPI) QuantumRecover(ctx context.Context, data, pubkey, sig hexutil.Bytes) (common.Address, error) {
+	return types.VerifyQuantumSignature(pubkey, accounts.TextHash(data), sig)
+}
+
 // InitializeWallet initializes a new wallet at the provided URL, by generating and returning a new private key.
 func (s *PersonalAccountAPI) InitializeWallet(ctx context.Context, url string) (string, error) {
 	return "", errors.New("specified wallet does not support initialization")
@@ -608,7 +658,7 @@ func (s *BlockChainAPI) BlockNumber() hexutil.Uint64 {
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
 func (s *BlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, _, err := s.b.ProvenStateAndHeader(ctx, blockNrOrHash, balanceAccounts(address), nil)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -616,6 +666,34 @@ func (s *BlockChainAPI) GetBalance(ctx context.Context, address common.Address,
 	return (*hexutil.Big)(b), state.Error()
 }
 
+// AccountKeyResult describes the public key controlling an account.
+type AccountKeyResult struct {
+	Address    common.Address `json:"address"`
+	Rotated    bool           `json:"rotated"`
+	PublicKey  hexutil.Bytes  `json:"publicKey,omitempty"`
+	KeyAddress common.Address `json:"keyAddress"`
+}
+
+// GetAccountKey returns the public key controlling the given address at the
+// given block. Accounts that were rotated are controlled by the key bound to
+// them, which transactions must carry; for all others the key is the one the
+// address derives from, which is not known to the chain and omitted.
+func (s *BlockChainAPI) GetAccountKey(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*AccountKeyResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	result := &AccountKeyResult{Address: address, KeyAddress: address}
+	if key := core.BoundKey(state, address); key != nil {
+		result.Rotated = true
+		result.PublicKey = key
+		if result.KeyAddress, err = types.PublicKeyToAddress(key); err != nil {
+			return nil, err
+		}
+	}
+	return result, state.Error()
+}
+
 // AccountResult structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -625,6 +703,16 @@ type AccountResult struct {
 	Nonce        hexutil.Uint64  `json:"nonce"`
 	StorageHash  common.Hash     `json:"storageHash"`
 	StorageProof []StorageResult `json:"storageProof"`
+	Legacy       *LegacyResult   `json:"legacy,omitempty"`
+}
+
+// LegacyResult proves the funds held by the 20 byte form of an ECDSA address,
+// which count towards its balance. The balance reported by eth_getBalance for
+// the address is the sum of both proven balances.
+type LegacyResult struct {
+	Address      common.Address `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *hexutil.Big   `json:"balance"`
 }
 
 type StorageResult struct {
@@ -661,7 +749,7 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 			return nil, err
 		}
 	}
-	statedb, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	statedb, header, err := s.b.ProvenStateAndHeader(ctx, blockNrOrHash, balanceAccounts(address), [][]common.Hash{keys})
 	if statedb == nil || err != nil {
 		return nil, err
 	}
@@ -707,12 +795,11 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 	if err != nil {
 		return nil, err
 	}
-	var accountProof proofList
-	if err := tr.Prove(crypto.Keccak256(address.Bytes()), &accountProof); err != nil {
+	accountProof, balance, err := proveAccount(tr, address)
+	if err != nil {
 		return nil, err
 	}
-	balance := statedb.GetBalance(address).ToBig()
-	return &AccountResult{
+	result := &AccountResult{
 		Address:      address,
 		AccountProof: accountProof,
 		Balance:      (*hexutil.Big)(balance),
@@ -720,7 +807,50 @@ func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, st
 		Nonce:        hexutil.Uint64(statedb.GetNonce(address)),
 		StorageHash:  storageRoot,
 		StorageProof: storageProof,
-	}, statedb.Error()
+	}
+	// The balance of the proven account excludes the funds of its 20 byte form,
+	// prove those too if there are any.
+	if legacy, ok := statedb.LegacyAccount(address); ok {
+		legacyProof, legacyBalance, err := proveAccount(tr, legacy)
+		if err != nil {
+			return nil, err
+		}
+		if legacyBalance.Sign() > 0 {
+			result.Legacy = &LegacyResult{
+				Address:      legacy,
+				AccountProof: legacyProof,
+				Balance:      (*hexutil.Big)(legacyBalance),
+			}
+		}
+	}
+	return result, statedb.Error()
+}
+
+// balanceAccounts returns the accounts whose balances add up to the balance of
+// the given address: the address itself and its 20 byte form, if any, along with
+// the marker telling which aliasing rules apply.
+func balanceAccounts(address common.Address) []common.Address {
+	if legacy, ok := types.TruncatedAddress(address); ok {
+		return []common.Address{address, legacy, params.LegacyAliasAddress}
+	}
+	return []common.Address{address}
+}
+
+// proveAccount creates the Merkle-proof of an account and returns it along with
+// the balance stored in the account.
+func proveAccount(tr *trie.StateTrie, address common.Address) (proofList, *big.Int, error) {
+	var proof proofList
+	if err := tr.Prove(crypto.Keccak256(address.Bytes()), &proof); err != nil {
+		return nil, nil, err
+	}
+	account, err := tr.GetAccount(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account == nil {
+		return proof, new(big.Int), nil
+	}
+	return proof, account.Balance.ToBig(), nil
 }
 
 // decodeHash parses a hex-encoded 32-byte hash. The input may optionally
@@ -1147,6 +1277,9 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	if err != nil {
 		return 0, err
 	}
+	// Account for the keys and signatures the transaction will be signed with
+	call.SignatureCost = core.SenderSignatureCost(state, call.From)
+
 	estimate, revert, err := gasestimator.Estimate(ctx, call, opts, gasCap)
 	if err != nil {
 		if len(revert) > 0 {
@@ -1286,6 +1419,11 @@ type RPCTransaction struct {
 	R                   *hexutil.Big      `json:"r"`
 	S                   *hexutil.Big      `json:"s"`
 	YParity             *hexutil.Uint64   `json:"yParity,omitempty"`
+	PublicKey           *hexutil.Bytes    `json:"publicKey,omitempty"`
+	Signature           *hexutil.Bytes    `json:"signature,omitempty"`
+	Threshold           *hexutil.Uint64   `json:"threshold,omitempty"`
+	PublicKeys          []hexutil.Bytes   `json:"publicKeys,omitempty"`
+	Signatures          []hexutil.Bytes   `json:"signatures,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1360,6 +1498,46 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		}
 		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
 		result.BlobVersionedHashes = tx.BlobHashes()
+
+	case types.PostQuantumTxType, types.RotatedKeyTxType:
+		al := tx.AccessList()
+		pub, sig := tx.QuantumSignature()
+		if tx.Type() == types.RotatedKeyTxType {
+			pub, sig = tx.RotatedKey()
+		}
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		result.PublicKey = (*hexutil.Bytes)(&pub)
+		result.Signature = (*hexutil.Bytes)(&sig)
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+
+	case types.MultisigTxType:
+		al := tx.AccessList()
+		threshold, pubs, sigs := tx.MultisigSignatures()
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		result.Threshold = (*hexutil.Uint64)(&threshold)
+		for _, pub := range pubs {
+			result.PublicKeys = append(result.PublicKeys, pub)
+		}
+		for _, sig := range sigs {
+			result.Signatures = append(result.Signatures, sig)
+		}
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
 	}
 	return result
 }
@@ -1616,6 +1794,48 @@ func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash commo
 	return tx.MarshalBinary()
 }
 
+// InclusionProofResult proves a transaction or its receipt to be part of a block.
+// The proof holds the trie nodes on the path from the TxHash or ReceiptHash of the
+// block header to the consensus encoding of the value, keyed by its RLP encoded
+// transaction index.
+type InclusionProofResult struct {
+	BlockHash        common.Hash     `json:"blockHash"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64  `json:"transactionIndex"`
+	Value            hexutil.Bytes   `json:"value"`
+	Proof            []hexutil.Bytes `json:"proof"`
+}
+
+// GetTransactionProof returns the transaction with the given hash along with the
+// Merkle proof of its inclusion in its block.
+func (s *TransactionAPI) GetTransactionProof(ctx context.Context, hash common.Hash) (*InclusionProofResult, error) {
+	return s.inclusionProof(ctx, hash, false)
+}
+
+// GetReceiptProof returns the receipt of the transaction with the given hash
+// along with the Merkle proof of its inclusion in its block.
+func (s *TransactionAPI) GetReceiptProof(ctx context.Context, hash common.Hash) (*InclusionProofResult, error) {
+	return s.inclusionProof(ctx, hash, true)
+}
+
+func (s *TransactionAPI) inclusionProof(ctx context.Context, hash common.Hash, receipt bool) (*InclusionProofResult, error) {
+	proof, err := s.b.InclusionProof(ctx, hash, receipt)
+	if proof == nil || err != nil {
+		return nil, err
+	}
+	result := &InclusionProofResult{
+		BlockHash:        proof.BlockHash,
+		BlockNumber:      hexutil.Uint64(proof.BlockNumber),
+		TransactionIndex: hexutil.Uint64(proof.Index),
+		Value:            proof.Value,
+		Proof:            make([]hexutil.Bytes, len(proof.Proof)),
+	}
+	for i, node := range proof.Proof {
+		result.Proof[i] = node
+	}
+	return result, nil
+}
+
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	found, tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
@@ -1814,7 +2034,7 @@ func (s *TransactionAPI) Sign(addr common.Address, data hexutil.Bytes) (hexutil.
 	}
 	// Sign the requested hash with the wallet
 	signature, err := wallet.SignText(account, data)
-	if err == nil {
+	if err == nil && len(signature) == crypto.SignatureLength {
 		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
 	}
 	return signature, err
@@ -61,6 +61,11 @@ type Backend interface {
 	BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error)
 	StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error)
 	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
+	// ProvenStateAndHeader is like StateAndHeaderByNumberOrHash, but the state
+	// need only hold the given accounts and storage slots of theirs. Nodes without
+	// state, such as header-only light nodes, assemble it from Merkle proofs
+	// retrieved from the network.
+	ProvenStateAndHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, accounts []common.Address, slots [][]common.Hash) (*state.StateDB, *types.Header, error)
 	PendingBlockAndReceipts() (*types.Block, types.Receipts)
 	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
 	GetTd(ctx context.Context, hash common.Hash) *big.Int
@@ -72,6 +77,9 @@ type Backend interface {
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64, error)
+	// InclusionProof proves the inclusion of a transaction, or of its receipt, in
+	// its block. Nil is returned if the transaction is unknown.
+	InclusionProof(ctx context.Context, txHash common.Hash, receipt bool) (*types.InclusionProof, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
@@ -119,6 +127,9 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "personal",
 			Service:   NewPersonalAccountAPI(apiBackend, nonceLock),
+		}, {
+			Namespace: "ixios",
+			Service:   NewZetaAPI(apiBackend),
 		},
 	}
 }
@@ -422,6 +422,28 @@ func (args *TransactionArgs) toTransaction() *types.Transaction {
 			}
 		}
 
+	case types.IsQuantumAddress(args.from()):
+		// Post-quantum accounts can only sign post-quantum transactions
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		feeCap, tipCap := (*big.Int)(args.MaxFeePerGas), (*big.Int)(args.MaxPriorityFeePerGas)
+		if feeCap == nil {
+			feeCap, tipCap = (*big.Int)(args.GasPrice), (*big.Int)(args.GasPrice)
+		}
+		data = &types.PostQuantumTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasFeeCap:  feeCap,
+			GasTipCap:  tipCap,
+			Value:      (*big.Int)(args.Value),
+			Data:       args.data(),
+			AccessList: al,
+		}
+
 	case args.MaxFeePerGas != nil:
 		al := types.AccessList{}
 		if args.AccessList != nil {
@@ -31,6 +31,7 @@ var Modules = map[string]string{
 	"les":      LESJs,
 	"vflux":    VfluxJs,
 	"dev":      DevJs,
+	"ixios":    IxiosJs,
 }
 
 const CliqueJs = `
@@ -64,6 +65,11 @@ web3._extend({
 			call: 'clique_propose',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'proposeWithKeys',
+			call: 'clique_propose',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'discard',
 			call: 'clique_discard',
@@ -80,6 +86,45 @@ web3._extend({
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'getValidatorKeys',
+			call: 'clique_getValidatorKeys',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorKeysAtHash',
+			call: 'clique_getValidatorKeysAtHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'proposeKeyGroup',
+			call: 'clique_proposeKeyGroup',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'discardKeyGroup',
+			call: 'clique_discardKeyGroup',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getKeyGroups',
+			call: 'clique_getKeyGroups',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'clique_getRewards',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorStats',
+			call: 'clique_getValidatorStats',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -594,6 +639,22 @@ web3._extend({
 			params: 3,
 			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getAccountKey',
+			call: 'eth_getAccountKey',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionProof',
+			call: 'eth_getTransactionProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getReceiptProof',
+			call: 'eth_getReceiptProof',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'createAccessList',
 			call: 'eth_createAccessList',
@@ -727,6 +788,11 @@ web3._extend({
 			call: 'personal_ecRecover',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'quantumRecover',
+			call: 'personal_quantumRecover',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'openWallet',
 			call: 'personal_openWallet',
@@ -911,3 +977,18 @@ web3._extend({
 	],
 });
 `
+
+const IxiosJs = `
+web3._extend({
+	property: 'ixios',
+	methods: [
+		new web3._extend.Method({
+			name: 'zeta',
+			call: 'ixios_zeta',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+	]
+});
+`
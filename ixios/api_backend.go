@@ -33,6 +33,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/core/vm"
 	"github.com/ixios-io/ixiosSpark/event"
 	"github.com/ixios-io/ixiosSpark/ixios/gasprice"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
 	"github.com/ixios-io/ixiosSpark/ixios/tracers"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/params"
@@ -73,6 +74,10 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	}
 	// Otherwise resolve and return the block
 	if number == rpc.LatestBlockNumber {
+		// Header-only nodes have no blocks beyond the genesis
+		if b.eth.handler.light {
+			return b.eth.blockchain.CurrentHeader(), nil
+		}
 		return b.eth.blockchain.CurrentBlock(), nil
 	}
 	if number == rpc.FinalizedBlockNumber {
@@ -232,6 +237,26 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+func (b *EthAPIBackend) ProvenStateAndHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, accounts []common.Address, slots [][]common.Hash) (*state.StateDB, *types.Header, error) {
+	if !b.eth.handler.light {
+		return b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	}
+	// Header-only nodes lack the state, retrieve the proofs of the requested
+	// parts from the network instead
+	header, err := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	stateDb, err := b.eth.handler.proofState(ctx, header.Root, accounts, slots)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateDb, header, nil
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
@@ -327,6 +352,23 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	return true, tx, lookup.BlockHash, lookup.BlockIndex, lookup.Index, nil
 }
 
+func (b *EthAPIBackend) InclusionProof(ctx context.Context, txHash common.Hash, receipt bool) (*types.InclusionProof, error) {
+	// Header-only nodes lack the blocks, retrieve the proof from the network
+	if b.eth.handler.light {
+		return b.eth.handler.inclusionProof(ctx, txHash, receipt)
+	}
+	var proofs []types.InclusionProof
+	if receipt {
+		proofs = eth.ServiceGetReceiptProofsQuery(b.eth.blockchain, []common.Hash{txHash})
+	} else {
+		proofs = eth.ServiceGetTransactionProofsQuery(b.eth.blockchain, []common.Hash{txHash})
+	}
+	if len(proofs) == 0 || proofs[0].BlockHash == (common.Hash{}) {
+		return nil, nil
+	}
+	return &proofs[0], nil
+}
+
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return b.eth.txPool.Nonce(addr), nil
 }
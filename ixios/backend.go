@@ -21,6 +21,7 @@ import (
 	"sync"
 
 	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/accounts/keystore"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/consensus"
@@ -41,6 +42,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/ixios/ethconfig"
 	"github.com/ixios-io/ixiosSpark/ixios/gasprice"
 	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/snap"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/node"
@@ -100,9 +102,6 @@ type Ixios struct {
 // initialisation of the common Ixios object)
 func New(stack *node.Node, config *ethconfig.Config) (*Ixios, error) {
 	// Ensure configuration values are compatible and sane
-	if config.SyncMode == downloader.LightSync {
-		return nil, errors.New("can't run ixios.Ixios in light sync mode, light mode has been deprecated")
-	}
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
@@ -227,9 +226,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ixios, error) {
 		TxPool:         eth.txPool,
 		Network:        networkID,
 		Sync:           config.SyncMode,
+		Checkpoint:     config.SyncCheckpoint,
+		CheckpointTD:   config.SyncCheckpointTD,
 		BloomCache:     uint64(cacheLimit),
 		EventMux:       eth.eventMux,
 		RequiredBlocks: config.RequiredBlocks,
+		Reputation:     eth.p2pServer,
 	}); err != nil {
 		return nil, err
 	}
@@ -403,6 +405,10 @@ func (s *Ixios) SetEtherbase(etherbase common.Address) {
 // is already running, this method adjust the number of threads allowed to use
 // and updates the minimum price required by the transaction pool.
 func (s *Ixios) StartMining() error {
+	// Header-only nodes have no state to build blocks on
+	if s.config.SyncMode == downloader.LightSync {
+		return errors.New("can't mine in light sync mode")
+	}
 	// If the sealer was not running, initialize it
 	if !s.IsMining() {
 		// Propagate the initial price point to the transaction pool
@@ -422,12 +428,18 @@ func (s *Ixios) StartMining() error {
 			cli = c
 		}
 		if cli != nil {
+			// Validators backed by a key group seal with its members, their own
+			// key need not be available
 			wallet, err := s.accountManager.Find(accounts.Account{Address: eb})
 			if wallet == nil || err != nil {
-				log.Error("Etherbase account unavailable locally", "err", err)
-				return fmt.Errorf("signer missing: %v", err)
+				if group, _ := cli.KeyGroup(s.blockchain, eb); group == nil {
+					log.Error("Etherbase account unavailable locally", "err", err)
+					return fmt.Errorf("signer missing: %v", err)
+				}
 			}
-			cli.Authorize(eb, wallet.SignData)
+			cli.Authorize(eb, s.signData)
+			cli.AuthorizeMKS(s.signMKS)
+			cli.AuthorizeGroup(s.signGroup)
 		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
@@ -438,6 +450,86 @@ func (s *Ixios) StartMining() error {
 	return nil
 }
 
+// signData signs data with whichever wallet holds the account, letting sealing
+// reach the MKS and key group member keys besides the etherbase.
+func (s *Ixios) signData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	wallet, err := s.accountManager.Find(account)
+	if err != nil {
+		return nil, fmt.Errorf("account %v unavailable: %v", account.Address, err)
+	}
+	return wallet.SignData(account, mimeType, data)
+}
+
+// signMKS produces both halves of a hybrid fastClique seal. Wallets able to,
+// such as external signers, are asked for both signatures in a single request;
+// otherwise each key signs on its own.
+func (s *Ixios) signMKS(signer, mksSigner accounts.Account, data []byte) ([]byte, []byte, error) {
+	wallet, err := s.accountManager.Find(signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("account %v unavailable: %v", signer.Address, err)
+	}
+	if hybrid, ok := wallet.(accounts.HybridSigner); ok {
+		sigs, err := hybrid.SignHybridData([]accounts.Account{signer, mksSigner}, accounts.MimetypeCliqueMKS, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		var sig, pqsig []byte
+		for _, tagged := range sigs {
+			switch tagged.Scheme {
+			case keystore.SchemeECDSA:
+				sig = tagged.Signature
+			case keystore.SchemeDilithium5:
+				pqsig = tagged.Signature
+			}
+		}
+		if sig == nil || pqsig == nil {
+			return nil, nil, fmt.Errorf("hybrid signer returned no %s and %s signatures", keystore.SchemeECDSA, keystore.SchemeDilithium5)
+		}
+		return sig, pqsig, nil
+	}
+	pqsig, err := s.signData(mksSigner, accounts.MimetypeCliqueMKS, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := wallet.SignData(signer, accounts.MimetypeCliqueMKS, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sig, pqsig, nil
+}
+
+// signGroup collects the signatures of a key group seal, asking every member
+// key available to the node's wallets, external cosigners included, at once and
+// returning as soon as the threshold is met.
+func (s *Ixios) signGroup(members []accounts.Account, threshold int, mimeType string, data []byte) ([][]byte, error) {
+	type result struct {
+		sig []byte
+		err error
+	}
+	results := make(chan result, len(members))
+	for _, member := range members {
+		go func(member accounts.Account) {
+			sig, err := s.signData(member, mimeType, data)
+			results <- result{sig, err}
+		}(member)
+	}
+	var (
+		sigs [][]byte
+		errs []error
+	)
+	for range members {
+		res := <-results
+		if res.err != nil {
+			errs = append(errs, res.err)
+			continue
+		}
+		if sigs = append(sigs, res.sig); len(sigs) == threshold {
+			return sigs, nil
+		}
+	}
+	return nil, fmt.Errorf("%d of %d key group signatures collected: %w", len(sigs), threshold, errors.Join(errs...))
+}
+
 // StopMining terminates the sealer, both at the consensus engine level as well as
 // at the block creation level.
 func (s *Ixios) StopMining() {
@@ -476,6 +568,9 @@ func (s *Ixios) SyncMode() downloader.SyncMode {
 // network protocols to start.
 func (s *Ixios) Protocols() []p2p.Protocol {
 	protos := eth.MakeProtocols((*ethHandler)(s.handler), s.networkID, s.ethDialCandidates)
+	if s.config.SnapshotCache > 0 {
+		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), nil)...)
+	}
 	return protos
 }
 
@@ -28,6 +28,8 @@ import (
 	"github.com/ixios-io/ixiosSpark/core/state/snapshot"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/event"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/snap"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/params"
@@ -74,11 +76,13 @@ var (
 	errTooOld                  = errors.New("peer's protocol version too old")
 	errNoAncestorFound         = errors.New("no common ancestor found")
 	errNoPivotHeader           = errors.New("pivot header is not found")
+	errCheckpointTooRecent     = errors.New("remote chain too short past the sync checkpoint")
 	ErrMergeTransition         = errors.New("legacy sync reached the merge")
 )
 
-// peerDropFn is a callback type for dropping a peer detected as malicious.
-type peerDropFn func(id string)
+// peerDropFn is a callback type for dropping a peer detected as malicious, along
+// with the misbehaviour it is dropped for.
+type peerDropFn func(id string, reason error)
 
 // badBlockFn is a callback for the async beacon sync to notify the caller that
 // the origin header requested to sync to, produced a chain with a bad block.
@@ -95,11 +99,14 @@ type Downloader struct {
 	mode atomic.Uint32  // Synchronisation mode defining the strategy used (per sync cycle), use d.getMode() to get the SyncMode
 	mux  *event.TypeMux // Event multiplexer to announce sync operation events
 
-	genesis uint64   // Genesis block number to limit sync to (e.g. light client CHT)
-	queue   *queue   // Scheduler for selecting the hashes to download
-	peers   *peerSet // Set of active peers from which download can proceed
+	genesis      uint64      // Genesis block number to limit sync to (e.g. light client CHT)
+	checkpoint   common.Hash // Trusted checkpoint header to snap sync from instead of genesis
+	checkpointTD *big.Int    // Total difficulty of the trusted checkpoint header
+	queue        *queue      // Scheduler for selecting the hashes to download
+	peers        *peerSet    // Set of active peers from which download can proceed
 
-	stateDB kvdb.Database // Database to state sync into (and deduplicate via)
+	stateDB    kvdb.Database // Database to state sync into (and deduplicate via)
+	SnapSyncer *snap.Syncer  // State syncer retrieving the pivot state over `ixsnap`
 
 	// Statistics
 	syncStatsChainOrigin uint64       // Origin block number where syncing started at
@@ -201,6 +208,9 @@ type BlockChain interface {
 	// InsertReceiptChain inserts a batch of receipts into the local chain.
 	InsertReceiptChain(types.Blocks, []types.Receipts, uint64) (int, error)
 
+	// InsertCheckpointHeader anchors an empty chain at a trusted checkpoint header.
+	InsertCheckpointHeader(*types.Header, *big.Int) error
+
 	// Snapshots returns the blockchain snapshot tree to paused it during sync.
 	Snapshots() *snapshot.Tree
 
@@ -209,13 +219,18 @@ type BlockChain interface {
 	TrieDB() *triedb.Database
 }
 
-// New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(stateDb kvdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn, success func()) *Downloader {
+// New creates a new downloader to fetch hashes and blocks from remote peers. If
+// a checkpoint hash is given, snap sync of an empty chain starts from that header,
+// whose total difficulty is given along, instead of genesis.
+func New(checkpoint common.Hash, checkpointTD *big.Int, stateDb kvdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn, success func()) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
 	dl := &Downloader{
+		checkpoint:     checkpoint,
+		checkpointTD:   checkpointTD,
 		stateDB:        stateDb,
+		SnapSyncer:     snap.NewSyncer(stateDb, chain.TrieDB().Scheme()),
 		mux:            mux,
 		queue:          newQueue(blockCacheMaxItems, blockCacheInitialItems),
 		peers:          newPeerSet(),
@@ -259,10 +274,21 @@ func (d *Downloader) Progress() ixiosSpark.SyncProgress {
 		log.Error("Unknown downloader chain/mode combo", "light", d.lightchain != nil, "full", d.blockchain != nil, "mode", mode)
 	}
 
+	progress := d.SnapSyncer.Progress()
 	return ixiosSpark.SyncProgress{
-		StartingBlock: d.syncStatsChainOrigin,
-		CurrentBlock:  current,
-		HighestBlock:  d.syncStatsChainHeight,
+		StartingBlock:       d.syncStatsChainOrigin,
+		CurrentBlock:        current,
+		HighestBlock:        d.syncStatsChainHeight,
+		SyncedAccounts:      progress.Accounts,
+		SyncedAccountBytes:  uint64(progress.AccountBytes),
+		SyncedBytecodes:     progress.Bytecodes,
+		SyncedBytecodeBytes: uint64(progress.BytecodeBytes),
+		SyncedStorage:       progress.Storage,
+		SyncedStorageBytes:  uint64(progress.StorageBytes),
+		HealedTrienodes:     progress.TrienodeHealSynced,
+		HealedTrienodeBytes: uint64(progress.TrienodeHealBytes),
+		HealedBytecodes:     progress.BytecodeHealSynced,
+		HealedBytecodeBytes: uint64(progress.BytecodeHealBytes),
 	}
 }
 
@@ -306,6 +332,12 @@ func (d *Downloader) UnregisterPeer(id string) error {
 	return nil
 }
 
+// DeliverSnapPacket is invoked from a peer's message handler when it transmits a
+// data packet for the local node to consume.
+func (d *Downloader) DeliverSnapPacket(peer *snap.Peer, packet snap.Packet) error {
+	return d.SnapSyncer.Deliver(peer, packet)
+}
+
 // LegacySync tries to sync up our local blockchain with a remote peer, both
 // adding various sanity checks, as well as wrapping it with various log entries.
 func (d *Downloader) LegacySync(id string, head common.Hash, td, ttd *big.Int, mode SyncMode) error {
@@ -324,7 +356,7 @@ func (d *Downloader) LegacySync(id string, head common.Hash, td, ttd *big.Int, m
 			// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
 			log.Warn("Downloader wants to drop peer, but peerdrop-function is not set", "peer", id)
 		} else {
-			d.dropPeer(id)
+			d.dropPeer(id, dropReason(err))
 		}
 		return err
 	}
@@ -335,6 +367,23 @@ func (d *Downloader) LegacySync(id string, head common.Hash, td, ttd *big.Int, m
 	return err
 }
 
+// dropReason classifies the error a peer failed a sync with into the misbehaviour
+// it gets dropped for. Other errors, such as the peer lacking the requested data
+// or being too far behind, are returned unchanged and are not held against it.
+func dropReason(err error) error {
+	switch {
+	case errors.Is(err, errTimeout):
+		return fmt.Errorf("%w: %v", eth.ErrRequestTimeout, err)
+	case errors.Is(err, errStallingPeer) || errors.Is(err, errUnsyncedPeer) || errors.Is(err, errEmptyHeaderSet):
+		return fmt.Errorf("%w: %v", eth.ErrStaleHead, err)
+	case errors.Is(err, errInvalidChain) || errors.Is(err, errBadPeer) || errors.Is(err, errInvalidAncestor) ||
+		errors.Is(err, errInvalidBody) || errors.Is(err, errInvalidReceipt):
+		return fmt.Errorf("%w: %v", eth.ErrBadChain, err)
+	default:
+		return err
+	}
+}
+
 // synchronise will select the peer and use it for synchronising. If an empty string is given
 // it will use the best peer possible and synchronise if its TD is higher than our own. If any of the
 // checks fail an error will be returned. This method is synchronous
@@ -376,6 +425,21 @@ func (d *Downloader) synchronise(id string, hash common.Hash, td, ttd *big.Int,
 	d.cancelPeer = id
 	d.cancelLock.Unlock()
 
+	// If snap sync was requested, the state syncer will directly modify the
+	// persistent state, making the trie database unusable until the state is
+	// fully synced. Disable it explicitly to prevent any subsequent state reads,
+	// along with the snapshot maintenance that would otherwise access the flaky
+	// data in the meantime.
+	if mode == SnapSync {
+		if d.blockchain.TrieDB().Scheme() == rawdb.PathScheme {
+			if err := d.blockchain.TrieDB().Disable(); err != nil {
+				return err
+			}
+		}
+		if snapshots := d.blockchain.Snapshots(); snapshots != nil {
+			snapshots.Disable()
+		}
+	}
 	// Atomically set the requested sync mode
 	d.mode.Store(uint32(mode))
 
@@ -414,19 +478,45 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	}(time.Now())
 
 	// Look up the sync boundaries: the common ancestor and the target block
-	var latest *types.Header
-	latest, _, err = d.fetchHead(p)
+	var latest, pivot *types.Header
+	latest, pivot, err = d.fetchHead(p)
 	if err != nil {
 		return err
 	}
+	// If no pivot block was returned, the head is below the min full block
+	// threshold (i.e. new chain). In that case we won't really snap sync
+	// anyway, but still need a valid pivot block to avoid some code hitting
+	// nil panics on access.
+	if mode == SnapSync && pivot == nil {
+		pivot = d.blockchain.CurrentBlock()
+	}
 	height := latest.Number.Uint64()
 
+	// If syncing from a checkpoint, anchor the chain at it first
+	var anchor *types.Header
+	if (mode == SnapSync || mode == LightSync) && d.checkpoint != (common.Hash{}) {
+		if anchor, err = d.anchorCheckpoint(p); err != nil {
+			return err
+		}
+		if anchor != nil && mode == SnapSync && pivot.Number.Uint64() <= anchor.Number.Uint64() {
+			return fmt.Errorf("%w: pivot %d, checkpoint %d", errCheckpointTooRecent, pivot.Number, anchor.Number)
+		}
+	}
 	var origin uint64
 
-	// reach out to the network and find the ancestor
-	origin, err = d.findAncestor(p, latest)
-	if err != nil {
-		return err
+	// Until the chain grows past the checkpoint there is nothing before it to
+	// look for, otherwise reach out to the network and find the ancestor
+	local := d.blockchain.CurrentSnapBlock()
+	if mode == LightSync {
+		local = d.lightchain.CurrentHeader()
+	}
+	if anchor != nil && local.Number.Uint64() <= anchor.Number.Uint64() {
+		origin = anchor.Number.Uint64()
+	} else {
+		origin, err = d.findAncestor(p, latest)
+		if err != nil {
+			return err
+		}
 	}
 
 	d.syncStatsLock.Lock()
@@ -435,8 +525,58 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 	}
 	d.syncStatsChainHeight = height
 	d.syncStatsLock.Unlock()
-	d.committed.Store(true)
 
+	// Ensure our origin point is below any snap sync pivot point
+	if mode == SnapSync {
+		if height <= uint64(fsMinFullBlocks) {
+			origin = 0
+		} else {
+			pivotNumber := pivot.Number.Uint64()
+			if pivotNumber <= origin {
+				origin = pivotNumber - 1
+			}
+			// Write out the pivot into the database so a rollback beyond it will
+			// reenable snap sync
+			rawdb.WriteLastPivotNumber(d.stateDB, pivotNumber)
+		}
+	}
+	d.committed.Store(true)
+	if mode == SnapSync && pivot.Number.Uint64() != 0 {
+		d.committed.Store(false)
+	}
+	if mode == SnapSync {
+		// Set the ancient data limitation. If we are running snap sync, all block
+		// data older than ancientLimit will be written to the ancient store. More
+		// recent data will be written to the active database and will wait for the
+		// freezer to migrate.
+		if height > fullMaxForkAncestry+1 {
+			d.ancientLimit = height - fullMaxForkAncestry - 1
+		} else {
+			d.ancientLimit = 0
+		}
+		// The freezer of a chain anchored at a checkpoint only starts out past
+		// it once the blocks are migrated, write them to the active store.
+		if anchor != nil {
+			d.ancientLimit = 0
+		}
+		frozen, _ := d.stateDB.Ancients()
+
+		// If a part of blockchain data has already been written into active store,
+		// disable the ancient style insertion explicitly.
+		if origin >= frozen && frozen != 0 {
+			d.ancientLimit = 0
+			log.Info("Disabling direct-ancient mode", "origin", origin, "ancient", frozen-1)
+		} else if d.ancientLimit > 0 {
+			log.Debug("Enabling direct-ancient mode", "ancient", d.ancientLimit)
+		}
+		// Rewind the ancient store and blockchain if reorg happens.
+		if origin+1 < frozen {
+			if err := d.lightchain.SetHead(origin); err != nil {
+				return err
+			}
+			log.Info("Truncated excess ancient chain segment", "oldhead", frozen-1, "newhead", origin)
+		}
+	}
 	// Initiate the sync using a concurrent header and content retrieval algorithm
 	d.queue.Prepare(origin+1, mode)
 	if d.syncInitHook != nil {
@@ -454,7 +594,15 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td, ttd *
 		func() error { return d.processHeaders(origin+1, td, ttd, beaconMode) },
 	}
 
-	fetchers = append(fetchers, func() error { return d.processFullSyncContent(ttd, false) })
+	if mode == SnapSync {
+		d.pivotLock.Lock()
+		d.pivotHeader = pivot
+		d.pivotLock.Unlock()
+
+		fetchers = append(fetchers, func() error { return d.processSnapSyncContent() })
+	} else if mode == FullSync {
+		fetchers = append(fetchers, func() error { return d.processFullSyncContent(ttd, false) })
+	}
 	return d.spawnSync(fetchers)
 }
 
@@ -542,6 +690,9 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 	// Request the advertised remote head block and wait for the response
 	latest, _ := p.peer.Head()
 	fetch := 1
+	if d.getMode() == SnapSync {
+		fetch = 2 // head + pivot headers
+	}
 	headers, hashes, err := d.fetchHeadersByHash(p, latest, fetch, fsMinFullBlocks-1, true)
 	if err != nil {
 		return nil, nil, err
@@ -567,6 +718,31 @@ func (d *Downloader) fetchHead(p *peerConnection) (head *types.Header, pivot *ty
 	return head, pivot, nil
 }
 
+// anchorCheckpoint returns the trusted checkpoint header the local chain is
+// anchored at, retrieving it from the remote peer and anchoring an empty chain
+// first if needed. Nil is returned if the chain was synced without it.
+func (d *Downloader) anchorCheckpoint(p *peerConnection) (*types.Header, error) {
+	if header := d.lightchain.GetHeaderByHash(d.checkpoint); header != nil {
+		return header, nil
+	}
+	if head := d.lightchain.CurrentHeader(); head.Number.Uint64() != 0 {
+		log.Debug("Local chain not empty, ignoring sync checkpoint", "head", head.Number, "checkpoint", d.checkpoint)
+		return nil, nil
+	}
+	p.log.Debug("Retrieving sync checkpoint", "hash", d.checkpoint)
+	headers, hashes, err := d.fetchHeadersByHash(p, d.checkpoint, 1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) != 1 || hashes[0] != d.checkpoint {
+		return nil, fmt.Errorf("%w: checkpoint %x not returned", errBadPeer, d.checkpoint)
+	}
+	if err := d.blockchain.InsertCheckpointHeader(headers[0], d.checkpointTD); err != nil {
+		return nil, err
+	}
+	return headers[0], nil
+}
+
 // calculateRequestSpan calculates what headers to request from a peer when trying to determine the
 // common ancestor.
 // It returns parameters to be used for peer.RequestHeadersByNumber:
@@ -633,7 +809,15 @@ func (d *Downloader) findAncestor(p *peerConnection, remoteHeader *types.Header)
 		localHeight  uint64
 		remoteHeight = remoteHeader.Number.Uint64()
 	)
-	localHeight = d.blockchain.CurrentBlock().Number.Uint64()
+	mode := d.getMode()
+	switch mode {
+	case FullSync:
+		localHeight = d.blockchain.CurrentBlock().Number.Uint64()
+	case SnapSync:
+		localHeight = d.blockchain.CurrentSnapBlock().Number.Uint64()
+	default:
+		localHeight = d.lightchain.CurrentHeader().Number.Uint64()
+	}
 	p.log.Debug("Looking for common ancestor", "local", localHeight, "remote", remoteHeight)
 
 	// Recap floor value for binary search
@@ -644,7 +828,7 @@ func (d *Downloader) findAncestor(p *peerConnection, remoteHeader *types.Header)
 		floor = int64(localHeight - maxForkAncestry)
 	}
 
-	ancestor, err := d.findAncestorSpanSearch(p, FullSync, remoteHeight, localHeight, floor)
+	ancestor, err := d.findAncestorSpanSearch(p, mode, remoteHeight, localHeight, floor)
 	if err == nil {
 		return ancestor, nil
 	}
@@ -656,13 +840,26 @@ func (d *Downloader) findAncestor(p *peerConnection, remoteHeader *types.Header)
 		return 0, err
 	}
 
-	ancestor, err = d.findAncestorBinarySearch(p, FullSync, remoteHeight, floor)
+	ancestor, err = d.findAncestorBinarySearch(p, mode, remoteHeight, floor)
 	if err != nil {
 		return 0, err
 	}
 	return ancestor, nil
 }
 
+// hasAncestor checks whether the given block is known locally to the extent the
+// sync mode requires it to be.
+func (d *Downloader) hasAncestor(mode SyncMode, hash common.Hash, number uint64) bool {
+	switch mode {
+	case FullSync:
+		return d.blockchain.HasBlock(hash, number)
+	case SnapSync:
+		return d.blockchain.HasFastBlock(hash, number)
+	default:
+		return d.lightchain.HasHeader(hash, number)
+	}
+}
+
 func (d *Downloader) findAncestorSpanSearch(p *peerConnection, mode SyncMode, remoteHeight, localHeight uint64, floor int64) (uint64, error) {
 	from, count, skip, max := calculateRequestSpan(remoteHeight, localHeight)
 
@@ -697,7 +894,7 @@ func (d *Downloader) findAncestorSpanSearch(p *peerConnection, mode SyncMode, re
 		h := hashes[i]
 		n := headers[i].Number.Uint64()
 
-		known := d.blockchain.HasBlock(h, n)
+		known := d.hasAncestor(mode, h, n)
 		if known {
 			number, hash = n, h
 			break
@@ -741,7 +938,7 @@ func (d *Downloader) findAncestorBinarySearch(p *peerConnection, mode SyncMode,
 		// Modify the search interval based on the response
 		h := hashes[0]
 		n := headers[0].Number.Uint64()
-		known := d.blockchain.HasBlock(h, n)
+		known := d.hasAncestor(mode, h, n)
 
 		if !known {
 			end = check
@@ -820,7 +1017,7 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64, head uint64) e
 		default:
 			// Header retrieval either timed out, or the peer failed in some strange way
 			// (e.g. disconnect). Consider the master peer bad and drop
-			d.dropPeer(p.id)
+			d.dropPeer(p.id, dropReason(err))
 
 			// Finish the sync gracefully instead of dumping the gathered data though
 			for _, ch := range []chan bool{d.queue.blockWakeCh, d.queue.receiptWakeCh} {
@@ -1063,6 +1260,9 @@ func (d *Downloader) processHeaders(origin uint64, td, ttd *big.Int, beaconMode
 				// L: Request new headers up from 11 (R's TD was higher, it must have something)
 				// R: Nothing to give
 				head := d.blockchain.CurrentBlock()
+				if d.getMode() == LightSync {
+					head = d.lightchain.CurrentHeader()
+				}
 				if !gotHeaders && td.Cmp(d.blockchain.GetTd(head.Hash(), head.Number.Uint64())) > 0 {
 					return errStallingPeer
 				}
@@ -1087,6 +1287,22 @@ func (d *Downloader) processHeaders(origin uint64, td, ttd *big.Int, beaconMode
 				chunkHeaders := headers[:limit]
 				chunkHashes := hashes[:limit]
 
+				// In snap sync the headers are imported right away, as the blocks
+				// up to the pivot are committed without execution. Light sync only
+				// ever imports headers, there is no content to retrieve.
+				if mode := d.getMode(); mode == SnapSync || mode == LightSync {
+					if n, err := d.lightchain.InsertHeaderChain(chunkHeaders); err != nil {
+						log.Warn("Invalid header encountered", "number", chunkHeaders[n].Number, "hash", chunkHashes[n], "parent", chunkHeaders[n].ParentHash, "err", err)
+						return fmt.Errorf("%w: %v", errInvalidChain, err)
+					}
+					if mode == LightSync {
+						headers = headers[limit:]
+						hashes = hashes[limit:]
+						origin += uint64(limit)
+						continue
+					}
+				}
+
 				// If we've reached the allowed number of pending headers, stall a bit
 				for d.queue.PendingBodies() >= maxQueuedHeaders || d.queue.PendingReceipts() >= maxQueuedHeaders {
 					select {
@@ -1173,6 +1389,226 @@ func (d *Downloader) processFullSyncContent(ttd *big.Int, beaconMode bool) error
 	}
 }
 
+// processSnapSyncContent takes fetch results from the queue and writes them to the
+// database. It also controls the synchronisation of state nodes of the pivot block.
+func (d *Downloader) processSnapSyncContent() error {
+	// Start syncing state of the reported head block. This should get us most of
+	// the state of the pivot block.
+	d.pivotLock.RLock()
+	sync := d.syncState(d.pivotHeader.Root)
+	d.pivotLock.RUnlock()
+
+	defer func() {
+		// The `sync` object is replaced every time the pivot moves. We need to
+		// defer close the very last active one, hence the lazy evaluation vs.
+		// calling defer sync.Cancel() !!!
+		sync.Cancel()
+	}()
+
+	closeOnErr := func(s *stateSync) {
+		if err := s.Wait(); err != nil && err != errCancelStateFetch && err != errCanceled && err != snap.ErrCancelled {
+			d.queue.Close() // wake up Results
+		}
+	}
+	go closeOnErr(sync)
+
+	// To cater for moving pivot points, track the pivot block and subsequently
+	// accumulated download results separately.
+	//
+	// These will be nil up to the point where we reach the pivot, and will only
+	// be set temporarily if the synced blocks are piling up, but the pivot is
+	// still busy downloading. In that case, we need to occasionally check for
+	// pivot moves, so need to unblock the loop. These fields will accumulate
+	// the results in the meantime.
+	//
+	// Note, there's no issue with memory piling up since after 64 blocks the
+	// pivot will forcefully move so these accumulators will be dropped.
+	var (
+		oldPivot *fetchResult   // Locked in pivot block, might change eventually
+		oldTail  []*fetchResult // Downloaded content after the pivot
+		timer    = time.NewTimer(time.Second)
+	)
+	defer timer.Stop()
+
+	for {
+		// Wait for the next batch of downloaded data to be available. If we have
+		// not yet reached the pivot point, wait blockingly as there's no need to
+		// spin-loop check for pivot moves. If we reached the pivot but have not
+		// yet processed it, check for results async, so we might notice pivot
+		// moves while state syncing. If the pivot was passed fully, block again
+		// as there's no more reason to check for pivot moves at all.
+		results := d.queue.Results(oldPivot == nil)
+		if len(results) == 0 {
+			// If pivot sync is done, stop
+			if d.committed.Load() {
+				return sync.Cancel()
+			}
+			// If sync failed, stop
+			select {
+			case <-d.cancelCh:
+				sync.Cancel()
+				return errCanceled
+			default:
+			}
+		}
+		if d.chainInsertHook != nil {
+			d.chainInsertHook(results)
+		}
+		// If we haven't downloaded the pivot block yet, check pivot staleness
+		// notifications from the header downloader
+		d.pivotLock.RLock()
+		pivot := d.pivotHeader
+		d.pivotLock.RUnlock()
+
+		if oldPivot == nil { // no results piling up, we can move the pivot
+			if !d.committed.Load() { // not yet passed the pivot, we can move the pivot
+				if pivot.Root != sync.root { // pivot position changed, we can move the pivot
+					sync.Cancel()
+					sync = d.syncState(pivot.Root)
+
+					go closeOnErr(sync)
+				}
+			}
+		} else { // results already piled up, consume before handling pivot move
+			results = append(append([]*fetchResult{oldPivot}, oldTail...), results...)
+		}
+		// Split around the pivot block and process the two sides via snap/full sync
+		if !d.committed.Load() {
+			latest := results[len(results)-1].Header
+			// If the height is above the pivot block by 2 sets, it means the pivot
+			// become stale in the network, and it was garbage collected, move to a
+			// new pivot.
+			//
+			// Note, we have `reorgProtHeaderDelay` number of blocks withheld, Those
+			// need to be taken into account, otherwise we're detecting the pivot move
+			// late and will drop peers due to unavailable state!!!
+			if height := latest.Number.Uint64(); height >= pivot.Number.Uint64()+2*uint64(fsMinFullBlocks)-uint64(reorgProtHeaderDelay) {
+				log.Warn("Pivot became stale, moving", "old", pivot.Number.Uint64(), "new", height-uint64(fsMinFullBlocks)+uint64(reorgProtHeaderDelay))
+				pivot = results[len(results)-1-fsMinFullBlocks+reorgProtHeaderDelay].Header // must exist as lower old pivot is uncommitted
+
+				d.pivotLock.Lock()
+				d.pivotHeader = pivot
+				d.pivotLock.Unlock()
+
+				// Write out the pivot into the database so a rollback beyond it will
+				// reenable snap sync
+				rawdb.WriteLastPivotNumber(d.stateDB, pivot.Number.Uint64())
+			}
+		}
+		P, beforeP, afterP := splitAroundPivot(pivot.Number.Uint64(), results)
+		if err := d.commitSnapSyncData(beforeP, sync); err != nil {
+			return err
+		}
+		if P != nil {
+			// If new pivot block found, cancel old state retrieval and restart
+			if oldPivot != P {
+				sync.Cancel()
+				sync = d.syncState(P.Header.Root)
+
+				go closeOnErr(sync)
+				oldPivot = P
+			}
+			// Wait for completion, occasionally checking for pivot staleness
+			timer.Reset(time.Second)
+			select {
+			case <-sync.done:
+				if sync.err != nil {
+					return sync.err
+				}
+				if err := d.commitPivotBlock(P); err != nil {
+					return err
+				}
+				oldPivot = nil
+
+			case <-timer.C:
+				oldTail = afterP
+				continue
+			}
+		}
+		// Snap sync done, pivot commit done, full import
+		if err := d.importBlockResults(afterP); err != nil {
+			return err
+		}
+	}
+}
+
+// splitAroundPivot splits the results around the pivot block, returning the
+// pivot result itself along with the results before and after it.
+func splitAroundPivot(pivot uint64, results []*fetchResult) (p *fetchResult, before, after []*fetchResult) {
+	if len(results) == 0 {
+		return nil, nil, nil
+	}
+	if lastNum := results[len(results)-1].Header.Number.Uint64(); lastNum < pivot {
+		// the pivot is somewhere in the future
+		return nil, results, nil
+	}
+	// This can also be optimized, but only happens very seldom
+	for _, result := range results {
+		num := result.Header.Number.Uint64()
+		switch {
+		case num < pivot:
+			before = append(before, result)
+		case num == pivot:
+			p = result
+		default:
+			after = append(after, result)
+		}
+	}
+	return p, before, after
+}
+
+// commitSnapSyncData writes the blocks and receipts below the pivot into the
+// database without executing them.
+func (d *Downloader) commitSnapSyncData(results []*fetchResult, stateSync *stateSync) error {
+	// Check for any early termination requests
+	if len(results) == 0 {
+		return nil
+	}
+	select {
+	case <-d.quitCh:
+		return errCancelContentProcessing
+	case <-stateSync.done:
+		if err := stateSync.Wait(); err != nil {
+			return err
+		}
+	default:
+	}
+	// Retrieve the batch of results to import
+	first, last := results[0].Header, results[len(results)-1].Header
+	log.Debug("Inserting snap-sync blocks", "items", len(results),
+		"firstnum", first.Number, "firsthash", first.Hash(),
+		"lastnumn", last.Number, "lasthash", last.Hash(),
+	)
+	blocks := make([]*types.Block, len(results))
+	receipts := make([]types.Receipts, len(results))
+	for i, result := range results {
+		blocks[i] = types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
+		receipts[i] = result.Receipts
+	}
+	if index, err := d.blockchain.InsertReceiptChain(blocks, receipts, d.ancientLimit); err != nil {
+		log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
+		return fmt.Errorf("%w: %v", errInvalidChain, err)
+	}
+	return nil
+}
+
+// commitPivotBlock commits the pivot block with its synced state as the new
+// head of the chain, from which on blocks are imported with full execution.
+func (d *Downloader) commitPivotBlock(result *fetchResult) error {
+	block := types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Uncles).WithWithdrawals(result.Withdrawals)
+	log.Debug("Committing snap sync pivot as new head", "number", block.Number(), "hash", block.Hash())
+
+	// Commit the pivot block as the new head, will require full sync from here on
+	if _, err := d.blockchain.InsertReceiptChain([]*types.Block{block}, []types.Receipts{result.Receipts}, d.ancientLimit); err != nil {
+		return err
+	}
+	if err := d.blockchain.SnapSyncCommitHead(block.Hash()); err != nil {
+		return err
+	}
+	d.committed.Store(true)
+	return nil
+}
+
 func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	// Check for any early termination requests
 	if len(results) == 0 {
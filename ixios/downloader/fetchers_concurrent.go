@@ -141,15 +141,19 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 			for _, peer := range d.peers.AllPeers() {
 				pending, stale := pending[peer.id], stales[peer.id]
 				if pending == nil && stale == nil {
-					idles = append(idles, peer)
-					caps = append(caps, queue.capacity(peer, time.Second))
+					// Skip peers unable to serve the items at all, like
+					// header-only ones asked for bodies or receipts
+					if capacity := queue.capacity(peer, time.Second); capacity > 0 {
+						idles = append(idles, peer)
+						caps = append(caps, capacity)
+					}
 				} else if stale != nil {
 					if waited := time.Since(stale.Sent); waited > timeoutGracePeriod {
 						// Request has been in flight longer than the grace period
 						// permitted it, consider the peer malicious attempting to
 						// stall the sync.
 						peer.log.Warn("Peer stalling, dropping", "waited", common.PrettyDuration(waited))
-						d.dropPeer(peer.id)
+						d.dropPeer(peer.id, eth.ErrRequestTimeout)
 					}
 				}
 			}
@@ -310,7 +314,7 @@ func (d *Downloader) concurrentFetch(queue typedQueue, beaconMode bool) error {
 			if fails > 2 {
 				queue.updateCapacity(peer, 0, 0)
 			} else {
-				d.dropPeer(peer.id)
+				d.dropPeer(peer.id, eth.ErrRequestTimeout)
 
 				// If this peer was the master peer, abort sync immediately
 				d.cancelLock.RLock()
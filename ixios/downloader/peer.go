@@ -52,6 +52,7 @@ type peerConnection struct {
 // Peer encapsulates the methods required to synchronise with a remote full peer.
 type Peer interface {
 	Head() (common.Hash, *big.Int)
+	HeaderOnly() bool
 	RequestHeadersByHash(common.Hash, int, int, bool, chan *eth.Response) (*eth.Request, error)
 	RequestHeadersByNumber(uint64, int, int, bool, chan *eth.Response) (*eth.Request, error)
 
@@ -107,8 +108,11 @@ func (p *peerConnection) HeaderCapacity(targetRTT time.Duration) int {
 }
 
 // BodyCapacity retrieves the peer's body download allowance based on its
-// previously discovered throughput.
+// previously discovered throughput. Header-only peers have none.
 func (p *peerConnection) BodyCapacity(targetRTT time.Duration) int {
+	if p.peer.HeaderOnly() {
+		return 0
+	}
 	cap := p.rates.Capacity(eth.BlockBodiesMsg, targetRTT)
 	if cap > MaxBlockFetch {
 		cap = MaxBlockFetch
@@ -117,8 +121,11 @@ func (p *peerConnection) BodyCapacity(targetRTT time.Duration) int {
 }
 
 // ReceiptCapacity retrieves the peers receipt download allowance based on its
-// previously discovered throughput.
+// previously discovered throughput. Header-only peers have none.
 func (p *peerConnection) ReceiptCapacity(targetRTT time.Duration) int {
+	if p.peer.HeaderOnly() {
+		return 0
+	}
 	cap := p.rates.Capacity(eth.ReceiptsMsg, targetRTT)
 	if cap > MaxReceiptFetch {
 		cap = MaxReceiptFetch
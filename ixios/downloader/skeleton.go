@@ -788,7 +788,7 @@ func (s *skeleton) executeTask(peer *peerConnection, req *headerRequest) {
 		// gone stale and monitor them. However, in that case too, we need a way
 		// to protect against malicious peers never responding, so it would need
 		// a second, hard-timeout mechanism.
-		s.drop(peer.id)
+		s.drop(peer.id, eth.ErrRequestTimeout)
 
 	case res := <-resCh:
 		// Headers successfully retrieved
@@ -948,7 +948,7 @@ func (s *skeleton) processResponse(res *headerResponse) (linked bool, merged boo
 			for i := 0; i < requestHeaders; i++ {
 				s.scratchSpace[i] = nil
 			}
-			s.drop(s.scratchOwners[0])
+			s.drop(s.scratchOwners[0], eth.ErrBadChain)
 			s.scratchOwners[0] = ""
 			break
 		}
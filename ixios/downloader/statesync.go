@@ -100,10 +100,9 @@ func newStateSync(d *Downloader, root common.Hash) *stateSync {
 // it finishes, and finally notifying any goroutines waiting for the loop to
 // finish.
 func (s *stateSync) run() {
-	// 123REMOVED123  :  snap removed
-	//close(s.started)
-	//s.err = s.d.SnapSyncer.Sync(s.root, s.cancel)
-	//close(s.done)
+	close(s.started)
+	s.err = s.d.SnapSyncer.Sync(s.root, s.cancel)
+	close(s.done)
 }
 
 // Wait blocks until the sync is done or canceled.
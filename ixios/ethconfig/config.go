@@ -15,6 +15,7 @@
 package ethconfig
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/ixios-io/ixiosSpark/common"
@@ -77,6 +78,14 @@ type Config struct {
 	NetworkId uint64
 	SyncMode  downloader.SyncMode
 
+	// SyncCheckpoint is the hash of a trusted epoch checkpoint header to snap or
+	// light sync an empty chain from, instead of verifying every seal since genesis.
+	SyncCheckpoint common.Hash `toml:",omitempty"`
+
+	// SyncCheckpointTD is the total difficulty of the chain up to and including
+	// the sync checkpoint, which cannot be derived without the preceding history.
+	SyncCheckpointTD *big.Int `toml:",omitempty"`
+
 	// This can be set to list of enrtree:// URLs which will be queried for
 	// for nodes to connect to.
 	EthDiscoveryURLs  []string
@@ -16,6 +16,7 @@ package fetcher
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -70,8 +71,9 @@ type headersInsertFn func(headers []*types.Header) (int, error)
 // chainInsertFn is a callback type to insert a batch of blocks into the local chain.
 type chainInsertFn func(types.Blocks) (int, error)
 
-// peerDropFn is a callback type for dropping a peer detected as malicious.
-type peerDropFn func(id string)
+// peerDropFn is a callback type for dropping a peer detected as malicious, along
+// with the misbehaviour it is dropped for.
+type peerDropFn func(id string, reason error)
 
 // blockAnnounce is the hash notification of the availability of a new block in the
 // network.
@@ -457,7 +459,7 @@ func (f *BlockFetcher) loop() {
 								// waiting for a catchup. With an unresponsive
 								// peer however, it's a protocol violation.
 								log.Error("Timeout reached while fetching headers", "peer", peer, "hash", hash)
-								f.dropPeer(peer)
+								f.dropPeer(peer, eth.ErrRequestTimeout)
 							}
 						}(hash)
 					}
@@ -516,7 +518,7 @@ func (f *BlockFetcher) loop() {
 						// waiting for a catchup. With an unresponsive
 						// peer however, it's a protocol violation.
 						log.Error("Timeout reached while fetching block body", "peer", peer)
-						f.dropPeer(peer)
+						f.dropPeer(peer, eth.ErrRequestTimeout)
 					}
 				}(peer, hashes)
 			}
@@ -545,7 +547,7 @@ func (f *BlockFetcher) loop() {
 					// If the delivered header does not match the promised number, drop the announcer
 					if header.Number.Uint64() != announce.number {
 						log.Trace("Invalid block number fetched", "peer", announce.origin, "hash", header.Hash(), "announced", announce.number, "provided", header.Number)
-						f.dropPeer(announce.origin)
+						f.dropPeer(announce.origin, fmt.Errorf("%w: announced number %d, delivered %d", eth.ErrBadChain, announce.number, header.Number))
 						f.forgetHash(hash)
 						continue
 					}
@@ -774,7 +776,7 @@ func (f *BlockFetcher) importHeaders(peer string, header *types.Header) {
 		// Validate the header and if something went wrong, drop the peer
 		if err := f.verifyHeader(header); err != nil && err != consensus.ErrFutureBlock {
 			log.Debug("Propagated header verification failed", "peer", peer, "number", header.Number, "hash", hash, "err", err)
-			f.dropPeer(peer)
+			f.dropPeer(peer, fmt.Errorf("%w: %v", eth.ErrBadChain, err))
 			return
 		}
 		// Run the actual import and log any issues
@@ -819,7 +821,7 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 		default:
 			// Something went very wrong, drop the peer
 			log.Debug("Propagated block verification failed", "peer", peer, "number", block.Number(), "hash", hash, "err", err)
-			f.dropPeer(peer)
+			f.dropPeer(peer, fmt.Errorf("%w: %v", eth.ErrBadChain, err))
 			return
 		}
 		// Run the actual import and log any issues
@@ -26,6 +26,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/params"
 	"github.com/ixios-io/ixiosSpark/rpc"
+	"github.com/ixios-io/ixiosSpark/zeta"
 	"golang.org/x/exp/slices"
 )
 
@@ -211,6 +212,10 @@ func (oracle *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
 	if price.Cmp(oracle.maxPrice) > 0 {
 		price = new(big.Int).Set(oracle.maxPrice)
 	}
+	// Never suggest less than the minimum gas price of the next block
+	if minPrice := zeta.Value(oracle.backend.ChainConfig(), new(big.Int).Add(head.Number, common.Big1), head.Time); price.Cmp(minPrice) < 0 {
+		price = minPrice
+	}
 	oracle.cacheLock.Lock()
 	oracle.lastHead = headHash
 	oracle.lastPrice = price
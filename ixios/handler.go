@@ -15,6 +15,7 @@ package ixios
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"os"
@@ -32,6 +33,7 @@ import (
 	"github.com/ixios-io/ixiosSpark/ixios/downloader"
 	"github.com/ixios-io/ixiosSpark/ixios/fetcher"
 	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/snap"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/p2p"
@@ -82,10 +84,13 @@ type handlerConfig struct {
 	Chain          *core.BlockChain       // Blockchain to serve data from
 	TxPool         txPool                 // Transaction pool to propagate from
 	Network        uint64                 // Network identifier to advertise
-	Sync           downloader.SyncMode    // Whether to snap or full sync
+	Sync           downloader.SyncMode    // Whether to snap, full or light sync
+	Checkpoint     common.Hash            // Trusted checkpoint header to snap or light sync from
+	CheckpointTD   *big.Int               // Total difficulty of the trusted checkpoint header
 	BloomCache     uint64                 // Megabytes to alloc for snap sync bloom
 	EventMux       *event.TypeMux         // Legacy event mux, deprecate for `feed`
 	RequiredBlocks map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
+	Reputation     peerReputation         // Reputation store of remote nodes, nil to not track any
 }
 
 type handler struct {
@@ -93,6 +98,7 @@ type handler struct {
 	forkFilter forkid.Filter // Fork ID filter, constant across the lifetime of the node
 
 	snapSync atomic.Bool // Flag whether snap sync is enabled (gets disabled if we already have blocks)
+	light    bool        // Flag whether only headers are synced and verified, without any state
 	synced   atomic.Bool // Flag whether we're considered synchronised (enables transaction processing)
 
 	database        kvdb.Database
@@ -105,6 +111,7 @@ type handler struct {
 	blockFetcher *fetcher.BlockFetcher
 	txFetcher    *fetcher.TxFetcher
 	peers        *peerSet
+	reputation   peerReputation
 
 	eventMux      *event.TypeMux
 	txsCh         chan core.NewTxsEvent
@@ -137,12 +144,18 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		txpool:         config.TxPool,
 		chain:          config.Chain,
 		peers:          newPeerSet(),
+		reputation:     config.Reputation,
 		requiredBlocks: config.RequiredBlocks,
 		quitSync:       make(chan struct{}),
 		handlerDoneCh:  make(chan struct{}),
 		handlerStartCh: make(chan struct{}),
 	}
-	if config.Sync == downloader.FullSync {
+	if config.Sync == downloader.LightSync {
+		// Header-only nodes have no state to sync, they verify the headers and
+		// retrieve proofs of the state they are queried for from full peers.
+		h.light = true
+		log.Info("Enabled header-only light mode")
+	} else if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
 		// block is ahead, so snap sync was enabled for this node at a certain point.
 		// The scenarios where this can happen is
@@ -175,7 +188,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		return nil, errors.New("snap sync not supported with snapshots disabled")
 	}
 	// Construct the downloader (long sync)
-	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, nil, h.removePeer, h.enableSyncedFeatures)
+	h.downloader = downloader.New(config.Checkpoint, config.CheckpointTD, config.Database, h.eventMux, h.chain, nil, h.dropPeer, h.enableSyncedFeatures)
 	if ttd := h.chain.Config().TerminalTotalDifficulty; ttd != nil {
 		if h.chain.Config().TerminalTotalDifficultyPassed {
 			log.Info("Chain post-merge, sync via beacon client")
@@ -194,9 +207,6 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	validator := func(header *types.Header) error {
 		return h.chain.Engine().VerifyHeader(h.chain, header)
 	}
-	heighter := func() uint64 {
-		return h.chain.CurrentBlock().Number.Uint64()
-	}
 	inserter := func(blocks types.Blocks) (int, error) {
 		/*if !h.synced.Load() {
 			log.Warn("Syncing, discarded propagated block", "number", blocks[0].Number(), "hash", blocks[0].Hash())
@@ -204,7 +214,11 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		}*/
 		return h.chain.InsertChain(blocks)
 	}
-	h.blockFetcher = fetcher.NewBlockFetcher(false, nil, h.chain.GetBlockByHash, validator, h.BroadcastBlock, heighter, nil, inserter, h.removePeer)
+	if h.light {
+		h.blockFetcher = fetcher.NewBlockFetcher(true, h.chain.GetHeaderByHash, nil, validator, nil, h.height, h.chain.InsertHeaderChain, nil, h.dropPeer)
+	} else {
+		h.blockFetcher = fetcher.NewBlockFetcher(false, nil, h.chain.GetBlockByHash, validator, h.BroadcastBlock, h.height, nil, inserter, h.dropPeer)
+	}
 
 	fetchTx := func(peer string, hashes []common.Hash) error {
 		p := h.peers.peer(peer)
@@ -216,11 +230,23 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	addTxs := func(txs []*types.Transaction) []error {
 		return h.txpool.Add(txs, false, false)
 	}
-	h.txFetcher = fetcher.NewTxFetcher(h.txpool.Has, addTxs, fetchTx, h.removePeer)
+	dropTxPeer := func(peer string) {
+		h.dropPeer(peer, errTxAnnounceMismatch)
+	}
+	h.txFetcher = fetcher.NewTxFetcher(h.txpool.Has, addTxs, fetchTx, dropTxPeer)
 	h.chainSync = newChainSyncer(h)
 	return h, nil
 }
 
+// height returns the number of the local chain head, the header chain one in
+// light mode.
+func (h *handler) height() uint64 {
+	if h.light {
+		return h.chain.CurrentHeader().Number.Uint64()
+	}
+	return h.chain.CurrentBlock().Number.Uint64()
+}
+
 // protoTracker tracks the number of active protocol handlers.
 func (h *handler) protoTracker() {
 	defer h.wg.Done()
@@ -274,14 +300,20 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 		td      = h.chain.GetTd(hash, number)
 	)
 	forkID := forkid.NewID(h.chain.Config(), genesis, number, head.Time)
-	if err := peer.Handshake(h.networkID, td, hash, genesis.Hash(), forkID, h.forkFilter); err != nil {
+	if err := peer.Handshake(h.networkID, td, hash, genesis.Hash(), forkID, h.forkFilter, h.light); err != nil {
 		peer.Log().Debug("IxiosSpark handshake failed", "err", err)
 		return err
 	}
-	// Ignore maxPeers if this is a trusted peer
+	// Ignore maxPeers if this is a trusted peer, otherwise make room by evicting
+	// the worst reputed peer if it scores below the new one
 	if !peer.Peer.Info().Network.Trusted {
 		if h.peers.len() >= h.maxPeers {
-			return p2p.DiscTooManyPeers
+			worst := h.peers.worstPeer(h.peerScore, h.peerScore(peer.ID()))
+			if worst == nil {
+				return p2p.DiscTooManyPeers
+			}
+			worst.Log().Debug("Evicting peer for a better reputed one", "peer", peer.ID())
+			worst.Peer.Disconnect(p2p.DiscTooManyPeers)
 		}
 	}
 	peer.Log().Debug("IxiosSpark peer connected", "name", peer.Name())
@@ -347,10 +379,11 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 					return
 				}
 				peer.Log().Debug("Peer required block verified", "number", number, "hash", hash)
+				h.adjustScore(peer.ID(), scoreUseful, "required block verified")
 				res.Done <- nil
 			case <-timeout.C:
 				peer.Log().Warn("Required block challenge timed out, dropping", "addr", peer.RemoteAddr(), "type", peer.Name())
-				h.removePeer(peer.ID())
+				h.dropPeer(peer.ID(), fmt.Errorf("%w: required block challenge", eth.ErrRequestTimeout))
 			}
 		}(number, hash, req)
 	}
@@ -358,6 +391,33 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	return handler(peer)
 }
 
+// runSnapExtension registers a `ixsnap` peer into the joint eth/snap peerset and
+// starts handling inbound messages. As `ixsnap` is only a satellite protocol to
+// `ixios`, peers must also be running the latter.
+func (h *handler) runSnapExtension(peer *snap.Peer, handler snap.Handler) error {
+	if !h.incHandlers() {
+		return p2p.DiscQuitting
+	}
+	defer h.decHandlers()
+
+	if !peer.RunningCap(eth.ProtocolName, eth.ProtocolVersions) {
+		return errSnapWithoutEth
+	}
+	if err := h.peers.registerSnapExtension(peer); err != nil {
+		peer.Log().Debug("Snap extension registration failed", "err", err)
+		return err
+	}
+	defer h.peers.unregisterSnapExtension(peer.ID())
+
+	if err := h.downloader.SnapSyncer.Register(peer); err != nil {
+		peer.Log().Error("Failed to register peer in snap syncer", "err", err)
+		return err
+	}
+	defer h.downloader.SnapSyncer.Unregister(peer.ID())
+
+	return handler(peer)
+}
+
 // removePeer requests disconnection of a peer.
 func (h *handler) removePeer(id string) {
 	peer := h.peers.peer(id)
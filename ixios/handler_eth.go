@@ -51,7 +51,7 @@ func (h *ethHandler) PeerInfo(id enode.ID) interface{} {
 // AcceptTxs retrieves whether transaction processing is enabled on the node
 // or if inbound transactions should simply be dropped.
 func (h *ethHandler) AcceptTxs() bool {
-	return h.synced.Load()
+	return !h.light && h.synced.Load()
 }
 
 // Handle is invoked from a peer's message handler when it receives a new remote
@@ -75,9 +75,11 @@ func (h *ethHandler) Handle(peer *eth.Peer, packet eth.Packet) error {
 				return errors.New("disallowed broadcast blob transaction")
 			}
 		}
+		(*handler)(h).penalizeUnderpriced(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, false)
 
 	case *eth.PooledTransactionsResponse:
+		(*handler)(h).penalizeUnderpriced(peer, *packet)
 		return h.txFetcher.Enqueue(peer.ID(), *packet, true)
 
 	default:
@@ -93,12 +95,26 @@ func (h *ethHandler) handleBlockAnnounces(peer *eth.Peer, hashes []common.Hash,
 		unknownHashes  = make([]common.Hash, 0, len(hashes))
 		unknownNumbers = make([]uint64, 0, len(numbers))
 	)
+	var (
+		height = (*handler)(h).height()
+		stale  int
+	)
 	for i := 0; i < len(hashes); i++ {
-		if !h.chain.HasBlock(hashes[i], numbers[i]) {
+		if numbers[i]+staleAnnounceDist < height {
+			stale++
+		}
+		known := h.chain.HasBlock(hashes[i], numbers[i])
+		if h.light {
+			known = h.chain.HasHeader(hashes[i], numbers[i])
+		}
+		if !known {
 			unknownHashes = append(unknownHashes, hashes[i])
 			unknownNumbers = append(unknownNumbers, numbers[i])
 		}
 	}
+	if stale > 0 {
+		(*handler)(h).adjustScore(peer.ID(), scoreStaleHead, fmt.Sprintf("%d stale block announcements", stale))
+	}
 	for i := 0; i < len(unknownHashes); i++ {
 		// note: goroutine added
 		go func() {
@@ -15,6 +15,7 @@ package ixios
 
 import (
 	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/snap"
 )
 
 // ethPeerInfo represents a short summary of the `eth` sub-protocol metadata known
@@ -34,3 +35,21 @@ func (p *ethPeer) info() *ethPeerInfo {
 		Version: p.Version(),
 	}
 }
+
+// snapPeerInfo represents a short summary of the `ixsnap` sub-protocol metadata
+// known about a connected peer.
+type snapPeerInfo struct {
+	Version uint `json:"version"` // State sync protocol version negotiated
+}
+
+// snapPeer is a wrapper around snap.Peer to maintain a few extra metadata.
+type snapPeer struct {
+	*snap.Peer
+}
+
+// info gathers and returns some `ixsnap` protocol metadata known about a peer.
+func (p *snapPeer) info() *snapPeerInfo {
+	return &snapPeerInfo{
+		Version: p.Version(),
+	}
+}
@@ -16,10 +16,12 @@ package ixios
 import (
 	"errors"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/snap"
 	"github.com/ixios-io/ixiosSpark/p2p"
 )
 
@@ -44,7 +46,8 @@ var (
 // peerSet represents the collection of active peers currently participating in
 // the `eth` protocol, with or without the `snap` extension.
 type peerSet struct {
-	peers  map[string]*ethPeer // Peers connected on the `eth` protocol
+	peers  map[string]*ethPeer  // Peers connected on the `eth` protocol
+	snaps  map[string]*snapPeer // Peers connected on the `ixsnap` protocol
 	lock   sync.RWMutex
 	closed bool
 	quitCh chan struct{} // Quit channel to signal termination
@@ -54,6 +57,7 @@ type peerSet struct {
 func newPeerSet() *peerSet {
 	return &peerSet{
 		peers:  make(map[string]*ethPeer),
+		snaps:  make(map[string]*snapPeer),
 		quitCh: make(chan struct{}),
 	}
 }
@@ -93,6 +97,43 @@ func (ps *peerSet) unregisterPeer(id string) error {
 	return nil
 }
 
+// registerSnapExtension injects a new `ixsnap` peer into the working set, or
+// returns an error if the peer is already known.
+func (ps *peerSet) registerSnapExtension(peer *snap.Peer) error {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if ps.closed {
+		return errPeerSetClosed
+	}
+	id := peer.ID()
+	if _, ok := ps.snaps[id]; ok {
+		return errPeerAlreadyRegistered
+	}
+	ps.snaps[id] = &snapPeer{Peer: peer}
+	return nil
+}
+
+// unregisterSnapExtension removes a remote `ixsnap` peer from the active set.
+func (ps *peerSet) unregisterSnapExtension(id string) error {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	if _, ok := ps.snaps[id]; !ok {
+		return errPeerNotRegistered
+	}
+	delete(ps.snaps, id)
+	return nil
+}
+
+// snapPeer retrieves the registered `ixsnap` peer with the given id.
+func (ps *peerSet) snapPeer(id string) *snapPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	return ps.snaps[id]
+}
+
 // peer retrieves the registered peer with the given id.
 func (ps *peerSet) peer(id string) *ethPeer {
 	ps.lock.RLock()
@@ -142,23 +183,77 @@ func (ps *peerSet) len() int {
 }
 
 // peerWithHighestTD retrieves the known peer with the currently highest total
-// difficulty, but below the given PoS switchover threshold.
-func (ps *peerSet) peerWithHighestTD() *eth.Peer {
+// difficulty, but below the given PoS switchover threshold. Header-only peers are
+// skipped, they cannot serve the blocks of their chain. Peers with a negative
+// reputation score are only picked if there are no others.
+func (ps *peerSet) peerWithHighestTD(score func(id string) int) *eth.Peer {
 	ps.lock.RLock()
 	defer ps.lock.RUnlock()
 
 	var (
-		bestPeer *eth.Peer
-		bestTd   *big.Int
+		bestPeer      *eth.Peer
+		bestTd        *big.Int
+		bestReputable bool
 	)
 	for _, p := range ps.peers {
-		if _, td := p.Head(); bestPeer == nil || td.Cmp(bestTd) > 0 {
-			bestPeer, bestTd = p.Peer, td
+		if p.HeaderOnly() {
+			continue
+		}
+		_, td := p.Head()
+		reputable := score(p.ID()) >= 0
+		if bestPeer == nil || (reputable && !bestReputable) || (reputable == bestReputable && td.Cmp(bestTd) > 0) {
+			bestPeer, bestTd, bestReputable = p.Peer, td, reputable
 		}
 	}
 	return bestPeer
 }
 
+// peersWithVersion retrieves the full peers running at least the given protocol
+// version, best reputed first and the ones with the higher total difficulty,
+// the most likely to hold recent data, first among equally reputed peers.
+func (ps *peerSet) peersWithVersion(version uint, score func(id string) int) []*ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	var (
+		list   = make([]*ethPeer, 0, len(ps.peers))
+		scores = make(map[string]int, len(ps.peers))
+	)
+	for _, p := range ps.peers {
+		if p.Version() >= version && !p.HeaderOnly() {
+			list = append(list, p)
+			scores[p.ID()] = score(p.ID())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if si, sj := scores[list[i].ID()], scores[list[j].ID()]; si != sj {
+			return si > sj
+		}
+		_, tdi := list[i].Head()
+		_, tdj := list[j].Head()
+		return tdi.Cmp(tdj) > 0
+	})
+	return list
+}
+
+// worstPeer retrieves the untrusted peer with the lowest reputation score, if it
+// scores below the given one.
+func (ps *peerSet) worstPeer(score func(id string) int, below int) *ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	var worst *ethPeer
+	for _, p := range ps.peers {
+		if p.Peer.Info().Network.Trusted {
+			continue
+		}
+		if s := score(p.ID()); s < below {
+			worst, below = p, s
+		}
+	}
+	return worst
+}
+
 // close disconnects all peers.
 func (ps *peerSet) close() {
 	ps.lock.Lock()
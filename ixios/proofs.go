@@ -0,0 +1,239 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package ixios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/rawdb"
+	"github.com/ixios-io/ixiosSpark/core/state"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/kvdb"
+	"github.com/ixios-io/ixiosSpark/rlp"
+	"github.com/ixios-io/ixiosSpark/trie"
+	"github.com/ixios-io/ixiosSpark/triedb"
+)
+
+// proofTimeout is the maximum time to wait for a peer to deliver proofs.
+const proofTimeout = 5 * time.Second
+
+var (
+	// errNoProofPeers is returned if state is read in light mode without any
+	// peers to retrieve proofs from.
+	errNoProofPeers = errors.New("no peers to retrieve state proofs from")
+
+	// errProofTimeout is returned if a peer fails to deliver proofs in time.
+	errProofTimeout = errors.New("proof request timed out")
+)
+
+// proofState retrieves the Merkle proofs of the given accounts and storage slots
+// of theirs in the state with the given root from full peers, returning a state
+// backed by the proofs alone. This is how header-only nodes read state: proofs
+// are verified against the root so peers need not be trusted, and any reads
+// beyond the proven accounts and slots fail.
+func (h *handler) proofState(ctx context.Context, root common.Hash, accounts []common.Address, slots [][]common.Hash) (*state.StateDB, error) {
+	peers := h.peers.peersWithVersion(eth.PROTOCOL_VERSION_2, h.peerScore)
+	if len(peers) == 0 {
+		return nil, errNoProofPeers
+	}
+	var err error
+	for _, peer := range peers {
+		var proofs [][]byte
+		if proofs, err = h.fetchProofs(ctx, peer, root, accounts, slots); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			peer.Log().Debug("Failed to retrieve proofs", "root", root, "err", err)
+			h.penalizeTimeout(peer, err)
+			continue
+		}
+		db := rawdb.NewMemoryDatabase()
+		for _, node := range proofs {
+			db.Put(crypto.Keccak256(node), node)
+		}
+		// Peers lacking the state deliver incomplete proofs, try the next one
+		if err = verifyProofs(db, root, accounts, slots); err != nil {
+			peer.Log().Debug("Invalid proofs delivered", "root", root, "err", err)
+			continue
+		}
+		h.adjustScore(peer.ID(), scoreUseful, "state proofs verified")
+		return state.New(root, state.NewDatabaseWithNodeDB(db, triedb.NewDatabase(db, nil)), nil)
+	}
+	return nil, fmt.Errorf("state %x unavailable: %v", root, err)
+}
+
+// fetchProofs requests the Merkle proofs of the given accounts and storage slots
+// from a peer and waits for them to be delivered.
+func (h *handler) fetchProofs(ctx context.Context, peer *ethPeer, root common.Hash, accounts []common.Address, slots [][]common.Hash) ([][]byte, error) {
+	res, err := awaitProofs(ctx, func(sink chan *eth.Response) (*eth.Request, error) {
+		return peer.RequestProofs(root, accounts, slots, sink)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *res.(*eth.ProofsResponse), nil
+}
+
+// inclusionProof retrieves the proof of inclusion of the transaction with the
+// given hash, or of its receipt, in its block from full peers, verified against
+// the local canonical chain. Nil is returned if no peer knows the transaction.
+func (h *handler) inclusionProof(ctx context.Context, hash common.Hash, receipt bool) (*types.InclusionProof, error) {
+	peers := h.peers.peersWithVersion(eth.PROTOCOL_VERSION_3, h.peerScore)
+	if len(peers) == 0 {
+		return nil, errNoProofPeers
+	}
+	var err error
+	for _, peer := range peers {
+		// Receipts do not commit to their transaction, so prove the transaction
+		// too to tie the receipt to it
+		proof, perr := h.fetchInclusionProof(ctx, peer, hash, false)
+		if perr == nil && proof != nil && receipt {
+			txProof := proof
+			if proof, perr = h.fetchInclusionProof(ctx, peer, hash, true); perr == nil && proof != nil {
+				if proof.BlockHash != txProof.BlockHash || proof.Index != txProof.Index {
+					perr = errors.New("receipt proven for another transaction")
+				}
+			}
+		}
+		if perr != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			peer.Log().Debug("Failed to retrieve inclusion proof", "hash", hash, "receipt", receipt, "err", perr)
+			h.penalizeTimeout(peer, perr)
+			err = perr
+			continue
+		}
+		if proof != nil {
+			h.adjustScore(peer.ID(), scoreUseful, "inclusion proof verified")
+			return proof, nil
+		}
+	}
+	return nil, err
+}
+
+// fetchInclusionProof requests the proof of inclusion of the transaction with
+// the given hash, or of its receipt, from a peer and verifies it against the
+// local canonical chain. Nil is returned if the peer does not know it.
+func (h *handler) fetchInclusionProof(ctx context.Context, peer *ethPeer, hash common.Hash, receipt bool) (*types.InclusionProof, error) {
+	res, err := awaitProofs(ctx, func(sink chan *eth.Response) (*eth.Request, error) {
+		if receipt {
+			return peer.RequestReceiptProofs([]common.Hash{hash}, sink)
+		}
+		return peer.RequestTransactionProofs([]common.Hash{hash}, sink)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var proofs []types.InclusionProof
+	if receipt {
+		proofs = *res.(*eth.ReceiptProofsResponse)
+	} else {
+		proofs = *res.(*eth.TransactionProofsResponse)
+	}
+	if len(proofs) != 1 {
+		return nil, fmt.Errorf("%d proofs delivered for 1 requested", len(proofs))
+	}
+	proof := &proofs[0]
+	if proof.BlockHash == (common.Hash{}) {
+		return nil, nil
+	}
+	header := h.chain.GetHeader(proof.BlockHash, proof.BlockNumber)
+	if header == nil || h.chain.GetCanonicalHash(proof.BlockNumber) != proof.BlockHash {
+		return nil, fmt.Errorf("proof for non-canonical block %d [%x]", proof.BlockNumber, proof.BlockHash)
+	}
+	root := header.TxHash
+	if receipt {
+		root = header.ReceiptHash
+	}
+	if err := trie.VerifyInclusionProof(root, proof); err != nil {
+		return nil, err
+	}
+	if !receipt {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(proof.Value); err != nil {
+			return nil, err
+		}
+		if tx.Hash() != hash {
+			return nil, fmt.Errorf("transaction %x proven instead of %x", tx.Hash(), hash)
+		}
+	}
+	return proof, nil
+}
+
+// penalizeTimeout lowers the reputation of a peer if the error retrieving proofs
+// from it was a timeout. Peers lacking the requested state are not penalized.
+func (h *handler) penalizeTimeout(peer *ethPeer, err error) {
+	if errors.Is(err, errProofTimeout) {
+		h.adjustScore(peer.ID(), scoreTimeout, err.Error())
+	}
+}
+
+// awaitProofs sends a proof request to a peer and waits for the response to be
+// delivered.
+func awaitProofs(ctx context.Context, request func(chan *eth.Response) (*eth.Request, error)) (interface{}, error) {
+	resCh := make(chan *eth.Response)
+
+	req, err := request(resCh)
+	if err != nil {
+		return nil, err
+	}
+	defer req.Close()
+
+	timeout := time.NewTimer(proofTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timeout.C:
+		return nil, errProofTimeout
+	case res := <-resCh:
+		res.Done <- nil
+		return res.Res, nil
+	}
+}
+
+// verifyProofs checks that the proof nodes in db prove the presence or absence
+// of the given accounts and storage slots in the state with the given root.
+func verifyProofs(db kvdb.KeyValueReader, root common.Hash, accounts []common.Address, slots [][]common.Hash) error {
+	for i, address := range accounts {
+		blob, err := trie.VerifyProof(root, crypto.Keccak256(address.Bytes()), db)
+		if err != nil {
+			return fmt.Errorf("account %x: %v", address, err)
+		}
+		if blob == nil || i >= len(slots) {
+			continue
+		}
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			return fmt.Errorf("account %x: %v", address, err)
+		}
+		if account.Root == types.EmptyRootHash {
+			continue
+		}
+		for _, slot := range slots[i] {
+			if _, err := trie.VerifyProof(account.Root, crypto.Keccak256(slot.Bytes()), db); err != nil {
+				return fmt.Errorf("account %x slot %x: %v", address, slot, err)
+			}
+		}
+	}
+	return nil
+}
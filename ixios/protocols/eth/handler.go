@@ -42,6 +42,18 @@ const (
 	// maxReceiptsServe is the maximum number of block receipts to serve. This
 	// number is mostly there to limit the number of disk lookups.
 	maxReceiptsServe = 8192
+
+	// maxProofAccountsServe is the maximum number of accounts to prove in a
+	// single reply, each proof costing a handful of trie lookups.
+	maxProofAccountsServe = 256
+
+	// maxProofSlotsServe is the maximum number of storage slots to prove in a
+	// single reply.
+	maxProofSlotsServe = 1024
+
+	// maxInclusionProofsServe is the maximum number of transaction or receipt
+	// inclusion proofs to serve, each requiring a whole block to be hashed.
+	maxInclusionProofsServe = 64
 )
 
 // Handler is a callback to invoke from an outside runner after the boilerplate
@@ -166,6 +178,12 @@ var ixiosMessage = map[uint64]msgHandler{
 	BlockBodiesMsg:                handleBlockBodies,
 	GetReceiptsMsg:                handleGetReceipts,
 	ReceiptsMsg:                   handleReceipts,
+	GetProofsMsg:                  handleGetProofs,
+	ProofsMsg:                     handleProofs,
+	GetReceiptProofsMsg:           handleGetReceiptProofs,
+	ReceiptProofsMsg:              handleReceiptProofs,
+	GetTransactionProofsMsg:       handleGetTransactionProofs,
+	TransactionProofsMsg:          handleTransactionProofs,
 	GetPooledTransactionsMsg:      handleGetPooledTransactions,
 	PooledTransactionsMsg:         handlePooledTransactions,
 }
@@ -20,9 +20,11 @@ import (
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/core"
 	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/log"
 	"github.com/ixios-io/ixiosSpark/rlp"
 	"github.com/ixios-io/ixiosSpark/trie"
+	"github.com/ixios-io/ixiosSpark/trie/trienode"
 )
 
 func handleGetBlockHeaders(backend Backend, msg Decoder, peer *Peer) error {
@@ -270,6 +272,158 @@ func ServiceGetReceiptsQuery(chain *core.BlockChain, query GetReceiptsRequest) [
 	return receipts
 }
 
+func handleGetProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// Decode the state proof retrieval message
+	var query GetProofsPacket
+	if err := msg.Decode(&query); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	response := ServiceGetProofsQuery(backend.Chain(), query.GetProofsRequest)
+	return peer.ReplyProofs(query.RequestId, response)
+}
+
+// ServiceGetProofsQuery assembles the response to a proof query. It is exposed
+// to allow external packages to test protocol behavior.
+func ServiceGetProofsQuery(chain *core.BlockChain, query *GetProofsRequest) [][]byte {
+	// Retrieve the requested state and bail out if non existent
+	tr, err := trie.NewStateTrie(trie.StateTrieID(query.Root), chain.TrieDB())
+	if err != nil {
+		return nil
+	}
+	// Gather proofs until the fetch or network limits is reached
+	var (
+		proof = trienode.NewProofSet()
+		slots int
+	)
+	for i, address := range query.Accounts {
+		if proof.DataSize() >= softResponseLimit || i >= maxProofAccountsServe {
+			break
+		}
+		if err := tr.Prove(crypto.Keccak256(address.Bytes()), proof); err != nil {
+			log.Debug("Failed to prove account", "root", query.Root, "address", address, "err", err)
+			return nil
+		}
+		if i >= len(query.Slots) || len(query.Slots[i]) == 0 {
+			continue
+		}
+		// The proof of a missing or storageless account proves its slots empty
+		account, err := tr.GetAccount(address)
+		if err != nil {
+			return nil
+		}
+		if account == nil || account.Root == types.EmptyRootHash {
+			continue
+		}
+		id := trie.StorageTrieID(query.Root, crypto.Keccak256Hash(address.Bytes()), account.Root)
+		st, err := trie.NewStateTrie(id, chain.TrieDB())
+		if err != nil {
+			return nil
+		}
+		for _, slot := range query.Slots[i] {
+			if slots >= maxProofSlotsServe {
+				break
+			}
+			if err := st.Prove(crypto.Keccak256(slot.Bytes()), proof); err != nil {
+				log.Debug("Failed to prove storage slot", "root", query.Root, "address", address, "slot", slot, "err", err)
+				return nil
+			}
+			slots++
+		}
+	}
+	var proofs [][]byte
+	for _, blob := range proof.List() {
+		proofs = append(proofs, blob)
+	}
+	return proofs
+}
+
+func handleGetReceiptProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// Decode the receipt proof retrieval message
+	var query GetReceiptProofsPacket
+	if err := msg.Decode(&query); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	response := ServiceGetReceiptProofsQuery(backend.Chain(), query.GetReceiptProofsRequest)
+	return peer.ReplyReceiptProofs(query.RequestId, response)
+}
+
+// ServiceGetReceiptProofsQuery assembles the response to a receipt proof query.
+// It is exposed to allow external packages to test protocol behavior.
+func ServiceGetReceiptProofsQuery(chain *core.BlockChain, query GetReceiptProofsRequest) []types.InclusionProof {
+	return serviceInclusionProofs(chain, query, true)
+}
+
+func handleGetTransactionProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// Decode the transaction proof retrieval message
+	var query GetTransactionProofsPacket
+	if err := msg.Decode(&query); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	response := ServiceGetTransactionProofsQuery(backend.Chain(), query.GetTransactionProofsRequest)
+	return peer.ReplyTransactionProofs(query.RequestId, response)
+}
+
+// ServiceGetTransactionProofsQuery assembles the response to a transaction proof
+// query. It is exposed to allow external packages to test protocol behavior.
+func ServiceGetTransactionProofsQuery(chain *core.BlockChain, query GetTransactionProofsRequest) []types.InclusionProof {
+	return serviceInclusionProofs(chain, query, false)
+}
+
+// serviceInclusionProofs proves the inclusion of the transactions with the given
+// hashes, or of their receipts, in their blocks. Unknown transactions are left
+// with an empty proof to keep the response aligned with the query.
+func serviceInclusionProofs(chain *core.BlockChain, hashes []common.Hash, receipts bool) []types.InclusionProof {
+	// Gather proofs until the fetch or network limits is reached
+	var (
+		bytes  int
+		proofs []types.InclusionProof
+	)
+	for _, hash := range hashes {
+		if bytes >= softResponseLimit || len(proofs) >= maxInclusionProofsServe {
+			break
+		}
+		var proof types.InclusionProof
+		if lookup, _, _ := chain.GetTransactionLookup(hash); lookup != nil {
+			if p := proveInclusion(chain, lookup.BlockHash, lookup.BlockIndex, lookup.Index, receipts); p != nil {
+				proof = *p
+			}
+		}
+		proofs = append(proofs, proof)
+
+		bytes += len(proof.Value)
+		for _, node := range proof.Proof {
+			bytes += len(node)
+		}
+	}
+	return proofs
+}
+
+// proveInclusion proves the inclusion of the transaction at the given index of a
+// block, or of its receipt, in the block.
+func proveInclusion(chain *core.BlockChain, hash common.Hash, number uint64, index uint64, receipt bool) *types.InclusionProof {
+	var list types.DerivableList
+	if receipt {
+		receipts := chain.GetReceiptsByHash(hash)
+		if receipts == nil {
+			return nil
+		}
+		list = receipts
+	} else {
+		block := chain.GetBlock(hash, number)
+		if block == nil {
+			return nil
+		}
+		list = block.Transactions()
+	}
+	proof, err := trie.ProveInclusion(list, int(index))
+	if err != nil {
+		log.Debug("Failed to prove inclusion", "hash", hash, "number", number, "index", index, "receipt", receipt, "err", err)
+		return nil
+	}
+	proof.BlockHash, proof.BlockNumber = hash, number
+	return proof
+}
+
 func handleNewBlockhashes(backend Backend, msg Decoder, peer *Peer) error {
 	// A batch of new block announcements just arrived
 	ann := new(NewBlockHashesPacket)
@@ -380,6 +534,45 @@ func handleReceipts(backend Backend, msg Decoder, peer *Peer) error {
 	}, metadata)
 }
 
+func handleProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// A batch of state proofs arrived to one of our previous requests
+	res := new(ProofsPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	return peer.dispatchResponse(&Response{
+		id:   res.RequestId,
+		code: ProofsMsg,
+		Res:  &res.ProofsResponse,
+	}, nil)
+}
+
+func handleReceiptProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// A batch of receipt proofs arrived to one of our previous requests
+	res := new(ReceiptProofsPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	return peer.dispatchResponse(&Response{
+		id:   res.RequestId,
+		code: ReceiptProofsMsg,
+		Res:  &res.ReceiptProofsResponse,
+	}, nil)
+}
+
+func handleTransactionProofs(backend Backend, msg Decoder, peer *Peer) error {
+	// A batch of transaction proofs arrived to one of our previous requests
+	res := new(TransactionProofsPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	return peer.dispatchResponse(&Response{
+		id:   res.RequestId,
+		code: TransactionProofsMsg,
+		Res:  &res.TransactionProofsResponse,
+	}, nil)
+}
+
 func handleNewPooledTransactionHashes(backend Backend, msg Decoder, peer *Peer) error {
 	// New transaction announcement arrived, make sure we have
 	// a valid and fresh chain to handle them
@@ -32,8 +32,9 @@ const (
 )
 
 // Handshake executes the eth protocol handshake, negotiating version number,
-// network IDs, difficulties, head and genesis blocks.
-func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter) error {
+// network IDs, difficulties, head and genesis blocks. Header-only nodes tell so
+// from ixios/2 on, older versions don't know of them.
+func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter, headerOnly bool) error {
 	// Send out own handshake in a new thread
 	errc := make(chan error, 2)
 
@@ -47,6 +48,7 @@ func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis
 			Head:            head,
 			Genesis:         genesis,
 			ForkID:          forkID,
+			HeaderOnly:      headerOnly && p.version >= PROTOCOL_VERSION_2,
 		})
 	}()
 	go func() {
@@ -67,6 +69,7 @@ func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis
 		}
 	}
 	p.td, p.head = status.TD, status.Head
+	p.headerOnly = status.HeaderOnly
 
 	return nil
 }
@@ -71,8 +71,9 @@ type Peer struct {
 	rw        p2p.MsgReadWriter // Input/output streams for snap
 	version   uint              // Protocol version negotiated
 
-	head common.Hash // Latest advertised head block hash
-	td   *big.Int    // Latest advertised head block total difficulty
+	head       common.Hash // Latest advertised head block hash
+	td         *big.Int    // Latest advertised head block total difficulty
+	headerOnly bool        // Whether the peer only holds headers, without bodies, receipts or state
 
 	knownBlocks     *knownCache            // Set of block hashes known to be known by this peer
 	queuedBlocks    chan *blockPropagation // Queue of blocks to broadcast to the peer
@@ -146,6 +147,12 @@ func (p *Peer) Head() (hash common.Hash, td *big.Int) {
 	return hash, new(big.Int).Set(p.td)
 }
 
+// HeaderOnly returns whether the peer only holds headers, so it cannot serve any
+// block bodies, receipts or state.
+func (p *Peer) HeaderOnly() bool {
+	return p.headerOnly
+}
+
 // SetHead updates the head hash and total difficulty of the peer.
 func (p *Peer) SetHead(hash common.Hash, td *big.Int) {
 	p.lock.Lock()
@@ -321,6 +328,30 @@ func (p *Peer) ReplyReceiptsRLP(id uint64, receipts []rlp.RawValue) error {
 	})
 }
 
+// ReplyProofs is the response to GetProofs.
+func (p *Peer) ReplyProofs(id uint64, proofs [][]byte) error {
+	return p2p.Send(p.rw, ProofsMsg, &ProofsPacket{
+		RequestId:      id,
+		ProofsResponse: proofs,
+	})
+}
+
+// ReplyReceiptProofs is the response to GetReceiptProofs.
+func (p *Peer) ReplyReceiptProofs(id uint64, proofs []types.InclusionProof) error {
+	return p2p.Send(p.rw, ReceiptProofsMsg, &ReceiptProofsPacket{
+		RequestId:             id,
+		ReceiptProofsResponse: proofs,
+	})
+}
+
+// ReplyTransactionProofs is the response to GetTransactionProofs.
+func (p *Peer) ReplyTransactionProofs(id uint64, proofs []types.InclusionProof) error {
+	return p2p.Send(p.rw, TransactionProofsMsg, &TransactionProofsPacket{
+		RequestId:                 id,
+		TransactionProofsResponse: proofs,
+	})
+}
+
 // RequestOneHeader is a wrapper around the header query functions to fetch a
 // single header. It is used solely by the fetcher.
 func (p *Peer) RequestOneHeader(hash common.Hash, sink chan *Response) (*Request, error) {
@@ -445,6 +476,85 @@ func (p *Peer) RequestReceipts(hashes []common.Hash, sink chan *Response) (*Requ
 	return req, nil
 }
 
+// RequestProofs fetches the Merkle proofs of a batch of accounts and storage
+// slots in the state with the given root from a remote node.
+func (p *Peer) RequestProofs(root common.Hash, accounts []common.Address, slots [][]common.Hash, sink chan *Response) (*Request, error) {
+	if p.version < PROTOCOL_VERSION_2 {
+		return nil, errProofsUnsupported
+	}
+	p.Log().Debug("Fetching batch of proofs", "root", root, "accounts", len(accounts))
+	id := rand.Uint64()
+
+	req := &Request{
+		id:   id,
+		sink: sink,
+		code: GetProofsMsg,
+		want: ProofsMsg,
+		data: &GetProofsPacket{
+			RequestId: id,
+			GetProofsRequest: &GetProofsRequest{
+				Root:     root,
+				Accounts: accounts,
+				Slots:    slots,
+			},
+		},
+	}
+	if err := p.dispatchRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// RequestReceiptProofs fetches the receipts of a batch of transactions along
+// with the proofs of their inclusion in their blocks from a remote node.
+func (p *Peer) RequestReceiptProofs(hashes []common.Hash, sink chan *Response) (*Request, error) {
+	if p.version < PROTOCOL_VERSION_3 {
+		return nil, errProofsUnsupported
+	}
+	p.Log().Debug("Fetching batch of receipt proofs", "count", len(hashes))
+	id := rand.Uint64()
+
+	req := &Request{
+		id:   id,
+		sink: sink,
+		code: GetReceiptProofsMsg,
+		want: ReceiptProofsMsg,
+		data: &GetReceiptProofsPacket{
+			RequestId:               id,
+			GetReceiptProofsRequest: hashes,
+		},
+	}
+	if err := p.dispatchRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// RequestTransactionProofs fetches a batch of transactions along with the proofs
+// of their inclusion in their blocks from a remote node.
+func (p *Peer) RequestTransactionProofs(hashes []common.Hash, sink chan *Response) (*Request, error) {
+	if p.version < PROTOCOL_VERSION_3 {
+		return nil, errProofsUnsupported
+	}
+	p.Log().Debug("Fetching batch of transaction proofs", "count", len(hashes))
+	id := rand.Uint64()
+
+	req := &Request{
+		id:   id,
+		sink: sink,
+		code: GetTransactionProofsMsg,
+		want: TransactionProofsMsg,
+		data: &GetTransactionProofsPacket{
+			RequestId:                   id,
+			GetTransactionProofsRequest: hashes,
+		},
+	}
+	if err := p.dispatchRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
 // RequestTxs fetches a batch of transactions from a remote node.
 func (p *Peer) RequestTxs(hashes []common.Hash) error {
 	p.Log().Debug("Fetching batch of transactions", "count", len(hashes))
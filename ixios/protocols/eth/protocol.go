@@ -27,7 +27,11 @@ import (
 
 // Constants to match up protocol versions and messages
 const (
-	PROTOCOL_VERSION = 1 // 1
+	PROTOCOL_VERSION_1 = 1 // 1
+	PROTOCOL_VERSION_2 = 2 // 2, adds state proof retrieval for header-only nodes
+	PROTOCOL_VERSION_3 = 3 // 3, adds transaction and receipt inclusion proofs
+
+	PROTOCOL_VERSION = PROTOCOL_VERSION_3
 )
 
 // ProtocolName is the official short name of the protocol used during
@@ -36,11 +40,11 @@ const ProtocolName = "ixios"
 
 // ProtocolVersions are the supported versions of the protocol (first
 // is primary).
-var ProtocolVersions = []uint{PROTOCOL_VERSION}
+var ProtocolVersions = []uint{PROTOCOL_VERSION_3, PROTOCOL_VERSION_2, PROTOCOL_VERSION_1}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{PROTOCOL_VERSION: 17}
+var protocolLengths = map[uint]uint64{PROTOCOL_VERSION_3: 23, PROTOCOL_VERSION_2: 19, PROTOCOL_VERSION_1: 17}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 100 * 1024 * 1024
@@ -59,6 +63,12 @@ const (
 	PooledTransactionsMsg         = 0x0a
 	GetReceiptsMsg                = 0x0f
 	ReceiptsMsg                   = 0x10
+	GetProofsMsg                  = 0x11
+	ProofsMsg                     = 0x12
+	GetReceiptProofsMsg           = 0x13
+	ReceiptProofsMsg              = 0x14
+	GetTransactionProofsMsg       = 0x15
+	TransactionProofsMsg          = 0x16
 )
 
 var (
@@ -70,6 +80,15 @@ var (
 	errNetworkIDMismatch       = errors.New("network ID mismatch")
 	errGenesisMismatch         = errors.New("genesis mismatch")
 	errForkIDRejected          = errors.New("fork ID rejected")
+	errProofsUnsupported       = errors.New("proofs not supported by peer protocol version")
+)
+
+// Misbehaviour peers get dropped for by the syncing and fetching subsystems,
+// reported along with the drop so it weighs on the reputation of the peer.
+var (
+	ErrBadChain       = errors.New("invalid chain data delivered")
+	ErrStaleHead      = errors.New("advertised head not delivered")
+	ErrRequestTimeout = errors.New("request timed out")
 )
 
 // Packet represents a p2p message in the `eth` protocol.
@@ -86,6 +105,7 @@ type StatusPacket struct {
 	Head            common.Hash
 	Genesis         common.Hash
 	ForkID          forkid.ID
+	HeaderOnly      bool `rlp:"optional"` // Whether the node only holds headers, without bodies, receipts or state (ixios/2 and later)
 }
 
 // NewBlockHashesPacket is the network packet for the block announcements.
@@ -274,6 +294,77 @@ type ReceiptsRLPPacket struct {
 	ReceiptsRLPResponse
 }
 
+// GetProofsRequest represents a query for the Merkle proofs of accounts and of
+// storage slots of theirs in the state with the given root. Slots[i] holds the
+// slots to prove of Accounts[i] and may be shorter than Accounts.
+type GetProofsRequest struct {
+	Root     common.Hash
+	Accounts []common.Address
+	Slots    [][]common.Hash
+}
+
+// GetProofsPacket represents a proof query with request ID wrapping.
+type GetProofsPacket struct {
+	RequestId uint64
+	*GetProofsRequest
+}
+
+// ProofsResponse is the network packet for proof distribution, the deduplicated
+// set of trie nodes making up all the requested proofs.
+type ProofsResponse [][]byte
+
+// ProofsPacket is the network packet for proof distribution with request ID
+// wrapping.
+type ProofsPacket struct {
+	RequestId uint64
+	ProofsResponse
+}
+
+// GetReceiptProofsRequest represents a query for the receipts of transactions
+// along with the proofs of their inclusion in their blocks.
+type GetReceiptProofsRequest []common.Hash
+
+// GetReceiptProofsPacket represents a receipt proof query with request ID
+// wrapping.
+type GetReceiptProofsPacket struct {
+	RequestId uint64
+	GetReceiptProofsRequest
+}
+
+// ReceiptProofsResponse is the network packet for receipt proof distribution,
+// one per queried transaction. Unknown transactions have an empty block hash.
+type ReceiptProofsResponse []types.InclusionProof
+
+// ReceiptProofsPacket is the network packet for receipt proof distribution with
+// request ID wrapping.
+type ReceiptProofsPacket struct {
+	RequestId uint64
+	ReceiptProofsResponse
+}
+
+// GetTransactionProofsRequest represents a query for transactions along with
+// the proofs of their inclusion in their blocks.
+type GetTransactionProofsRequest []common.Hash
+
+// GetTransactionProofsPacket represents a transaction proof query with request
+// ID wrapping.
+type GetTransactionProofsPacket struct {
+	RequestId uint64
+	GetTransactionProofsRequest
+}
+
+// TransactionProofsResponse is the network packet for transaction proof
+// distribution, one per queried transaction. Unknown transactions have an empty
+// block hash.
+type TransactionProofsResponse []types.InclusionProof
+
+// TransactionProofsPacket is the network packet for transaction proof
+// distribution with request ID wrapping.
+type TransactionProofsPacket struct {
+	RequestId uint64
+	TransactionProofsResponse
+}
+
 // NewPooledTransactionHashesPacket represents a transaction announcement packet on eth/68 and newer.
 type NewPooledTransactionHashesPacket struct {
 	Types  []byte
@@ -348,3 +439,21 @@ func (*GetReceiptsRequest) Kind() byte   { return GetReceiptsMsg }
 
 func (*ReceiptsResponse) Name() string { return "Receipts" }
 func (*ReceiptsResponse) Kind() byte   { return ReceiptsMsg }
+
+func (*GetProofsRequest) Name() string { return "GetProofs" }
+func (*GetProofsRequest) Kind() byte   { return GetProofsMsg }
+
+func (*ProofsResponse) Name() string { return "Proofs" }
+func (*ProofsResponse) Kind() byte   { return ProofsMsg }
+
+func (*GetReceiptProofsRequest) Name() string { return "GetReceiptProofs" }
+func (*GetReceiptProofsRequest) Kind() byte   { return GetReceiptProofsMsg }
+
+func (*ReceiptProofsResponse) Name() string { return "ReceiptProofs" }
+func (*ReceiptProofsResponse) Kind() byte   { return ReceiptProofsMsg }
+
+func (*GetTransactionProofsRequest) Name() string { return "GetTransactionProofs" }
+func (*GetTransactionProofsRequest) Kind() byte   { return GetTransactionProofsMsg }
+
+func (*TransactionProofsResponse) Name() string { return "TransactionProofs" }
+func (*TransactionProofsResponse) Kind() byte   { return TransactionProofsMsg }
@@ -0,0 +1,1056 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/rawdb"
+	"github.com/ixios-io/ixiosSpark/core/state"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/kvdb"
+	"github.com/ixios-io/ixiosSpark/log"
+	"github.com/ixios-io/ixiosSpark/rlp"
+	"github.com/ixios-io/ixiosSpark/trie"
+	"github.com/ixios-io/ixiosSpark/trie/trienode"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// requestSize is the target number of bytes to request from a remote peer
+	// in a single state retrieval.
+	requestSize = 512 * 1024
+
+	// maxStorageSetFetch is the maximum number of accounts whose storage is
+	// requested in a single query.
+	maxStorageSetFetch = 128
+
+	// maxCodeRequestCount is the maximum number of bytecode blobs to request in a
+	// single query.
+	maxCodeRequestCount = 64
+
+	// maxTrieRequestCount is the maximum number of trie node blobs to request in
+	// a single query.
+	maxTrieRequestCount = 256
+
+	// maxParallelRequests is the maximum number of requests in flight at once,
+	// each assigned to a different peer.
+	maxParallelRequests = 16
+
+	// progressInterval is the time between two progress reports of a running
+	// state sync.
+	progressInterval = 8 * time.Second
+)
+
+var (
+	// requestTimeout is the maximum time a peer is allowed to spend on serving a
+	// single state request before it's considered unable to serve the root.
+	requestTimeout = 10 * time.Second
+
+	// statelessBackoff is the time a peer that failed to serve the current root is
+	// left alone before being retried. Small networks may have no one else to ask.
+	statelessBackoff = 30 * time.Second
+)
+
+var (
+	// ErrCancelled is returned from state syncing if the operation was prematurely
+	// terminated.
+	ErrCancelled = errors.New("sync cancelled")
+
+	// errStalled is returned if the trie healing runs out of missing nodes while
+	// still having pending ones, which should never happen.
+	errStalled = errors.New("state healing stalled")
+)
+
+// SyncPeer abstracts out the methods required for a peer to be synced against
+// with the goal of allowing the construction of mock peers without the full
+// blown networking.
+type SyncPeer interface {
+	// ID retrieves the peer's unique identifier.
+	ID() string
+
+	// RequestAccountRange fetches a batch of accounts rooted in a specific account
+	// trie, starting with the origin.
+	RequestAccountRange(id uint64, root, origin, limit common.Hash, bytes uint64) error
+
+	// RequestStorageRanges fetches a batch of storage slots belonging to one or
+	// more accounts. If slots from only one account is requested, an origin marker
+	// may also be used to retrieve from there.
+	RequestStorageRanges(id uint64, root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error
+
+	// RequestByteCodes fetches a batch of bytecodes by hash.
+	RequestByteCodes(id uint64, hashes []common.Hash, bytes uint64) error
+
+	// RequestTrieNodes fetches a batch of account or storage trie nodes rooted in
+	// a specific state trie.
+	RequestTrieNodes(id uint64, root common.Hash, paths []TrieNodePathSet, bytes uint64) error
+
+	// Log retrieves the peer's own contextual logger.
+	Log() log.Logger
+}
+
+// SyncProgress is a snapshot of the amount of state data retrieved by the
+// syncer, both during the range retrieval and the healing phases.
+type SyncProgress struct {
+	Accounts      uint64             // Number of accounts downloaded
+	AccountBytes  common.StorageSize // Number of account trie bytes persisted to disk
+	Bytecodes     uint64             // Number of bytecodes downloaded
+	BytecodeBytes common.StorageSize // Number of bytecode bytes downloaded
+	Storage       uint64             // Number of storage slots downloaded
+	StorageBytes  common.StorageSize // Number of storage trie bytes persisted to disk
+
+	TrienodeHealSynced uint64             // Number of state trie nodes downloaded
+	TrienodeHealBytes  common.StorageSize // Number of state trie bytes persisted to disk
+	BytecodeHealSynced uint64             // Number of bytecodes downloaded
+	BytecodeHealBytes  common.StorageSize // Number of bytecodes persisted to disk
+}
+
+// request is a state retrieval in flight, waiting for the response of a peer.
+type request struct {
+	peer string      // Peer to which this request is assigned
+	kind byte        // Message code of the expected response
+	res  chan Packet // Channel to deliver the response on (closed on peer drop)
+}
+
+// syncStatus is the progress of a state sync persisted across restarts, so that
+// a restarted node resumes the account ranges where it left off. The account
+// trie nodes not yet flushed to disk are lost with a restart and healed later.
+type syncStatus struct {
+	Root      common.Hash // State root the ranges were last retrieved from
+	Next      common.Hash // Next account hash to retrieve in the range phase
+	Ranged    bool        // Whether the account range phase has completed
+	Accounts  uint64      // Number of accounts downloaded
+	Bytecodes uint64      // Number of bytecodes downloaded
+	Storage   uint64      // Number of storage slots downloaded
+}
+
+// Syncer is the Ixios state downloader. It retrieves the accounts of a state in
+// contiguous ranges proven against the state root, together with the storage
+// slots and bytecodes they reference, and rebuilds the tries locally from them.
+// Anything the ranges did not cover, e.g. because the sync root moved while the
+// ranges were being retrieved, is then healed via trie.Sync by downloading the
+// individual missing trie nodes.
+//
+// The account ranges are retrieved in order, one at a time, while the storage,
+// bytecode and trie node batches are spread over the idle peers concurrently.
+// Each request goes to a random peer which has not recently failed to serve the
+// current root. The range progress is persisted, so a restart resumes it.
+type Syncer struct {
+	db     kvdb.KeyValueStore // Database to store the trie nodes into (and dedup)
+	scheme string             // Node scheme used in node database
+
+	root    common.Hash     // Current state trie root being synced
+	next    common.Hash     // Next account hash to retrieve in the range phase
+	ranged  bool            // Whether the account range phase has completed
+	accTrie *trie.StackTrie // Account trie assembled from the retrieved ranges
+	batch   kvdb.Batch      // Database batch collecting the account trie nodes
+
+	peers     map[string]SyncPeer  // Currently active peers to download from
+	stateless map[string]time.Time // Peers that failed to serve the current root, by the time to retry them
+	busy      map[string]struct{}  // Peers with a request in flight
+	update    chan struct{}        // Closed (and replaced) on possible sync progression
+	pend      map[uint64]*request  // Requests currently in flight, by id
+
+	progress SyncProgress // Amount of state data retrieved so far
+	logTime  time.Time    // Time instance when the progress was last reported
+
+	schedLock sync.Mutex   // Serializes the heal requests feeding the trie scheduler
+	lock      sync.RWMutex // Protects fields that can change outside of sync (peers, reqs, progress)
+}
+
+// NewSyncer creates a new state syncer to download the Ixios state over the
+// `ixsnap` protocol, resuming the progress of a previous run stored in db.
+func NewSyncer(db kvdb.KeyValueStore, scheme string) *Syncer {
+	s := &Syncer{
+		db:        db,
+		scheme:    scheme,
+		peers:     make(map[string]SyncPeer),
+		stateless: make(map[string]time.Time),
+		busy:      make(map[string]struct{}),
+		update:    make(chan struct{}),
+		pend:      make(map[uint64]*request),
+	}
+	s.loadSyncStatus()
+	return s
+}
+
+// loadSyncStatus retrieves the progress of a previously interrupted sync.
+func (s *Syncer) loadSyncStatus() {
+	blob := rawdb.ReadSnapshotSyncStatus(s.db)
+	if len(blob) == 0 {
+		return
+	}
+	var status syncStatus
+	if err := rlp.DecodeBytes(blob, &status); err != nil {
+		log.Error("Failed to decode state sync status", "err", err)
+		return
+	}
+	s.root, s.next, s.ranged = status.Root, status.Next, status.Ranged
+	s.progress.Accounts = status.Accounts
+	s.progress.Bytecodes = status.Bytecodes
+	s.progress.Storage = status.Storage
+
+	log.Debug("Loaded state sync status", "root", s.root, "next", s.next, "ranged", s.ranged)
+}
+
+// saveSyncStatus stores the progress of the sync into w.
+func (s *Syncer) saveSyncStatus(w kvdb.KeyValueWriter) {
+	progress := s.Progress()
+	blob, err := rlp.EncodeToBytes(&syncStatus{
+		Root:      s.root,
+		Next:      s.next,
+		Ranged:    s.ranged,
+		Accounts:  progress.Accounts,
+		Bytecodes: progress.Bytecodes,
+		Storage:   progress.Storage,
+	})
+	if err != nil {
+		panic(err) // Cannot happen, the status is all plain fields
+	}
+	rawdb.WriteSnapshotSyncStatus(w, blob)
+}
+
+// Register injects a new data source into the syncer's peerset.
+func (s *Syncer) Register(peer SyncPeer) error {
+	id := peer.ID()
+
+	s.lock.Lock()
+	if _, ok := s.peers[id]; ok {
+		s.lock.Unlock()
+		log.Error("Snap peer already registered", "id", id)
+		return errors.New("already registered")
+	}
+	s.peers[id] = peer
+
+	// Notify any active syncs that a new peer can be assigned data
+	s.notify()
+	s.lock.Unlock()
+	return nil
+}
+
+// Unregister removes a data source from the syncer's peerset, failing any
+// request that is still waiting for it.
+func (s *Syncer) Unregister(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.peers[id]; !ok {
+		log.Error("Snap peer not registered", "id", id)
+		return errors.New("not registered")
+	}
+	delete(s.peers, id)
+	delete(s.stateless, id)
+
+	for reqid, req := range s.pend {
+		if req.peer == id {
+			delete(s.pend, reqid)
+			close(req.res)
+		}
+	}
+	return nil
+}
+
+// Deliver injects a response packet from a remote peer into the syncer,
+// matching it up with the request it answers.
+func (s *Syncer) Deliver(peer SyncPeer, packet Packet) error {
+	var id uint64
+	switch packet := packet.(type) {
+	case *AccountRangePacket:
+		id = packet.ID
+	case *StorageRangesPacket:
+		id = packet.ID
+	case *ByteCodesPacket:
+		id = packet.ID
+	case *TrieNodesPacket:
+		id = packet.ID
+	default:
+		return fmt.Errorf("unexpected snap packet type: %T", packet)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	req, ok := s.pend[id]
+	if !ok || req.peer != peer.ID() || req.kind != packet.Kind() {
+		// Responses to timed out or cancelled requests end up here, don't punish
+		peer.Log().Debug("Unexpected snap response", "kind", packet.Name(), "reqid", id)
+		return nil
+	}
+	delete(s.pend, id)
+	req.res <- packet
+	return nil
+}
+
+// Progress returns the amount of state data retrieved so far.
+func (s *Syncer) Progress() SyncProgress {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.progress
+}
+
+// Sync starts (or resumes a previous) sync cycle to iterate over a state trie
+// with the given root and reconstruct the nodes based on the snapshot leaves.
+// Previously downloaded segments will not be redownloaded or fixed, rather any
+// errors will be healed after the leaves are fully accumulated.
+func (s *Syncer) Sync(root common.Hash, cancel chan struct{}) error {
+	s.lock.Lock()
+	s.stateless = make(map[string]time.Time)
+	s.lock.Unlock()
+
+	if root == types.EmptyRootHash {
+		log.Debug("Empty state root, nothing to sync")
+		return nil
+	}
+	if s.root != root {
+		log.Debug("Starting state sync cycle", "root", root, "next", s.next, "ranged", s.ranged)
+		s.root = root
+	}
+	if !s.ranged {
+		if err := s.syncRanges(cancel); err != nil {
+			// Keep the retrieved ranges for the next run, even after a restart
+			if ferr := s.flushRanges(); ferr != nil {
+				log.Error("Failed to store state sync progress", "err", ferr)
+			}
+			return err
+		}
+	}
+	if err := s.heal(cancel); err != nil {
+		return err
+	}
+	progress := s.Progress()
+	log.Info("State sync completed", "root", root, "accounts", progress.Accounts, "slots", progress.Storage,
+		"codes", progress.Bytecodes, "healed", progress.TrienodeHealSynced)
+
+	// Reset the range progress, a later sync will need to start from scratch
+	s.next, s.ranged, s.accTrie, s.batch = common.Hash{}, false, nil, nil
+	rawdb.DeleteSnapshotSyncStatus(s.db)
+	return nil
+}
+
+// syncRanges retrieves the account ranges of the state from where the last run
+// left off, together with the storage tries and bytecodes of the accounts.
+func (s *Syncer) syncRanges(cancel <-chan struct{}) error {
+	if s.accTrie == nil {
+		// The nodes left of the first account of a resumed sync are not known to
+		// the trie and would come out wrong, leave them to the healing
+		s.batch = s.db.NewBatch()
+		s.accTrie = trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+			rawdb.WriteTrieNode(s.batch, common.Hash{}, path, hash, blob, s.scheme)
+
+			s.lock.Lock()
+			s.progress.AccountBytes += common.StorageSize(len(blob))
+			s.lock.Unlock()
+		}).WithSkipBoundary(s.next != (common.Hash{}), false))
+	}
+	for {
+		hashes, accounts, cont, err := s.fetchAccounts(cancel)
+		if err != nil {
+			return err
+		}
+		// Gather the storage tries and bytecodes of the accounts that are missing
+		var (
+			codes    []common.Hash
+			seen     = make(map[common.Hash]struct{})
+			owners   []common.Hash
+			storages []common.Hash
+		)
+		for i, blob := range accounts {
+			var account types.StateAccount
+			if err := rlp.DecodeBytes(blob, &account); err != nil {
+				return err // Proven by the range, can't happen
+			}
+			if code := common.BytesToHash(account.CodeHash); code != types.EmptyCodeHash {
+				if _, ok := seen[code]; !ok && !rawdb.HasCode(s.db, code) {
+					seen[code] = struct{}{}
+					codes = append(codes, code)
+				}
+			}
+			if account.Root != types.EmptyRootHash && !rawdb.HasTrieNode(s.db, hashes[i], nil, account.Root, s.scheme) {
+				owners = append(owners, hashes[i])
+				storages = append(storages, account.Root)
+			}
+		}
+		if err := s.fetchCodes(codes, cancel); err != nil {
+			return err
+		}
+		if err := s.fetchStorage(owners, storages, cancel); err != nil {
+			return err
+		}
+		// Everything referenced by the accounts is stored, extend the account trie
+		for i, hash := range hashes {
+			if err := s.accTrie.Update(hash[:], accounts[i]); err != nil {
+				return err
+			}
+		}
+		s.lock.Lock()
+		s.progress.Accounts += uint64(len(hashes))
+		s.lock.Unlock()
+
+		if !cont {
+			s.accTrie.Commit()
+			s.ranged = true
+			return s.flushRanges()
+		}
+		s.next = incHash(hashes[len(hashes)-1])
+		if s.batch.ValueSize() > kvdb.IdealBatchSize {
+			if err := s.flushRanges(); err != nil {
+				return err
+			}
+		}
+		s.reportProgress(false)
+	}
+}
+
+// flushRanges writes the account trie nodes assembled so far to disk, together
+// with the range progress they belong to.
+func (s *Syncer) flushRanges() error {
+	if s.batch == nil {
+		return nil
+	}
+	s.saveSyncStatus(s.batch)
+	if err := s.batch.Write(); err != nil {
+		return err
+	}
+	s.batch.Reset()
+	return nil
+}
+
+// fetchAccounts retrieves the next range of accounts starting at the current
+// range marker, verified against the state root. The accounts are returned in
+// their full (consensus) encoding, along with a flag whether there are more
+// accounts to retrieve.
+func (s *Syncer) fetchAccounts(cancel <-chan struct{}) ([]common.Hash, [][]byte, bool, error) {
+	for {
+		peer, res, err := s.request(AccountRangeMsg, cancel, func(peer SyncPeer, id uint64) error {
+			return peer.RequestAccountRange(id, s.root, s.next, common.MaxHash, requestSize)
+		})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		packet := res.(*AccountRangePacket)
+
+		hashes, accounts := packet.Unpack()
+		if len(hashes) == 0 && len(packet.Proof) == 0 {
+			// Peer does not have the requested state, try someone else
+			s.markStateless(peer)
+			continue
+		}
+		cont, err := verifyAccounts(s.root, s.next, hashes, accounts, packet.Proof)
+		if err != nil {
+			peer.Log().Warn("Account range failed proof", "root", s.root, "origin", s.next, "err", err)
+			s.markStateless(peer)
+			continue
+		}
+		return hashes, accounts, cont, nil
+	}
+}
+
+// verifyAccounts converts the slim accounts of a range response to their full
+// encoding in place and verifies the range against the state root.
+func verifyAccounts(root common.Hash, origin common.Hash, hashes []common.Hash, accounts [][]byte, proof [][]byte) (bool, error) {
+	keys := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		keys[i] = common.CopyBytes(hash[:])
+
+		full, err := types.FullAccountRLP(accounts[i])
+		if err != nil {
+			return false, err
+		}
+		accounts[i] = full
+	}
+	return trie.VerifyRangeProof(root, origin[:], keys, accounts, proofSet(proof))
+}
+
+// fetchStorage retrieves the complete storage tries of the given accounts, in
+// batches retrieved concurrently.
+func (s *Syncer) fetchStorage(accounts []common.Hash, roots []common.Hash, cancel <-chan struct{}) error {
+	var tasks []func(cancel <-chan struct{}) error
+	for len(accounts) > 0 {
+		count := min(len(accounts), maxStorageSetFetch)
+		batch, batchRoots := accounts[:count], roots[:count]
+		tasks = append(tasks, func(cancel <-chan struct{}) error {
+			return s.fetchStorageBatch(batch, batchRoots, cancel)
+		})
+		accounts, roots = accounts[count:], roots[count:]
+	}
+	return runParallel(cancel, tasks)
+}
+
+// fetchStorageBatch retrieves the complete storage tries of the given accounts,
+// retrying the ones a peer did not deliver with another.
+func (s *Syncer) fetchStorageBatch(accounts []common.Hash, roots []common.Hash, cancel <-chan struct{}) error {
+	for len(accounts) > 0 {
+		count := len(accounts)
+		if count > maxStorageSetFetch {
+			count = maxStorageSetFetch
+		}
+		peer, res, err := s.request(StorageRangesMsg, cancel, func(peer SyncPeer, id uint64) error {
+			return peer.RequestStorageRanges(id, s.root, accounts[:count], nil, nil, requestSize)
+		})
+		if err != nil {
+			return err
+		}
+		packet := res.(*StorageRangesPacket)
+
+		hashset, slotset := packet.Unpack()
+		if len(hashset) == 0 || len(hashset) > count {
+			// Peer does not have the requested state, try someone else
+			s.markStateless(peer)
+			continue
+		}
+		// All sets but a proven last one are complete storage tries
+		var done int
+		for i := range hashset {
+			if i == len(hashset)-1 && len(packet.Proof) > 0 {
+				err = s.fetchLargeStorage(accounts[i], roots[i], hashset[i], slotset[i], packet.Proof, cancel)
+			} else {
+				err = s.commitStorage(accounts[i], roots[i], hashset[i], slotset[i])
+			}
+			if err != nil {
+				break
+			}
+			done++
+		}
+		accounts, roots = accounts[done:], roots[done:]
+		if err != nil {
+			if errors.Is(err, ErrCancelled) {
+				return err
+			}
+			peer.Log().Warn("Storage range failed", "root", s.root, "err", err)
+			s.markStateless(peer)
+		}
+	}
+	return nil
+}
+
+// commitStorage assembles a storage trie delivered in its entirety and stores
+// its nodes if it matches the expected root.
+func (s *Syncer) commitStorage(account common.Hash, root common.Hash, hashes []common.Hash, slots [][]byte) error {
+	var (
+		batch = s.db.NewBatch()
+		size  common.StorageSize
+	)
+	tr := trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(batch, account, path, hash, blob, s.scheme)
+		size += common.StorageSize(len(blob))
+	}))
+	for i, hash := range hashes {
+		if err := tr.Update(hash[:], slots[i]); err != nil {
+			return err
+		}
+	}
+	if have := tr.Commit(); have != root {
+		return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", account, have, root)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.progress.Storage += uint64(len(hashes))
+	s.progress.StorageBytes += size
+	s.lock.Unlock()
+	return nil
+}
+
+// fetchLargeStorage retrieves a storage trie too large to be delivered in one
+// response, chunk by chunk, starting with the given first chunk.
+func (s *Syncer) fetchLargeStorage(account common.Hash, root common.Hash, hashes []common.Hash, slots [][]byte, proof [][]byte, cancel <-chan struct{}) error {
+	cont, err := verifyStorage(root, common.Hash{}, hashes, slots, proof)
+	if err != nil {
+		return err
+	}
+	var (
+		batch = s.db.NewBatch()
+		size  common.StorageSize
+		count uint64
+	)
+	tr := trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(batch, account, path, hash, blob, s.scheme)
+		size += common.StorageSize(len(blob))
+	}))
+	for {
+		for i, hash := range hashes {
+			if err := tr.Update(hash[:], slots[i]); err != nil {
+				return err
+			}
+		}
+		count += uint64(len(hashes))
+
+		if batch.ValueSize() > kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if !cont {
+			break
+		}
+		// Retrieve the next chunk of slots, proven against the storage root
+		origin := incHash(hashes[len(hashes)-1])
+		for {
+			peer, res, err := s.request(StorageRangesMsg, cancel, func(peer SyncPeer, id uint64) error {
+				return peer.RequestStorageRanges(id, s.root, []common.Hash{account}, origin[:], common.MaxHash[:], requestSize)
+			})
+			if err != nil {
+				return err
+			}
+			packet := res.(*StorageRangesPacket)
+
+			hashset, slotset := packet.Unpack()
+			if len(hashset) != 1 || len(packet.Proof) == 0 {
+				// Peer does not have the requested state, try someone else
+				s.markStateless(peer)
+				continue
+			}
+			hashes, slots = hashset[0], slotset[0]
+			if cont, err = verifyStorage(root, origin, hashes, slots, packet.Proof); err != nil {
+				peer.Log().Warn("Storage range failed proof", "root", s.root, "account", account, "origin", origin, "err", err)
+				s.markStateless(peer)
+				continue
+			}
+			break
+		}
+	}
+	if have := tr.Commit(); have != root {
+		return fmt.Errorf("storage root mismatch for account %x: have %x, want %x", account, have, root)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.progress.Storage += count
+	s.progress.StorageBytes += size
+	s.lock.Unlock()
+	return nil
+}
+
+// verifyStorage verifies a range of storage slots against the storage root.
+func verifyStorage(root common.Hash, origin common.Hash, hashes []common.Hash, slots [][]byte, proof [][]byte) (bool, error) {
+	keys := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		keys[i] = common.CopyBytes(hash[:])
+	}
+	return trie.VerifyRangeProof(root, origin[:], keys, slots, proofSet(proof))
+}
+
+// fetchCodes retrieves the bytecodes with the given hashes, in batches retrieved
+// concurrently.
+func (s *Syncer) fetchCodes(hashes []common.Hash, cancel <-chan struct{}) error {
+	var tasks []func(cancel <-chan struct{}) error
+	for len(hashes) > 0 {
+		count := min(len(hashes), maxCodeRequestCount)
+		batch := hashes[:count]
+		tasks = append(tasks, func(cancel <-chan struct{}) error {
+			return s.fetchCodeBatch(batch, cancel)
+		})
+		hashes = hashes[count:]
+	}
+	return runParallel(cancel, tasks)
+}
+
+// fetchCodeBatch retrieves the bytecodes with the given hashes, retrying the ones
+// a peer did not deliver with another.
+func (s *Syncer) fetchCodeBatch(hashes []common.Hash, cancel <-chan struct{}) error {
+	for len(hashes) > 0 {
+		count := len(hashes)
+		if count > maxCodeRequestCount {
+			count = maxCodeRequestCount
+		}
+		peer, res, err := s.request(ByteCodesMsg, cancel, func(peer SyncPeer, id uint64) error {
+			return peer.RequestByteCodes(id, hashes[:count], requestSize)
+		})
+		if err != nil {
+			return err
+		}
+		delivered := make(map[common.Hash][]byte)
+		for _, code := range res.(*ByteCodesPacket).Codes {
+			delivered[crypto.Keccak256Hash(code)] = code
+		}
+		var (
+			batch   = s.db.NewBatch()
+			missing []common.Hash
+			size    common.StorageSize
+		)
+		for _, hash := range hashes[:count] {
+			code, ok := delivered[hash]
+			if !ok {
+				missing = append(missing, hash)
+				continue
+			}
+			rawdb.WriteCode(batch, hash, code)
+			size += common.StorageSize(len(code))
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		if len(missing) == count {
+			// Peer does not have any of the requested codes, try someone else
+			s.markStateless(peer)
+		}
+		s.lock.Lock()
+		s.progress.Bytecodes += uint64(count - len(missing))
+		s.progress.BytecodeBytes += size
+		s.lock.Unlock()
+
+		hashes = append(missing, hashes[count:]...)
+	}
+	return nil
+}
+
+// heal downloads the trie nodes and bytecodes of the state that are still
+// missing after the range retrieval, until the state is complete. The missing
+// items known at a time are retrieved in batches concurrently.
+func (s *Syncer) heal(cancel <-chan struct{}) error {
+	sched := state.NewStateSync(s.root, s.db, nil, s.scheme)
+	for sched.Pending() > 0 {
+		paths, hashes, codes := sched.Missing(maxParallelRequests * maxTrieRequestCount)
+		if len(paths) == 0 && len(codes) == 0 {
+			return errStalled
+		}
+		var tasks []func(cancel <-chan struct{}) error
+		for len(paths) > 0 {
+			count := min(len(paths), maxTrieRequestCount)
+			batch, batchHashes := paths[:count], hashes[:count]
+			tasks = append(tasks, func(cancel <-chan struct{}) error {
+				return s.healNodes(sched, batch, batchHashes, cancel)
+			})
+			paths, hashes = paths[count:], hashes[count:]
+		}
+		for len(codes) > 0 {
+			count := min(len(codes), maxCodeRequestCount)
+			batch := codes[:count]
+			tasks = append(tasks, func(cancel <-chan struct{}) error {
+				return s.healCodes(sched, batch, cancel)
+			})
+			codes = codes[count:]
+		}
+		if err := runParallel(cancel, tasks); err != nil {
+			return err
+		}
+		if sched.MemSize() > kvdb.IdealBatchSize || sched.Pending() == 0 {
+			batch := s.db.NewBatch()
+			if err := sched.Commit(batch); err != nil {
+				return err
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+		}
+		s.reportProgress(false)
+	}
+	return nil
+}
+
+// healNodes retrieves a batch of missing trie nodes, feeding them into the heal
+// scheduler. The nodes a peer did not deliver are retried with another.
+func (s *Syncer) healNodes(sched *trie.Sync, paths []string, hashes []common.Hash, cancel <-chan struct{}) error {
+	for len(paths) > 0 {
+		pathsets := make([]TrieNodePathSet, len(paths))
+		for i, path := range paths {
+			pathsets[i] = TrieNodePathSet(trie.NewSyncPath([]byte(path)))
+		}
+		peer, res, err := s.request(TrieNodesMsg, cancel, func(peer SyncPeer, id uint64) error {
+			return peer.RequestTrieNodes(id, s.root, pathsets, requestSize)
+		})
+		if err != nil {
+			return err
+		}
+		var (
+			nodes    = res.(*TrieNodesPacket).Nodes
+			filled   = make([]bool, len(hashes))
+			fills    int
+			size     common.StorageSize
+			unwanted bool
+		)
+		// Match the nodes up with the requested hashes, skipping the ones not served
+		s.schedLock.Lock()
+		for i, j := 0, 0; i < len(nodes); i++ {
+			hash := crypto.Keccak256Hash(nodes[i])
+			for j < len(hashes) && hash != hashes[j] {
+				j++
+			}
+			if j == len(hashes) {
+				unwanted = true
+				break
+			}
+			if err := sched.ProcessNode(trie.NodeSyncResult{Path: paths[j], Data: nodes[i]}); err != nil {
+				s.schedLock.Unlock()
+				return err
+			}
+			filled[j] = true
+			fills++
+			size += common.StorageSize(len(nodes[i]))
+			j++
+		}
+		s.schedLock.Unlock()
+
+		if fills == 0 || unwanted {
+			// Peer does not have the requested state (or sent junk), try someone else
+			s.markStateless(peer)
+		}
+		s.lock.Lock()
+		s.progress.TrienodeHealSynced += uint64(fills)
+		s.progress.TrienodeHealBytes += size
+		s.lock.Unlock()
+
+		var (
+			missingPaths  []string
+			missingHashes []common.Hash
+		)
+		for i, ok := range filled {
+			if !ok {
+				missingPaths = append(missingPaths, paths[i])
+				missingHashes = append(missingHashes, hashes[i])
+			}
+		}
+		paths, hashes = missingPaths, missingHashes
+	}
+	return nil
+}
+
+// healCodes retrieves a batch of missing bytecodes, feeding them into the heal
+// scheduler. The codes a peer did not deliver are retried with another.
+func (s *Syncer) healCodes(sched *trie.Sync, hashes []common.Hash, cancel <-chan struct{}) error {
+	for len(hashes) > 0 {
+		peer, res, err := s.request(ByteCodesMsg, cancel, func(peer SyncPeer, id uint64) error {
+			return peer.RequestByteCodes(id, hashes, requestSize)
+		})
+		if err != nil {
+			return err
+		}
+		delivered := make(map[common.Hash][]byte)
+		for _, code := range res.(*ByteCodesPacket).Codes {
+			delivered[crypto.Keccak256Hash(code)] = code
+		}
+		var (
+			missing []common.Hash
+			size    common.StorageSize
+		)
+		s.schedLock.Lock()
+		for _, hash := range hashes {
+			code, ok := delivered[hash]
+			if !ok {
+				missing = append(missing, hash)
+				continue
+			}
+			if err := sched.ProcessCode(trie.CodeSyncResult{Hash: hash, Data: code}); err != nil {
+				s.schedLock.Unlock()
+				return err
+			}
+			size += common.StorageSize(len(code))
+		}
+		s.schedLock.Unlock()
+
+		if len(missing) == len(hashes) {
+			// Peer does not have any of the requested codes, try someone else
+			s.markStateless(peer)
+		}
+		s.lock.Lock()
+		s.progress.BytecodeHealSynced += uint64(len(hashes) - len(missing))
+		s.progress.BytecodeHealBytes += size
+		s.lock.Unlock()
+
+		hashes = missing
+	}
+	return nil
+}
+
+// runParallel runs the given retrieval tasks concurrently, at most
+// maxParallelRequests of them at a time, and returns the first error. The rest
+// of the tasks are cancelled once one of them fails.
+func runParallel(cancel <-chan struct{}, tasks []func(cancel <-chan struct{}) error) error {
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	go func() {
+		select {
+		case <-cancel:
+			stop()
+		case <-ctx.Done():
+		}
+	}()
+	group, gctx := errgroup.WithContext(ctx)
+	group.SetLimit(maxParallelRequests)
+	for _, task := range tasks {
+		group.Go(func() error {
+			return task(gctx.Done())
+		})
+	}
+	return group.Wait()
+}
+
+// request assigns a state retrieval to a random idle peer able to serve the
+// current root and waits for its response. Peers that time out are considered
+// unable to serve the root for a while and the request is retried with someone
+// else, waiting for peers to join, turn idle or be retried if needed.
+func (s *Syncer) request(kind byte, cancel <-chan struct{}, send func(peer SyncPeer, id uint64) error) (SyncPeer, Packet, error) {
+	for {
+		peer, id, req, update, retry := s.assign(kind)
+		if peer == nil {
+			select {
+			case <-update:
+			case <-retry:
+			case <-cancel:
+				return nil, nil, ErrCancelled
+			}
+			continue
+		}
+		if err := send(peer, id); err != nil {
+			peer.Log().Debug("Failed to send state request", "err", err)
+			s.release(peer, id, true)
+			continue
+		}
+		timeout := time.NewTimer(requestTimeout)
+		select {
+		case res, ok := <-req.res:
+			timeout.Stop()
+			s.release(peer, id, false)
+			if !ok {
+				continue // Peer dropped, request failed
+			}
+			return peer, res, nil
+
+		case <-timeout.C:
+			peer.Log().Debug("State request timed out", "reqid", id)
+			s.release(peer, id, true)
+
+		case <-cancel:
+			timeout.Stop()
+			s.release(peer, id, false)
+			return nil, nil, ErrCancelled
+		}
+	}
+}
+
+// assign picks a random idle peer that might have the current state and tracks
+// a request of the given kind to it. If there is none, the channels to wait on
+// for peers to join or turn idle and for stateless peers to be retried are
+// returned instead.
+func (s *Syncer) assign(kind byte) (SyncPeer, uint64, *request, chan struct{}, <-chan time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		now    = time.Now()
+		idlers []SyncPeer
+		retry  time.Duration
+	)
+	for id, peer := range s.peers {
+		if _, ok := s.busy[id]; ok {
+			continue
+		}
+		if until, ok := s.stateless[id]; ok {
+			if wait := until.Sub(now); wait > 0 {
+				if retry == 0 || wait < retry {
+					retry = wait
+				}
+				continue
+			}
+			delete(s.stateless, id)
+		}
+		idlers = append(idlers, peer)
+	}
+	if len(idlers) == 0 {
+		var expire <-chan time.Time
+		if retry > 0 {
+			expire = time.After(retry)
+		}
+		return nil, 0, nil, s.update, expire
+	}
+	peer := idlers[rand.Intn(len(idlers))]
+
+	id := rand.Uint64()
+	for _, ok := s.pend[id]; ok; _, ok = s.pend[id] {
+		id = rand.Uint64()
+	}
+	req := &request{
+		peer: peer.ID(),
+		kind: kind,
+		res:  make(chan Packet, 1),
+	}
+	s.pend[id] = req
+	s.busy[req.peer] = struct{}{}
+	return peer, id, req, nil, nil
+}
+
+// release stops tracking a request that was answered, failed or is no longer
+// needed, and frees its peer for other requests. A peer that failed to serve
+// the request is considered stateless for a while.
+func (s *Syncer) release(peer SyncPeer, id uint64, failed bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.pend, id)
+	delete(s.busy, peer.ID())
+	if failed {
+		s.stateless[peer.ID()] = time.Now().Add(statelessBackoff)
+	}
+	s.notify()
+}
+
+// markStateless flags a peer as unable to serve the current sync root, so no
+// more requests are assigned to it for a while, or until the root changes.
+func (s *Syncer) markStateless(peer SyncPeer) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateless[peer.ID()] = time.Now().Add(statelessBackoff)
+}
+
+// notify wakes up the requests waiting for a peer to be assigned to. It must be
+// called with the lock held.
+func (s *Syncer) notify() {
+	close(s.update)
+	s.update = make(chan struct{})
+}
+
+// reportProgress logs the current state sync progress, unless it was reported
+// recently and the report is not forced.
+func (s *Syncer) reportProgress(force bool) {
+	if !force && time.Since(s.logTime) < progressInterval {
+		return
+	}
+	s.logTime = time.Now()
+
+	progress := s.Progress()
+	log.Info("Syncing: state download in progress", "accounts", progress.Accounts, "slots", progress.Storage,
+		"codes", progress.Bytecodes, "healed", progress.TrienodeHealSynced, "next", s.next)
+}
+
+// proofSet converts a list of proof nodes into a proof database, or nil if
+// there are no proofs at all.
+func proofSet(proof [][]byte) kvdb.KeyValueReader {
+	if len(proof) == 0 {
+		return nil
+	}
+	set := trienode.NewProofSet()
+	for _, node := range proof {
+		set.Put(crypto.Keccak256(node), node)
+	}
+	return set
+}
+
+// incHash returns the next hash, in lexicographical order (a.k.a plus one).
+// Note it's meant to be used for the range markers, which never overflow.
+func incHash(h common.Hash) common.Hash {
+	return common.BigToHash(new(big.Int).Add(h.Big(), common.Big1))
+}
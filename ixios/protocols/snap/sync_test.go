@@ -0,0 +1,530 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/rawdb"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/kvdb"
+	"github.com/ixios-io/ixiosSpark/log"
+	"github.com/ixios-io/ixiosSpark/rlp"
+	"github.com/ixios-io/ixiosSpark/trie"
+	"github.com/ixios-io/ixiosSpark/trie/trienode"
+	"github.com/ixios-io/ixiosSpark/triedb"
+)
+
+// testSource is a set of states served by the test peers.
+type testSource struct {
+	db    *triedb.Database
+	codes map[common.Hash][]byte
+}
+
+// newTestSource creates an empty set of states to serve.
+func newTestSource() *testSource {
+	return &testSource{
+		db:    triedb.NewDatabase(rawdb.NewMemoryDatabase(), triedb.HashDefaults),
+		codes: make(map[common.Hash][]byte),
+	}
+}
+
+// makeState creates a state of n accounts, some of them with bytecode and some
+// with storage, one of which too large to be served in one response. Different
+// seeds change the balances of a part of the accounts.
+func (src *testSource) makeState(t *testing.T, n int, seed uint64) common.Hash {
+	t.Helper()
+
+	var (
+		nodes   = trienode.NewMergedNodeSet()
+		accTrie = trie.NewEmpty(src.db)
+	)
+	for i := 0; i < n; i++ {
+		key := crypto.Keccak256Hash(binary.BigEndian.AppendUint64(nil, uint64(i)))
+		account := types.StateAccount{
+			Nonce:    uint64(i),
+			Balance:  uint256.NewInt(uint64(i) * 1000),
+			Root:     types.EmptyRootHash,
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		}
+		if i%3 == 0 {
+			account.Balance.AddUint64(account.Balance, seed)
+		}
+		if i%5 == 0 {
+			code := []byte{byte(i % 20), 0x60, 0x00}
+			hash := crypto.Keccak256Hash(code)
+			src.codes[hash] = code
+			account.CodeHash = hash.Bytes()
+		}
+		if slots := storageSize(i); slots > 0 {
+			stTrie, err := trie.New(trie.StorageTrieID(types.EmptyRootHash, key, types.EmptyRootHash), src.db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < slots; j++ {
+				slot := crypto.Keccak256(binary.BigEndian.AppendUint64(key.Bytes(), uint64(j)))
+				value, _ := rlp.EncodeToBytes(uint64(j + 1))
+				stTrie.MustUpdate(slot, value)
+			}
+			root, set, err := stTrie.Commit(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := nodes.Merge(set); err != nil {
+				t.Fatal(err)
+			}
+			account.Root = root
+		}
+		blob, _ := rlp.EncodeToBytes(&account)
+		accTrie.MustUpdate(key[:], blob)
+	}
+	root, set, err := accTrie.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nodes.Merge(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.db.Update(root, types.EmptyRootHash, 0, nodes, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.db.Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// storageSize returns the number of storage slots of the i-th test account.
+func storageSize(i int) int {
+	switch {
+	case i == 7:
+		return 500 // Needs to be retrieved in chunks
+	case i%10 == 0:
+		return 20
+	default:
+		return 0
+	}
+}
+
+// testPeer is a mock remote peer serving the states of a test source.
+type testPeer struct {
+	id     string
+	src    *testSource
+	syncer *Syncer
+	logger log.Logger
+
+	accountCap int // Maximum number of accounts in a range response
+	slotCap    int // Maximum number of slots in a storage response
+
+	drop    atomic.Int32 // Number of requests to leave unanswered
+	ranges  atomic.Int32 // Number of account ranges requested
+	onRange func()       // Hook invoked on every account range request
+}
+
+// newTestPeer creates a mock peer serving src to the syncer.
+func newTestPeer(id string, src *testSource, syncer *Syncer) *testPeer {
+	return &testPeer{
+		id:         id,
+		src:        src,
+		syncer:     syncer,
+		logger:     log.New("id", id),
+		accountCap: 40,
+		slotCap:    100,
+	}
+}
+
+func (p *testPeer) ID() string      { return p.id }
+func (p *testPeer) Log() log.Logger { return p.logger }
+
+// respond delivers the packet built by serve to the syncer, unless the peer is
+// set to drop the request.
+func (p *testPeer) respond(serve func() Packet) error {
+	if p.drop.Add(-1) >= 0 {
+		return nil
+	}
+	go p.syncer.Deliver(p, serve())
+	return nil
+}
+
+// openState opens the account trie of the given root, if the peer has it.
+func (p *testPeer) openState(root common.Hash) *trie.Trie {
+	tr, err := trie.New(trie.StateTrieID(root), p.src.db)
+	if err != nil {
+		return nil
+	}
+	return tr
+}
+
+// openStorage opens the storage trie of the given account, if the peer has it.
+func (p *testPeer) openStorage(accTrie *trie.Trie, root common.Hash, account []byte) *trie.Trie {
+	blob, err := accTrie.Get(account)
+	if err != nil || len(blob) == 0 {
+		return nil
+	}
+	var acc types.StateAccount
+	if err := rlp.DecodeBytes(blob, &acc); err != nil {
+		return nil
+	}
+	tr, err := trie.New(trie.StorageTrieID(root, common.BytesToHash(account), acc.Root), p.src.db)
+	if err != nil {
+		return nil
+	}
+	return tr
+}
+
+// proveRange collects the proof of the first and last key of a range.
+func proveRange(tr *trie.Trie, origin []byte, last []byte) [][]byte {
+	proof := trienode.NewProofSet()
+	tr.Prove(origin, proof)
+	if last != nil {
+		tr.Prove(last, proof)
+	}
+	var proofs [][]byte
+	for _, blob := range proof.List() {
+		proofs = append(proofs, blob)
+	}
+	return proofs
+}
+
+func (p *testPeer) RequestAccountRange(id uint64, root, origin, limit common.Hash, size uint64) error {
+	p.ranges.Add(1)
+	if p.onRange != nil {
+		p.onRange()
+	}
+	return p.respond(func() Packet {
+		packet := &AccountRangePacket{ID: id}
+		tr := p.openState(root)
+		if tr == nil {
+			return packet
+		}
+		var last []byte
+		it := trie.NewIterator(tr.MustNodeIterator(origin[:]))
+		for it.Next() && len(packet.Accounts) < p.accountCap {
+			if bytes.Compare(it.Key, limit[:]) > 0 {
+				break
+			}
+			var account types.StateAccount
+			rlp.DecodeBytes(it.Value, &account)
+			packet.Accounts = append(packet.Accounts, &AccountData{
+				Hash: common.BytesToHash(it.Key),
+				Body: types.SlimAccountRLP(account),
+			})
+			last = common.CopyBytes(it.Key)
+		}
+		packet.Proof = proveRange(tr, origin[:], last)
+		return packet
+	})
+}
+
+func (p *testPeer) RequestStorageRanges(id uint64, root common.Hash, accounts []common.Hash, origin, limit []byte, bytes uint64) error {
+	return p.respond(func() Packet {
+		packet := &StorageRangesPacket{ID: id}
+		accTrie := p.openState(root)
+		if accTrie == nil {
+			return packet
+		}
+		served := 0
+		for i, account := range accounts {
+			stTrie := p.openStorage(accTrie, root, account[:])
+			if stTrie == nil {
+				return packet
+			}
+			start := make([]byte, common.HashLength)
+			if i == 0 && len(origin) > 0 {
+				start = origin
+			}
+			var (
+				slots  []*StorageData
+				last   []byte
+				capped bool
+			)
+			it := trie.NewIterator(stTrie.MustNodeIterator(start))
+			for it.Next() {
+				if served == p.slotCap {
+					capped = true
+					break
+				}
+				slots = append(slots, &StorageData{Hash: common.BytesToHash(it.Key), Body: common.CopyBytes(it.Value)})
+				last = common.CopyBytes(it.Key)
+				served++
+			}
+			packet.Slots = append(packet.Slots, slots)
+			if capped || common.BytesToHash(start) != (common.Hash{}) {
+				packet.Proof = proveRange(stTrie, start, last)
+			}
+			if capped || served == p.slotCap {
+				break
+			}
+		}
+		return packet
+	})
+}
+
+func (p *testPeer) RequestByteCodes(id uint64, hashes []common.Hash, bytes uint64) error {
+	return p.respond(func() Packet {
+		packet := &ByteCodesPacket{ID: id}
+		for _, hash := range hashes {
+			if code, ok := p.src.codes[hash]; ok {
+				packet.Codes = append(packet.Codes, code)
+			}
+		}
+		return packet
+	})
+}
+
+func (p *testPeer) RequestTrieNodes(id uint64, root common.Hash, paths []TrieNodePathSet, bytes uint64) error {
+	return p.respond(func() Packet {
+		packet := &TrieNodesPacket{ID: id}
+		accTrie := p.openState(root)
+		if accTrie == nil {
+			return packet
+		}
+		for _, pathset := range paths {
+			if len(pathset) == 1 {
+				if blob, _, err := accTrie.GetNode(pathset[0]); err == nil && len(blob) > 0 {
+					packet.Nodes = append(packet.Nodes, blob)
+				}
+				continue
+			}
+			stTrie := p.openStorage(accTrie, root, pathset[0])
+			if stTrie == nil {
+				continue
+			}
+			for _, path := range pathset[1:] {
+				if blob, _, err := stTrie.GetNode(path); err == nil && len(blob) > 0 {
+					packet.Nodes = append(packet.Nodes, blob)
+				}
+			}
+		}
+		return packet
+	})
+}
+
+// setupSyncer creates a syncer over db with the given peers serving src.
+func setupSyncer(t *testing.T, db kvdb.KeyValueStore, src *testSource, peers int) (*Syncer, []*testPeer) {
+	t.Helper()
+
+	syncer := NewSyncer(db, rawdb.HashScheme)
+	var list []*testPeer
+	for i := 0; i < peers; i++ {
+		peer := newTestPeer(string(rune('a'+i)), src, syncer)
+		if err := syncer.Register(peer); err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, peer)
+	}
+	return syncer, list
+}
+
+// stopAfter returns a channel closed once the peers were requested the given
+// number of account ranges in total.
+func stopAfter(ranges int32, peers []*testPeer) chan struct{} {
+	var (
+		stop  = make(chan struct{})
+		count atomic.Int32
+	)
+	for _, peer := range peers {
+		peer.onRange = func() {
+			if count.Add(1) == ranges {
+				close(stop)
+			}
+		}
+	}
+	return stop
+}
+
+// syncState runs a sync of root, failing the test if it doesn't finish in time.
+func syncState(t *testing.T, syncer *Syncer, root common.Hash, cancel chan struct{}) error {
+	t.Helper()
+
+	done := make(chan error, 1)
+	go func() { done <- syncer.Sync(root, cancel) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Minute):
+		t.Fatal("sync timed out")
+		return nil
+	}
+}
+
+// checkState verifies that db holds the complete state of the given root.
+func checkState(t *testing.T, db kvdb.Database, root common.Hash) {
+	t.Helper()
+
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	accTrie, err := trie.New(trie.StateTrieID(root), tdb)
+	if err != nil {
+		t.Fatalf("failed to open account trie: %v", err)
+	}
+	var accounts int
+	it := trie.NewIterator(accTrie.MustNodeIterator(nil))
+	for it.Next() {
+		accounts++
+
+		var account types.StateAccount
+		if err := rlp.DecodeBytes(it.Value, &account); err != nil {
+			t.Fatalf("invalid account %x: %v", it.Key, err)
+		}
+		if hash := common.BytesToHash(account.CodeHash); hash != types.EmptyCodeHash && !rawdb.HasCode(db, hash) {
+			t.Fatalf("missing code %x of account %x", hash, it.Key)
+		}
+		if account.Root == types.EmptyRootHash {
+			continue
+		}
+		stTrie, err := trie.New(trie.StorageTrieID(root, common.BytesToHash(it.Key), account.Root), tdb)
+		if err != nil {
+			t.Fatalf("failed to open storage trie of %x: %v", it.Key, err)
+		}
+		st := trie.NewIterator(stTrie.MustNodeIterator(nil))
+		for st.Next() {
+		}
+		if st.Err != nil {
+			t.Fatalf("incomplete storage trie of %x: %v", it.Key, st.Err)
+		}
+	}
+	if it.Err != nil {
+		t.Fatalf("incomplete account trie: %v", it.Err)
+	}
+	if accounts == 0 {
+		t.Fatal("no accounts synced")
+	}
+}
+
+// TestSyncRanges checks that a state is retrieved in full via the account and
+// storage ranges, including storage tries served in chunks.
+func TestSyncRanges(t *testing.T) {
+	src := newTestSource()
+	root := src.makeState(t, 300, 0)
+
+	db := rawdb.NewMemoryDatabase()
+	syncer, _ := setupSyncer(t, db, src, 3)
+	if err := syncState(t, syncer, root, make(chan struct{})); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, root)
+
+	if progress := syncer.Progress(); progress.Accounts != 300 || progress.TrienodeHealSynced != 0 {
+		t.Errorf("unexpected progress: %d accounts, %d healed nodes", progress.Accounts, progress.TrienodeHealSynced)
+	}
+	if blob := rawdb.ReadSnapshotSyncStatus(db); len(blob) != 0 {
+		t.Error("sync status left after completion")
+	}
+}
+
+// TestSyncHeal checks that a state is retrieved in full by healing alone.
+func TestSyncHeal(t *testing.T) {
+	src := newTestSource()
+	root := src.makeState(t, 300, 0)
+
+	db := rawdb.NewMemoryDatabase()
+	syncer, _ := setupSyncer(t, db, src, 3)
+	syncer.ranged = true
+
+	if err := syncState(t, syncer, root, make(chan struct{})); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, root)
+
+	if progress := syncer.Progress(); progress.TrienodeHealSynced == 0 || progress.BytecodeHealSynced == 0 {
+		t.Errorf("nothing healed: %d nodes, %d codes", progress.TrienodeHealSynced, progress.BytecodeHealSynced)
+	}
+}
+
+// TestSyncPivotMove checks that a sync interrupted to move to a new root keeps
+// the ranges retrieved so far and heals the rest of the new state.
+func TestSyncPivotMove(t *testing.T) {
+	src := newTestSource()
+	root1 := src.makeState(t, 300, 0)
+	root2 := src.makeState(t, 300, 1)
+
+	db := rawdb.NewMemoryDatabase()
+	syncer, peers := setupSyncer(t, db, src, 3)
+
+	cancel := stopAfter(3, peers)
+	if err := syncState(t, syncer, root1, cancel); err != ErrCancelled {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, ErrCancelled)
+	}
+	if syncer.next == (common.Hash{}) {
+		t.Fatal("no ranges retrieved before the pivot move")
+	}
+	if err := syncState(t, syncer, root2, make(chan struct{})); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, root2)
+
+	if progress := syncer.Progress(); progress.TrienodeHealSynced == 0 {
+		t.Error("nothing healed after the pivot move")
+	}
+}
+
+// TestSyncResume checks that a sync interrupted by a restart resumes the account
+// ranges where it left off.
+func TestSyncResume(t *testing.T) {
+	src := newTestSource()
+	root := src.makeState(t, 300, 0)
+
+	db := rawdb.NewMemoryDatabase()
+	syncer, peers := setupSyncer(t, db, src, 3)
+
+	cancel := stopAfter(4, peers)
+	if err := syncState(t, syncer, root, cancel); err != ErrCancelled {
+		t.Fatalf("sync error mismatch: have %v, want %v", err, ErrCancelled)
+	}
+	// Restart the syncer on the same database
+	resumed, peers := setupSyncer(t, db, src, 3)
+	if resumed.root != root || resumed.next != syncer.next || resumed.next == (common.Hash{}) {
+		t.Fatalf("sync status not restored: root %x, next %x, want root %x, next %x", resumed.root, resumed.next, root, syncer.next)
+	}
+	if err := syncState(t, resumed, root, make(chan struct{})); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, root)
+
+	var ranges int32
+	for _, peer := range peers {
+		ranges += peer.ranges.Load()
+	}
+	if full := int32(300/peers[0].accountCap + 1); ranges >= full {
+		t.Errorf("ranges not resumed: requested %d, full sync needs %d", ranges, full)
+	}
+}
+
+// TestSyncStatelessBackoff checks that a peer which timed out is retried after a
+// while, instead of stalling the sync when there is no one else to ask.
+func TestSyncStatelessBackoff(t *testing.T) {
+	defer func(timeout, backoff time.Duration) {
+		requestTimeout, statelessBackoff = timeout, backoff
+	}(requestTimeout, statelessBackoff)
+	requestTimeout, statelessBackoff = 50*time.Millisecond, 100*time.Millisecond
+
+	src := newTestSource()
+	root := src.makeState(t, 100, 0)
+
+	db := rawdb.NewMemoryDatabase()
+	syncer, peers := setupSyncer(t, db, src, 1)
+	peers[0].drop.Store(3)
+
+	if err := syncState(t, syncer, root, make(chan struct{})); err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	checkState(t, db, root)
+}
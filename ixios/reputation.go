@@ -0,0 +1,131 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package ixios
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/ixios/protocols/eth"
+	"github.com/ixios-io/ixiosSpark/p2p"
+	"github.com/ixios-io/ixiosSpark/p2p/enode"
+	"github.com/ixios-io/ixiosSpark/zeta"
+)
+
+// Reputation score adjustments for the behaviour of `ixios` peers. A node whose
+// score drops to p2p.MinPeerScore is banned for a while.
+const (
+	scoreUseful      = 1   // Delivered data which was verified to be valid
+	scoreUnderpriced = -1  // Per broadcast transaction priced below 1 zeta
+	scoreStaleHead   = -5  // Announced or advertised a head far behind or never delivered
+	scoreTimeout     = -10 // Failed to answer a request in time
+	scoreViolation   = -25 // Violated the protocol in some other way
+	scoreBadChain    = -50 // Delivered an invalid fastClique seal or other invalid chain data
+
+	maxUnderpricedPenalty = -10 // Cap of the penalty for a single transaction delivery
+	staleAnnounceDist     = 32  // Blocks an announcement may lag behind the local head
+)
+
+// errTxAnnounceMismatch is reported for peers delivering transactions differing
+// from their announcement.
+var errTxAnnounceMismatch = errors.New("announced transaction metadata mismatch")
+
+// peerReputation is the store keeping track of the reputation of remote nodes
+// across connections, implemented by the p2p server.
+type peerReputation interface {
+	PeerScore(id enode.ID) int
+	AdjustPeerScore(id enode.ID, delta int, reason string) bool
+}
+
+// peerScore returns the reputation score of a peer, zero if nothing is known
+// about it.
+func (h *handler) peerScore(id string) int {
+	if h.reputation == nil {
+		return 0
+	}
+	node, err := enode.ParseID(id)
+	if err != nil {
+		return 0
+	}
+	return h.reputation.PeerScore(node)
+}
+
+// adjustScore changes the reputation score of a peer, disconnecting it if it got
+// banned for misbehaving. Trusted peers are never disconnected.
+func (h *handler) adjustScore(id string, delta int, reason string) {
+	if h.reputation == nil {
+		return
+	}
+	node, err := enode.ParseID(id)
+	if err != nil {
+		return // Tests use short IDs, don't choke on them
+	}
+	if !h.reputation.AdjustPeerScore(node, delta, reason) {
+		return
+	}
+	if peer := h.peers.peer(id); peer != nil && !peer.Peer.Info().Network.Trusted {
+		peer.Log().Debug("Dropping banned peer", "reason", reason)
+		peer.Peer.Disconnect(p2p.DiscUselessPeer)
+	}
+}
+
+// dropPeer requests disconnection of a peer, weighing the misbehaviour it is
+// dropped for on its reputation.
+func (h *handler) dropPeer(id string, reason error) {
+	if penalty := dropPenalty(reason); penalty != 0 {
+		h.adjustScore(id, penalty, reason.Error())
+	}
+	h.removePeer(id)
+}
+
+// dropPenalty returns the reputation penalty for the misbehaviour a peer is
+// dropped for, zero if the reason is not a misbehaviour.
+func dropPenalty(reason error) int {
+	switch {
+	case errors.Is(reason, eth.ErrBadChain):
+		return scoreBadChain
+	case errors.Is(reason, eth.ErrRequestTimeout):
+		return scoreTimeout
+	case errors.Is(reason, eth.ErrStaleHead):
+		return scoreStaleHead
+	case errors.Is(reason, errTxAnnounceMismatch):
+		return scoreViolation
+	default:
+		return 0
+	}
+}
+
+// penalizeUnderpriced lowers the reputation of a peer for the transactions it
+// sent priced below the minimum gas price of 1 zeta, which the pool rejects
+// anyway. Forwarding a few by mistake is cheap, spamming them is not.
+func (h *handler) penalizeUnderpriced(peer *eth.Peer, txs []*types.Transaction) {
+	head := h.chain.CurrentBlock()
+	minPrice := zeta.Value(h.chain.Config(), head.Number, head.Time)
+
+	var underpriced int
+	for _, tx := range txs {
+		if tx.GasPrice().Cmp(minPrice) < 0 {
+			underpriced++
+		}
+	}
+	if underpriced == 0 {
+		return
+	}
+	penalty := underpriced * scoreUnderpriced
+	if penalty < maxUnderpricedPenalty {
+		penalty = maxUnderpricedPenalty
+	}
+	h.adjustScore(peer.ID(), penalty, fmt.Sprintf("%d transactions below 1 zeta", underpriced))
+}
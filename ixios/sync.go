@@ -143,7 +143,7 @@ func (cs *chainSyncer) nextSyncOp() *chainSyncOp {
 	// We have enough peers, pick the one with the highest TD, but avoid going
 	// over the terminal total difficulty. Above that we expect the consensus
 	// clients to direct the chain head to sync to.
-	peer := cs.handler.peers.peerWithHighestTD()
+	peer := cs.handler.peers.peerWithHighestTD(cs.handler.peerScore)
 	if peer == nil {
 		return nil
 	}
@@ -161,6 +161,12 @@ func peerToSyncOp(mode downloader.SyncMode, p *eth.Peer) *chainSyncOp {
 }
 
 func (cs *chainSyncer) modeAndLocalHead() (downloader.SyncMode, *big.Int) {
+	// Header-only nodes never sync anything but headers
+	if cs.handler.light {
+		head := cs.handler.chain.CurrentHeader()
+		td := cs.handler.chain.GetTd(head.Hash(), head.Number.Uint64())
+		return downloader.LightSync, td
+	}
 	// If we're in snap sync mode, return that directly
 	if cs.handler.snapSync.Load() {
 		block := cs.handler.chain.CurrentSnapBlock()
@@ -204,6 +210,7 @@ func (h *handler) doSync(op *chainSyncOp) error {
 	if err != nil {
 		return err
 	}
+	h.adjustScore(op.peer.ID(), scoreUseful, "synced")
 	h.enableSyncedFeatures()
 
 	head := h.chain.CurrentBlock()
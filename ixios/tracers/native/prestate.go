@@ -21,6 +21,7 @@ import (
 
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
+	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/core/vm"
 	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/ixios/tracers"
@@ -96,9 +97,12 @@ func (t *prestateTracer) CaptureStart(env *vm.EVM, from common.Address, to commo
 	t.lookupAccount(to)
 	t.lookupAccount(env.Context.Coinbase)
 
-	// The recipient balance includes the value transferred.
-	toBal := new(big.Int).Sub(t.pre[to].Balance, value)
-	t.pre[to].Balance = toBal
+	// The recipient balance includes the value transferred, unless it went to a
+	// 20 byte address, whose funds are only visible from its 32 byte account.
+	if !types.IsLegacyAddress(to) {
+		toBal := new(big.Int).Sub(t.pre[to].Balance, value)
+		t.pre[to].Balance = toBal
+	}
 
 	// The sender balance is after reducing: value and gasLimit.
 	// We need to re-add them to get the pre-tx balance.
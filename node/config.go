@@ -76,6 +76,10 @@ type Config struct {
 	// ExternalSigner specifies an external URI for a clef-type signer.
 	ExternalSigner string `toml:",omitempty"`
 
+	// ExternalCosigners specifies the external URIs of further clef-type signers,
+	// holding the member keys of the key group backing a validator.
+	ExternalCosigners []string `toml:",omitempty"`
+
 	// UseLightweightKDF lowers the memory and CPU requirements of the key store
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -34,11 +35,12 @@ import (
 
 // Keys in the node database.
 const (
-	dbVersionKey   = "version" // Version of the database to flush if changes
-	dbNodePrefix   = "n:"      // Identifier to prefix node entries with
-	dbLocalPrefix  = "local:"
-	dbDiscoverRoot = "v4"
-	dbDiscv5Root   = "v5"
+	dbVersionKey       = "version" // Version of the database to flush if changes
+	dbNodePrefix       = "n:"      // Identifier to prefix node entries with
+	dbLocalPrefix      = "local:"
+	dbReputationPrefix = "rep:" // Identifier to prefix reputation entries with
+	dbDiscoverRoot     = "v4"
+	dbDiscv5Root       = "v5"
 
 	// These fields are stored per ID and IP, the full key is "n:<ID>:v4:<IP>:findfail".
 	// Use nodeItemKey to create those keys.
@@ -50,12 +52,20 @@ const (
 	// Local information is keyed by ID only, the full key is "local:<ID>:seq".
 	// Use localItemKey to create those keys.
 	dbLocalSeq = "seq"
+
+	// Reputation information is keyed by ID only, the full key is "rep:<ID>:score".
+	// Use reputationKey to create those keys.
+	dbNodeScore  = "score"
+	dbNodeBanned = "banned"
+	dbNodeRated  = "rated"
 )
 
 const (
-	dbNodeExpiration = 24 * time.Hour // Time after which an unseen node should be dropped.
-	dbCleanupCycle   = time.Hour      // Time period for running the expiration task.
-	dbVersion        = 9
+	dbNodeExpiration       = 24 * time.Hour     // Time after which an unseen node should be dropped.
+	dbReputationExpiration = 7 * 24 * time.Hour // Time after which an unrated node's reputation should be dropped.
+	dbMaxReputations       = 10000              // Maximum number of reputations kept, least recently rated dropped first.
+	dbCleanupCycle         = time.Hour          // Time period for running the expiration task.
+	dbVersion              = 9
 )
 
 var (
@@ -192,6 +202,24 @@ func localItemKey(id ID, field string) []byte {
 	return key
 }
 
+// reputationKey returns the key of a node reputation item.
+func reputationKey(id ID, field string) []byte {
+	key := append([]byte(dbReputationPrefix), id[:]...)
+	key = append(key, ':')
+	key = append(key, field...)
+	return key
+}
+
+// splitReputationKey returns the node id and field of a reputation key.
+func splitReputationKey(key []byte) (id ID, field string) {
+	if !bytes.HasPrefix(key, []byte(dbReputationPrefix)) || len(key) < len(dbReputationPrefix)+len(id)+1 {
+		return ID{}, ""
+	}
+	item := key[len(dbReputationPrefix):]
+	copy(id[:], item[:len(id)])
+	return id, string(item[len(id)+1:])
+}
+
 // fetchInt64 retrieves an integer associated with a particular key.
 func (db *DB) fetchInt64(key []byte) int64 {
 	blob, err := db.lvl.Get(key, nil)
@@ -312,6 +340,7 @@ func (db *DB) expirer() {
 		select {
 		case <-tick.C:
 			db.expireNodes()
+			db.expireReputations()
 		case <-db.quit:
 			return
 		}
@@ -424,6 +453,136 @@ func (db *DB) UpdateFindFailsV5(id ID, ip net.IP, fails int) error {
 	return db.storeInt64(v5Key(id, ip, dbNodeFindFails), int64(fails))
 }
 
+// NodeScore retrieves the reputation score of a node.
+func (db *DB) NodeScore(id ID) int64 {
+	return db.fetchInt64(reputationKey(id, dbNodeScore))
+}
+
+// UpdateNodeScore updates the reputation score of a node. The reputation of a
+// node back at a neutral score which is not banned is deleted.
+func (db *DB) UpdateNodeScore(id ID, score int64) error {
+	db.ensureExpirer()
+	if score == 0 && !time.Now().Before(db.BannedUntil(id)) {
+		deleteRange(db.lvl, reputationKey(id, ""))
+		return nil
+	}
+	if err := db.storeInt64(reputationKey(id, dbNodeScore), score); err != nil {
+		return err
+	}
+	return db.storeInt64(reputationKey(id, dbNodeRated), time.Now().Unix())
+}
+
+// BannedUntil retrieves the time until which a node is banned.
+func (db *DB) BannedUntil(id ID) time.Time {
+	return time.Unix(db.fetchInt64(reputationKey(id, dbNodeBanned)), 0)
+}
+
+// UpdateBannedUntil updates the time until which a node is banned.
+func (db *DB) UpdateBannedUntil(id ID, instance time.Time) error {
+	if err := db.storeInt64(reputationKey(id, dbNodeBanned), instance.Unix()); err != nil {
+		return err
+	}
+	return db.storeInt64(reputationKey(id, dbNodeRated), time.Now().Unix())
+}
+
+// reputation is the reputation record of a node, with times in unix seconds.
+type reputation struct {
+	id     ID
+	score  int64
+	banned int64
+	rated  int64
+}
+
+// iterateReputations calls fn with the reputation record of every node in the
+// database. The fields of a node are adjacent in the key space, so the records
+// are assembled in a single pass.
+func (db *DB) iterateReputations(fn func(rep reputation)) {
+	it := db.lvl.NewIterator(util.BytesPrefix([]byte(dbReputationPrefix)), nil)
+	defer it.Release()
+
+	var rep reputation
+	for it.Next() {
+		id, field := splitReputationKey(it.Key())
+		if id != rep.id {
+			if rep.id != (ID{}) {
+				fn(rep)
+			}
+			rep = reputation{id: id}
+		}
+		val, read := binary.Varint(it.Value())
+		if read <= 0 {
+			continue
+		}
+		switch field {
+		case dbNodeScore:
+			rep.score = val
+		case dbNodeBanned:
+			rep.banned = val
+		case dbNodeRated:
+			rep.rated = val
+		}
+	}
+	if rep.id != (ID{}) {
+		fn(rep)
+	}
+}
+
+// expireReputations deletes the reputation of nodes which have not been rated
+// for some time, then the least recently rated ones beyond dbMaxReputations.
+// Reputations of banned nodes are kept until the ban is over.
+func (db *DB) expireReputations() {
+	var (
+		now       = time.Now()
+		threshold = now.Add(-dbReputationExpiration).Unix()
+		kept      []reputation
+	)
+	db.iterateReputations(func(rep reputation) {
+		switch {
+		case rep.banned > now.Unix():
+		case rep.rated < threshold:
+			deleteRange(db.lvl, reputationKey(rep.id, ""))
+		default:
+			kept = append(kept, rep)
+		}
+	})
+	if len(kept) <= dbMaxReputations {
+		return
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].rated > kept[j].rated
+	})
+	for _, rep := range kept[dbMaxReputations:] {
+		deleteRange(db.lvl, reputationKey(rep.id, ""))
+	}
+}
+
+// QueryReputable retrieves up to n nodes with a known record and a reputation
+// score of at least minScore which are not banned, best scoring first.
+func (db *DB) QueryReputable(n int, minScore int64) []*Node {
+	var (
+		now   = time.Now().Unix()
+		reps  []reputation
+		nodes []*Node
+	)
+	db.iterateReputations(func(rep reputation) {
+		if rep.score >= minScore && rep.banned <= now {
+			reps = append(reps, rep)
+		}
+	})
+	sort.SliceStable(reps, func(i, j int) bool {
+		return reps[i].score > reps[j].score
+	})
+	for _, rep := range reps {
+		if len(nodes) >= n {
+			break
+		}
+		if node := db.Node(rep.id); node != nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 // localSeq retrieves the local record sequence counter, defaulting to the current
 // timestamp if no previous exists. This ensures that wiping all data associated
 // with a node (apart from its key) will not generate already used sequence nums.
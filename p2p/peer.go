@@ -110,6 +110,7 @@ type Peer struct {
 	closed   chan struct{}
 	pingRecv chan struct{}
 	disc     chan DiscReason
+	evicted  bool // Set by the server loop once disconnected to make room for a better peer
 
 	// events receives message send / receive events if set
 	events   *event.Feed
@@ -489,6 +490,7 @@ type PeerInfo struct {
 	ID      string   `json:"id"`            // Unique node identifier
 	Name    string   `json:"name"`          // Name of the node, including client type, version, OS, custom data
 	Caps    []string `json:"caps"`          // Protocols advertised by this peer
+	Score   int      `json:"score"`         // Reputation score of the node
 	Network struct {
 		LocalAddress  string `json:"localAddress"`  // Local endpoint of the TCP data connection
 		RemoteAddress string `json:"remoteAddress"` // Remote endpoint of the TCP data connection
@@ -0,0 +1,103 @@
+// IxiosSpark is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// This file is part of the IxiosSpark library, which builds upon the source code of the geth library.
+// The IxiosSpark source code is distributed with the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+// Copyright 2025 The ixiosSpark Authors, Copyright 2015-2024 The go-ethereum Authors (geth)
+// You should have received a copy of the GNU Lesser General Public License
+// with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"time"
+
+	"github.com/ixios-io/ixiosSpark/p2p/enode"
+)
+
+const (
+	// MaxPeerScore and MinPeerScore bound the reputation score of a node. A node
+	// whose score drops to MinPeerScore is banned for peerBanTime.
+	MaxPeerScore = 100
+	MinPeerScore = -100
+
+	peerBanTime        = time.Hour        // Time a node is refused for once banned
+	peerProbationScore = MinPeerScore / 2 // Score a banned node starts over with
+	maxReputableNodes  = 32               // Number of best scoring nodes to redial on startup
+)
+
+// PeerScore returns the reputation score of a node, zero if nothing is known
+// about it.
+func (srv *Server) PeerScore(id enode.ID) int {
+	srv.reputationLock.Lock()
+	defer srv.reputationLock.Unlock()
+
+	if srv.nodedb == nil {
+		return 0
+	}
+	return int(srv.nodedb.NodeScore(id))
+}
+
+// PeerBanned returns whether a node is banned for having misbehaved.
+func (srv *Server) PeerBanned(id enode.ID) bool {
+	srv.reputationLock.Lock()
+	defer srv.reputationLock.Unlock()
+
+	if srv.nodedb == nil {
+		return false
+	}
+	return time.Now().Before(srv.nodedb.BannedUntil(id))
+}
+
+// AdjustPeerScore changes the reputation score of a node by delta for the given
+// reason and returns whether the node got banned by it. Scores are persisted in
+// the node database, the dialer refuses banned nodes and prefers redialing the
+// best scoring ones. Disconnecting a banned peer is up to the caller.
+func (srv *Server) AdjustPeerScore(id enode.ID, delta int, reason string) bool {
+	srv.reputationLock.Lock()
+	defer srv.reputationLock.Unlock()
+
+	if srv.nodedb == nil {
+		return false
+	}
+	score := int(srv.nodedb.NodeScore(id)) + delta
+	if score > MaxPeerScore {
+		score = MaxPeerScore
+	}
+	if score > MinPeerScore {
+		srv.log.Trace("Adjusted peer score", "id", id, "delta", delta, "score", score, "reason", reason)
+		srv.nodedb.UpdateNodeScore(id, int64(score))
+		return false
+	}
+	until := time.Now().Add(peerBanTime)
+	srv.log.Debug("Banned misbehaving node", "id", id, "reason", reason, "until", until)
+
+	srv.nodedb.UpdateBannedUntil(id, until)
+	srv.nodedb.UpdateNodeScore(id, peerProbationScore)
+	return true
+}
+
+// reputableNodes returns the dialable nodes with the best reputation scores.
+func (srv *Server) reputableNodes() []*enode.Node {
+	srv.reputationLock.Lock()
+	defer srv.reputationLock.Unlock()
+
+	return srv.nodedb.QueryReputable(maxReputableNodes, 1)
+}
+
+// keepReputable stores the record of a dialed peer with a good reputation, so
+// it can be redialed after a restart. Records of inbound peers are not stored,
+// their endpoint is not the one they listen on.
+func (srv *Server) keepReputable(p *Peer) {
+	if !p.rw.is(dynDialedConn|staticDialedConn) || srv.PeerScore(p.ID()) <= 0 {
+		return
+	}
+	srv.reputationLock.Lock()
+	defer srv.reputationLock.Unlock()
+
+	srv.nodedb.UpdateNode(p.Node())
+}
@@ -189,6 +189,9 @@ type Server struct {
 
 	nodedb    *enode.DB
 	localnode *enode.LocalNode
+
+	reputationLock sync.Mutex // Serializes reputation updates in nodedb
+
 	ntab      *discover.UDPv4
 	DiscV5    *discover.UDPv5
 	discmix   *enode.FairMix
@@ -534,6 +537,12 @@ func (srv *Server) setupLocalNode() error {
 func (srv *Server) setupDiscovery() error {
 	srv.discmix = enode.NewFairMix(discmixTimeout)
 
+	// Redial the nodes which served us best before, the dialer picks from them
+	// fairly alongside the discovered ones.
+	if nodes := srv.reputableNodes(); len(nodes) > 0 {
+		srv.discmix.AddSource(enode.IterNodes(nodes))
+	}
+
 	// Don't listen on UDP endpoint if DHT is disabled.
 	if srv.NoDiscovery {
 		return nil
@@ -603,6 +612,7 @@ func (srv *Server) setupDialScheduler() {
 		netRestrict:    srv.NetRestrict,
 		dialer:         srv.Dialer,
 		clock:          srv.clock,
+		banned:         srv.PeerBanned,
 	}
 	if srv.ntab != nil {
 		config.resolver = srv.ntab
@@ -764,6 +774,14 @@ running:
 			// At this point the connection is past the protocol handshake.
 			// Its capabilities are known and the remote identity is verified.
 			err := srv.addPeerChecks(peers, inboundCount, c)
+			if err == nil && !c.is(trustedConn) && len(peers) >= srv.MaxPeers {
+				// The checks passed on a full server, make room for the new peer.
+				if p := srv.evictionCandidate(peers, c); p != nil {
+					p.log.Debug("Evicting peer for a better reputed one", "id", c.node.ID())
+					p.evicted = true
+					p.Disconnect(DiscTooManyPeers)
+				}
+			}
 			if err == nil {
 				// The handshakes are done and it passed all checks.
 				p := srv.launchPeer(c)
@@ -813,7 +831,7 @@ running:
 
 func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
 	switch {
-	case !c.is(trustedConn) && len(peers) >= srv.MaxPeers:
+	case !c.is(trustedConn) && len(peers) >= srv.MaxPeers && srv.evictionCandidate(peers, c) == nil:
 		return DiscTooManyPeers
 	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
 		return DiscTooManyPeers
@@ -821,6 +839,8 @@ func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount in
 		return DiscAlreadyConnected
 	case c.node.ID() == srv.localnode.ID():
 		return DiscSelf
+	case !c.is(trustedConn|staticDialedConn) && srv.PeerBanned(c.node.ID()):
+		return DiscUselessPeer
 	default:
 		return nil
 	}
@@ -836,6 +856,26 @@ func (srv *Server) addPeerChecks(peers map[enode.ID]*Peer, inboundCount int, c *
 	return srv.postHandshakeChecks(peers, inboundCount, c)
 }
 
+// evictionCandidate returns the peer to disconnect to make room for the node of c
+// on a full server: the untrusted peer with the lowest reputation score, if it
+// scores below the node. Peers evicted already are not considered, they still
+// count towards MaxPeers until they are gone.
+func (srv *Server) evictionCandidate(peers map[enode.ID]*Peer, c *conn) *Peer {
+	var (
+		worst *Peer
+		below = srv.PeerScore(c.node.ID())
+	)
+	for _, p := range peers {
+		if p.evicted || p.rw.is(trustedConn) {
+			continue
+		}
+		if score := srv.PeerScore(p.ID()); score < below {
+			worst, below = p, score
+		}
+	}
+	return worst
+}
+
 // listenLoop runs in its own goroutine and accepts
 // inbound connections.
 func (srv *Server) listenLoop() {
@@ -1042,6 +1082,7 @@ func (srv *Server) runPeer(p *Peer) {
 
 	// Run the per-peer main loop.
 	remoteRequested, err := p.run()
+	srv.keepReputable(p)
 
 	// Announce disconnect on the main loop to update the peer set.
 	// The main loop waits for existing peers to be sent on srv.delpeer
@@ -1111,7 +1152,9 @@ func (srv *Server) PeersInfo() []*PeerInfo {
 	infos := make([]*PeerInfo, 0, srv.PeerCount())
 	for _, peer := range srv.Peers() {
 		if peer != nil {
-			infos = append(infos, peer.Info())
+			info := peer.Info()
+			info.Score = srv.PeerScore(peer.ID())
+			infos = append(infos, info)
 		}
 	}
 	// Sort the result array alphabetically by node identifier
@@ -15,6 +15,7 @@ package params
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"os"
 
@@ -52,6 +53,7 @@ var (
 		GrayGlacierBlock:    nil,
 		ShanghaiTime:        nil,
 		CancunTime:          nil,
+		LegacyAliasBlock:    big.NewInt(40000000),
 		Clique: &CliqueConfig{
 			Period: 998,
 			Epoch:  86400,
@@ -76,6 +78,7 @@ var (
 		GrayGlacierBlock:    nil,
 		ShanghaiTime:        nil,
 		CancunTime:          nil,
+		LegacyAliasBlock:    big.NewInt(40000000),
 		Clique: &CliqueConfig{
 			Period: 998,
 			Epoch:  86400,
@@ -99,6 +102,7 @@ var (
 		GrayGlacierBlock:    nil,
 		ShanghaiTime:        nil,
 		CancunTime:          nil,
+		LegacyAliasBlock:    big.NewInt(40000000),
 		Clique: &CliqueConfig{
 			Period: 998,
 			Epoch:  86400,
@@ -122,6 +126,7 @@ var (
 		GrayGlacierBlock:    nil,
 		ShanghaiTime:        nil,
 		CancunTime:          nil,
+		LegacyAliasBlock:    big.NewInt(40000000),
 		Clique: &CliqueConfig{
 			Period: 998,
 			Epoch:  86400,
@@ -144,6 +149,10 @@ var (
 		ArrowGlacierBlock:             big.NewInt(0),
 		GrayGlacierBlock:              big.NewInt(0),
 		ShanghaiTime:                  newUint64(0),
+		PostQuantumBlock:              big.NewInt(0),
+		MultisigBlock:                 big.NewInt(0),
+		KeyRotationBlock:              big.NewInt(0),
+		LegacyAliasBlock:              big.NewInt(0),
 		TerminalTotalDifficulty:       big.NewInt(0),
 		TerminalTotalDifficultyPassed: true,
 	}
@@ -168,6 +177,10 @@ var (
 		ArrowGlacierBlock:             nil,
 		GrayGlacierBlock:              nil,
 		MergeNetsplitBlock:            nil,
+		PostQuantumBlock:              big.NewInt(0),
+		MultisigBlock:                 big.NewInt(0),
+		KeyRotationBlock:              big.NewInt(0),
+		LegacyAliasBlock:              big.NewInt(0),
 		ShanghaiTime:                  nil,
 		CancunTime:                    nil,
 		PragueTime:                    nil,
@@ -307,6 +320,11 @@ type ChainConfig struct {
 	GrayGlacierBlock    *big.Int `json:"grayGlacierBlock,omitempty"`    // Eip-5133 (bomb delay) switch block (nil = no fork, 0 = already activated)
 	MergeNetsplitBlock  *big.Int `json:"mergeNetsplitBlock,omitempty"`  // Virtual fork after The Merge to use as a network splitter
 
+	PostQuantumBlock *big.Int `json:"postQuantumBlock,omitempty"` // Post-quantum transactions switch block (nil = no fork, 0 = already activated)
+	MultisigBlock    *big.Int `json:"multisigBlock,omitempty"`    // Multisig transactions switch block (nil = no fork, 0 = already activated)
+	KeyRotationBlock *big.Int `json:"keyRotationBlock,omitempty"` // Key rotation switch block (nil = no fork, 0 = already activated)
+	LegacyAliasBlock *big.Int `json:"legacyAliasBlock,omitempty"` // Legacy balance aliasing switch block (nil = no fork, 0 = already activated)
+
 	// Fork scheduling was switched from blocks to timestamps here
 
 	ShanghaiTime *uint64 `json:"shanghaiTime,omitempty"` // Shanghai switch time (nil = no fork, 0 = already on shanghai)
@@ -323,6 +341,10 @@ type ChainConfig struct {
 	// even without having seen the TTD locally (safer long term).
 	TerminalTotalDifficultyPassed bool `json:"terminalTotalDifficultyPassed,omitempty"`
 
+	// Zeta lists the minimum gas price schedules of the network ordered by their
+	// activation block. If empty, DefaultZetaConfig applies from genesis.
+	Zeta []*ZetaConfig `json:"zeta,omitempty"`
+
 	// Various consensus engines
 	Ethash  *EthashConfig  `json:"ethash,omitempty"`
 	Clique  *CliqueConfig  `json:"clique,omitempty"`
@@ -341,6 +363,239 @@ func (c *EthashConfig) String() string {
 type CliqueConfig struct {
 	Period uint64 `json:"period"` // Number of seconds between blocks to enforce
 	Epoch  uint64 `json:"epoch"`  // Epoch length to reset votes and checkpoint
+
+	MKSBlock       *big.Int `json:"mksBlock,omitempty"`       // Hybrid MKS seal switch block (nil = no fork, 0 = already activated)
+	ThresholdBlock *big.Int `json:"thresholdBlock,omitempty"` // Key group seal switch block (nil = no fork, 0 = already activated)
+
+	Rewards  []*CliqueReward `json:"rewards,omitempty"`  // Block reward schedule ordered by activation block (empty = no issuance)
+	Treasury *common.Address `json:"treasury,omitempty"` // Recipient of the treasury share of block rewards
+}
+
+// CliqueReward is an entry of the clique block reward schedule. From its
+// activation block on, every block mints Reward wei, of which TreasuryShare
+// basis points are credited to the treasury and the rest to the sealer.
+type CliqueReward struct {
+	Block         *big.Int `json:"block,omitempty"`         // Activation block of the entry (nil = genesis)
+	Reward        *big.Int `json:"reward"`                  // Wei minted per block
+	TreasuryShare uint64   `json:"treasuryShare,omitempty"` // Basis points of the reward sent to the treasury
+}
+
+// CliqueRewardDenominator is the denominator of CliqueReward.TreasuryShare.
+const CliqueRewardDenominator = 10000
+
+// RewardAt returns the block reward schedule entry active at block num, or nil
+// if no reward is minted.
+func (c *CliqueConfig) RewardAt(num *big.Int) *CliqueReward {
+	var active *CliqueReward
+	for _, r := range c.Rewards {
+		if !isBlockForked(rewardBlock(r), num) {
+			break
+		}
+		active = r
+	}
+	return active
+}
+
+// checkRewards validates the block reward schedule.
+func (c *CliqueConfig) checkRewards() error {
+	if c.Treasury != nil && isECDSA20(*c.Treasury) {
+		return fmt.Errorf("invalid clique treasury %v: 20 byte addresses cannot hold rewards, use the 32 byte account", *c.Treasury)
+	}
+	var last *big.Int
+	for i, r := range c.Rewards {
+		if r.Reward == nil || r.Reward.Sign() < 0 {
+			return fmt.Errorf("invalid clique reward #%d: missing or negative reward", i)
+		}
+		if r.TreasuryShare > CliqueRewardDenominator {
+			return fmt.Errorf("invalid clique reward #%d: treasury share %d above %d", i, r.TreasuryShare, CliqueRewardDenominator)
+		}
+		if r.TreasuryShare > 0 && c.Treasury == nil {
+			return fmt.Errorf("invalid clique reward #%d: treasury share without treasury", i)
+		}
+		block := r.Block
+		if block == nil {
+			block = common.Big0
+		}
+		if i > 0 && block.Cmp(last) <= 0 {
+			return fmt.Errorf("unsupported clique reward ordering: entry #%d at block %v, but entry #%d at block %v", i-1, last, i, block)
+		}
+		last = block
+	}
+	return nil
+}
+
+// isECDSA20 reports whether addr is a legacy 20 byte address padded with zeros
+// to 32 bytes. The state reports no balance of its own for such accounts.
+func isECDSA20(addr common.Address) bool {
+	for _, b := range addr[:common.AddressLength-20] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// rewardsIncompatible reports whether the reward schedule of c1 cannot be
+// replaced by that of c2 because head is already past a differing entry,
+// returning the activation block of the first mismatch.
+func rewardsIncompatible(c1, c2 *CliqueConfig, head *big.Int) (*big.Int, bool) {
+	r1, r2 := c1.Rewards, c2.Rewards
+	for i := 0; i < len(r1) || i < len(r2); i++ {
+		var a, b *CliqueReward
+		if i < len(r1) {
+			a = r1[i]
+		}
+		if i < len(r2) {
+			b = r2[i]
+		}
+		activeA := a != nil && isBlockForked(rewardBlock(a), head)
+		activeB := b != nil && isBlockForked(rewardBlock(b), head)
+		if !activeA && !activeB {
+			break
+		}
+		if a == nil || b == nil || !configBlockEqual(a.Block, b.Block) || a.Reward.Cmp(b.Reward) != 0 || a.TreasuryShare != b.TreasuryShare {
+			if activeA {
+				return rewardBlock(a), true
+			}
+			return rewardBlock(b), true
+		}
+	}
+	// The treasury may only change while no share of past rewards went to it
+	if r := c1.RewardAt(head); r != nil && r.TreasuryShare > 0 {
+		if c2.Treasury == nil || *c1.Treasury != *c2.Treasury {
+			return rewardBlock(r), true
+		}
+	}
+	return nil, false
+}
+
+// rewardBlock returns the activation block of a reward entry, treating nil as genesis.
+func rewardBlock(r *CliqueReward) *big.Int {
+	if r.Block == nil {
+		return new(big.Int)
+	}
+	return r.Block
+}
+
+// ZetaConfig is a schedule of the minimum gas price, 1 zeta, denominated in wei.
+// Starting at its activation block the value decays geometrically per epoch
+// from Base towards Min.
+//
+// Epochs are counted in blocks from the activation block by default. If
+// EpochTime is set they are instead derived from the millisecond header
+// timestamps relative to StartTime, which keeps the schedule on the wall clock
+// regardless of how far the block rate drifts from one block per second.
+type ZetaConfig struct {
+	Block       *big.Int `json:"block,omitempty"`       // Activation block of the schedule (nil = genesis)
+	Base        *big.Int `json:"base"`                  // Value of 1 zeta during the first epoch
+	Min         *big.Int `json:"min"`                   // Value 1 zeta decays towards
+	DecayRate   uint64   `json:"decayRate"`             // Per-epoch decay factor with 18 decimals of precision
+	EpochLength uint64   `json:"epochLength,omitempty"` // Number of blocks per epoch
+	EpochTime   uint64   `json:"epochTime,omitempty"`   // Milliseconds per epoch (0 = block based epochs)
+	StartTime   uint64   `json:"startTime,omitempty"`   // Timestamp in milliseconds time based epochs count from (0 = genesis timestamp)
+}
+
+// IsTimeBased returns whether the epochs of the schedule are derived from
+// header timestamps rather than block numbers.
+func (z *ZetaConfig) IsTimeBased() bool {
+	return z.EpochTime != 0
+}
+
+// DefaultZetaConfig is the schedule used by networks which do not configure
+// one: 1 zeta starts at 10^15 wei and drops by 7% every 31536000 blocks, i.e.
+// yearly at one block per second.
+var DefaultZetaConfig = &ZetaConfig{
+	Base:        big.NewInt(1_000_000_000_000_000),
+	Min:         big.NewInt(1),
+	DecayRate:   930_000_000_000_000_000,
+	EpochLength: 31_536_000,
+}
+
+// ZetaDecayPrecision is the fixed point denominator of ZetaConfig.DecayRate.
+const ZetaDecayPrecision = 1_000_000_000_000_000_000
+
+// String implements the stringer interface, returning the schedule details.
+func (z *ZetaConfig) String() string {
+	if z.IsTimeBased() {
+		return fmt.Sprintf("zeta(block: %v, base: %v, min: %v, decay: %d, epoch: %dms, start: %d)", z.Block, z.Base, z.Min, z.DecayRate, z.EpochTime, z.StartTime)
+	}
+	return fmt.Sprintf("zeta(block: %v, base: %v, min: %v, decay: %d, epoch: %d)", z.Block, z.Base, z.Min, z.DecayRate, z.EpochLength)
+}
+
+// Epoch returns the 0-based epoch of the schedule that the block with number
+// num and millisecond timestamp time falls into. The caller must ensure the
+// schedule is active at num.
+func (z *ZetaConfig) Epoch(num *big.Int, time uint64) uint64 {
+	if z.IsTimeBased() {
+		if time < z.StartTime {
+			return 0
+		}
+		return (time - z.StartTime) / z.EpochTime
+	}
+	elapsed := new(big.Int).Set(num)
+	if z.Block != nil {
+		elapsed.Sub(elapsed, z.Block)
+	}
+	epoch := elapsed.Div(elapsed, new(big.Int).SetUint64(z.EpochLength))
+	if !epoch.IsUint64() {
+		return math.MaxUint64
+	}
+	return epoch.Uint64()
+}
+
+// equal reports whether two schedules are identical.
+func (z *ZetaConfig) equal(other *ZetaConfig) bool {
+	return configBlockEqual(z.Block, other.Block) &&
+		z.Base.Cmp(other.Base) == 0 && z.Min.Cmp(other.Min) == 0 &&
+		z.DecayRate == other.DecayRate && z.EpochLength == other.EpochLength &&
+		z.EpochTime == other.EpochTime && z.StartTime == other.StartTime
+}
+
+// SetZetaStartTime anchors time based zeta schedules without an explicit start
+// time to the given genesis timestamp.
+func (c *ChainConfig) SetZetaStartTime(genesisTime uint64) {
+	for _, z := range c.Zeta {
+		if z.IsTimeBased() && z.StartTime == 0 {
+			z.StartTime = genesisTime
+		}
+	}
+}
+
+// ZetaConfigAt returns the minimum gas price schedule active at block num.
+func (c *ChainConfig) ZetaConfigAt(num *big.Int) *ZetaConfig {
+	active := DefaultZetaConfig
+	for _, z := range c.Zeta {
+		if !isBlockForked(zetaBlock(z), num) {
+			break
+		}
+		active = z
+	}
+	return active
+}
+
+// checkZetaConfig validates the minimum gas price schedules of the network.
+func (c *ChainConfig) checkZetaConfig() error {
+	var last *big.Int
+	for i, z := range c.Zeta {
+		if z.Base == nil || z.Min == nil || z.Base.Sign() <= 0 || z.Min.Sign() <= 0 || z.Base.Cmp(z.Min) < 0 {
+			return fmt.Errorf("invalid zeta schedule #%d: base must be at least min and both positive", i)
+		}
+		if z.DecayRate > ZetaDecayPrecision {
+			return fmt.Errorf("invalid zeta schedule #%d: decay rate %d above %d", i, z.DecayRate, uint64(ZetaDecayPrecision))
+		}
+		if (z.EpochLength == 0) == (z.EpochTime == 0) {
+			return fmt.Errorf("invalid zeta schedule #%d: exactly one of epoch length and epoch time must be set", i)
+		}
+		block := z.Block
+		if block == nil {
+			block = common.Big0
+		}
+		if i > 0 && block.Cmp(last) <= 0 {
+			return fmt.Errorf("unsupported zeta schedule ordering: schedule #%d at block %v, but schedule #%d at block %v", i-1, last, i, block)
+		}
+		last = block
+	}
+	return nil
 }
 
 type GuildioConfig struct {
@@ -354,6 +609,18 @@ func (c *CliqueConfig) String() string {
 	return "clique"
 }
 
+// IsMKS returns whether num is either equal to the MKS fork block or greater,
+// i.e. whether block seals must carry a hybrid Multi-Key-Signature.
+func (c *CliqueConfig) IsMKS(num *big.Int) bool {
+	return isBlockForked(c.MKSBlock, num)
+}
+
+// IsThreshold returns whether num is either equal to the key group fork block or
+// greater, i.e. whether validators may seal blocks with a t-of-n key group.
+func (c *CliqueConfig) IsThreshold(num *big.Int) bool {
+	return isBlockForked(c.ThresholdBlock, num)
+}
+
 // Description returns a human-readable description of ChainConfig.
 func (c *ChainConfig) Description() string {
 	var banner string
@@ -468,6 +735,26 @@ func (c *ChainConfig) IsGrayGlacier(num *big.Int) bool {
 	return isBlockForked(c.GrayGlacierBlock, num)
 }
 
+// IsPostQuantum returns whether num is either equal to the post-quantum fork block or greater.
+func (c *ChainConfig) IsPostQuantum(num *big.Int) bool {
+	return isBlockForked(c.PostQuantumBlock, num)
+}
+
+// IsMultisig returns whether num is either equal to the multisig fork block or greater.
+func (c *ChainConfig) IsMultisig(num *big.Int) bool {
+	return isBlockForked(c.MultisigBlock, num)
+}
+
+// IsKeyRotation returns whether num is either equal to the key rotation fork block or greater.
+func (c *ChainConfig) IsKeyRotation(num *big.Int) bool {
+	return isBlockForked(c.KeyRotationBlock, num)
+}
+
+// IsLegacyAlias returns whether num is either equal to the legacy balance aliasing fork block or greater.
+func (c *ChainConfig) IsLegacyAlias(num *big.Int) bool {
+	return isBlockForked(c.LegacyAliasBlock, num)
+}
+
 // IsTerminalPoWBlock returns whether the given block is the last block of PoW stage.
 func (c *ChainConfig) IsTerminalPoWBlock(parentTotalDiff *big.Int, totalDiff *big.Int) bool {
 	if c.TerminalTotalDifficulty == nil {
@@ -547,6 +834,10 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		{name: "arrowGlacierBlock", block: c.ArrowGlacierBlock, optional: true},
 		{name: "grayGlacierBlock", block: c.GrayGlacierBlock, optional: true},
 		{name: "mergeNetsplitBlock", block: c.MergeNetsplitBlock, optional: true},
+		{name: "postQuantumBlock", block: c.PostQuantumBlock, optional: true},
+		{name: "multisigBlock", block: c.MultisigBlock, optional: true},
+		{name: "keyRotationBlock", block: c.KeyRotationBlock, optional: true},
+		{name: "legacyAliasBlock", block: c.LegacyAliasBlock, optional: true},
 		{name: "shanghaiTime", timestamp: c.ShanghaiTime},
 		{name: "cancunTime", timestamp: c.CancunTime, optional: true},
 		{name: "pragueTime", timestamp: c.PragueTime, optional: true},
@@ -586,6 +877,12 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if err := c.checkZetaConfig(); err != nil {
+		return err
+	}
+	if c.Clique != nil {
+		return c.Clique.checkRewards()
+	}
 	return nil
 }
 
@@ -645,6 +942,32 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkBlockIncompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, headNumber) {
 		return newBlockCompatError("Merge netsplit fork block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	if isForkBlockIncompatible(c.PostQuantumBlock, newcfg.PostQuantumBlock, headNumber) {
+		return newBlockCompatError("Post-quantum fork block", c.PostQuantumBlock, newcfg.PostQuantumBlock)
+	}
+	if isForkBlockIncompatible(c.MultisigBlock, newcfg.MultisigBlock, headNumber) {
+		return newBlockCompatError("Multisig fork block", c.MultisigBlock, newcfg.MultisigBlock)
+	}
+	if isForkBlockIncompatible(c.KeyRotationBlock, newcfg.KeyRotationBlock, headNumber) {
+		return newBlockCompatError("Key rotation fork block", c.KeyRotationBlock, newcfg.KeyRotationBlock)
+	}
+	if isForkBlockIncompatible(c.LegacyAliasBlock, newcfg.LegacyAliasBlock, headNumber) {
+		return newBlockCompatError("Legacy alias fork block", c.LegacyAliasBlock, newcfg.LegacyAliasBlock)
+	}
+	if c.Clique != nil && newcfg.Clique != nil && isForkBlockIncompatible(c.Clique.MKSBlock, newcfg.Clique.MKSBlock, headNumber) {
+		return newBlockCompatError("Clique MKS fork block", c.Clique.MKSBlock, newcfg.Clique.MKSBlock)
+	}
+	if c.Clique != nil && newcfg.Clique != nil && isForkBlockIncompatible(c.Clique.ThresholdBlock, newcfg.Clique.ThresholdBlock, headNumber) {
+		return newBlockCompatError("Clique key group fork block", c.Clique.ThresholdBlock, newcfg.Clique.ThresholdBlock)
+	}
+	if c.Clique != nil && newcfg.Clique != nil {
+		if block, ok := rewardsIncompatible(c.Clique, newcfg.Clique, headNumber); ok {
+			return newBlockCompatError("Clique reward schedule", block, block)
+		}
+	}
+	if block, ok := zetaScheduleIncompatible(c.Zeta, newcfg.Zeta, headNumber); ok {
+		return newBlockCompatError("Zeta schedule", block, block)
+	}
 	if isForkTimestampIncompatible(c.ShanghaiTime, newcfg.ShanghaiTime, headTimestamp) {
 		return newTimestampCompatError("Shanghai fork timestamp", c.ShanghaiTime, newcfg.ShanghaiTime)
 	}
@@ -687,6 +1010,41 @@ func (c *ChainConfig) LatestFork(time uint64) forks.Fork {
 	}
 }
 
+// zetaScheduleIncompatible reports whether the zeta schedules in s1 cannot be
+// replaced by s2 because head is already past a differing schedule, returning
+// the activation block of the first mismatch.
+func zetaScheduleIncompatible(s1, s2 []*ZetaConfig, head *big.Int) (*big.Int, bool) {
+	for i := 0; i < len(s1) || i < len(s2); i++ {
+		var a, b *ZetaConfig
+		if i < len(s1) {
+			a = s1[i]
+		}
+		if i < len(s2) {
+			b = s2[i]
+		}
+		activeA := a != nil && isBlockForked(zetaBlock(a), head)
+		activeB := b != nil && isBlockForked(zetaBlock(b), head)
+		if !activeA && !activeB {
+			return nil, false
+		}
+		if a == nil || b == nil || !a.equal(b) {
+			if activeA {
+				return zetaBlock(a), true
+			}
+			return zetaBlock(b), true
+		}
+	}
+	return nil, false
+}
+
+// zetaBlock returns the activation block of a zeta schedule, treating nil as genesis.
+func zetaBlock(z *ZetaConfig) *big.Int {
+	if z.Block == nil {
+		return new(big.Int)
+	}
+	return z.Block
+}
+
 // isForkBlockIncompatible returns true if a fork scheduled at block s1 cannot be
 // rescheduled to block s2 because head is already past the fork.
 func isForkBlockIncompatible(s1, s2, head *big.Int) bool {
@@ -820,6 +1178,8 @@ type Rules struct {
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
+	IsPostQuantum, IsMultisig, IsKeyRotation                bool
+	IsLegacyAlias                                           bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -847,5 +1207,9 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsCancun:         isMerge && c.IsCancun(num, timestamp),
 		IsPrague:         isMerge && c.IsPrague(num, timestamp),
 		IsVerkle:         isMerge && c.IsVerkle(num, timestamp),
+		IsPostQuantum:    c.IsPostQuantum(num),
+		IsMultisig:       c.IsMultisig(num),
+		IsKeyRotation:    c.IsKeyRotation(num),
+		IsLegacyAlias:    c.IsLegacyAlias(num),
 	}
 }
@@ -92,6 +92,7 @@ const (
 	TxDataNonZeroGasEIP2028   uint64 = 16   // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
 	TxAccessListAddressGas    uint64 = 2400 // Per address specified in EIP 2930 access list
 	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in EIP 2930 access list
+	TxSignatureByteGas        uint64 = 16   // Per byte of public keys and signatures carried by post-quantum, multisig and rotated key transactions
 
 	// These have been changed during the course of the chain
 	CallGasFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
@@ -159,6 +160,14 @@ const (
 	Bls12381MapG1Gas          uint64 = 5500   // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 110000 // Gas price for BLS12-381 mapping field element to G2 operation
 
+	Dilithium2VerifyGas uint64 = 7000  // Gas price for a Dilithium-2 signature verification
+	Dilithium3VerifyGas uint64 = 10000 // Gas price for a Dilithium-3 signature verification
+	Dilithium5VerifyGas uint64 = 18000 // Gas price for a Dilithium-5 signature verification
+	Falcon512VerifyGas  uint64 = 2500  // Gas price for a Falcon-512 signature verification
+
+	KeyRotationGas     uint64 = 50000 // Gas price for rebinding an address to a new public key
+	KeyRotationWordGas uint64 = 5000  // Gas price per 32-byte word of the stored public key
+
 	// The Refund Quotient is the cap on how much of the used gas can be refunded. Before EIP-3529,
 	// up to half the consumed gas could be refunded. Redefined as 1/5th in EIP-3529
 	RefundQuotient        uint64 = 2
@@ -175,6 +184,21 @@ const (
 	MaxBlobGasPerBlock          = 6 * BlobTxBlobGasPerBlob // Maximum consumable blob gas for data blobs per block
 )
 
+// The MKS (Multi-Key-Signature) public keys of a validator, as carried after its
+// address in the genesis and checkpoint extra-data and in authorization votes,
+// are laid out as
+//
+//	[MKSDilithiumOffset:MKSSphincsOffset]: Dilithium5 public key
+//	[MKSSphincsOffset:MKSSize]:            SPHINCS+-256 public key
+//
+// The secp256k1 key of the validator is not included, it is recovered from the
+// seal and identified by the validator address.
+const (
+	MKSDilithiumOffset = 0
+	MKSSphincsOffset   = MKSDilithiumOffset + 2592 // Dilithium5 public key size
+	MKSSize            = MKSSphincsOffset + 64     // SPHINCS+-256 public key size (PK.seed || PK.root)
+)
+
 // Gas discount table for BLS12-381 G1 and G2 multi exponentiation operations
 var Bls12381MultiExpDiscountTable = [128]uint64{1200, 888, 764, 641, 594, 547, 500, 453, 438, 423, 408, 394, 379, 364, 349, 334, 330, 326, 322, 318, 314, 310, 306, 302, 298, 294, 289, 285, 281, 277, 273, 269, 268, 266, 265, 263, 262, 260, 259, 257, 256, 254, 253, 251, 250, 248, 247, 245, 244, 242, 241, 239, 238, 236, 235, 233, 232, 231, 229, 228, 226, 225, 223, 222, 221, 220, 219, 219, 218, 217, 216, 216, 215, 214, 213, 213, 212, 211, 211, 210, 209, 208, 208, 207, 206, 205, 205, 204, 203, 202, 202, 201, 200, 199, 199, 198, 197, 196, 196, 195, 194, 193, 193, 192, 191, 191, 190, 189, 188, 188, 187, 186, 185, 185, 184, 183, 182, 182, 181, 180, 179, 179, 178, 177, 176, 176, 175, 174}
 
@@ -184,4 +208,8 @@ var (
 	BeaconRootsStorageAddress = common.HexToAddress("0x0000000000000000000000000000000000000001")
 	// SystemAddress is where the system-transaction is sent from as per EIP-4788
 	SystemAddress common.Address = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
+	// KeyRegistryAddress is where key rotations are sent to and the public keys bound to rotated accounts are stored
+	KeyRegistryAddress common.Address = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffd")
+	// LegacyAliasAddress is marked in the state once the balances of ECDSA accounts and their 20 byte forms are aliased
+	LegacyAliasAddress common.Address = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffc")
 )
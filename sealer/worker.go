@@ -950,6 +950,10 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 		log.Error("Failed to create sealing context", "err", err)
 		return nil, err
 	}
+	// Start aliasing legacy balances if this is the fork block
+	if w.chainConfig.LegacyAliasBlock != nil && w.chainConfig.LegacyAliasBlock.Cmp(header.Number) == 0 {
+		env.state.ActivateLegacyAliasing()
+	}
 	if header.ParentBeaconRoot != nil {
 		context := core.NewEVMBlockContext(header, w.chain, nil)
 		vmenv := vm.NewEVM(context, vm.TxContext{}, env.state, w.chainConfig, vm.Config{})
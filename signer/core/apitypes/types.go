@@ -182,6 +182,10 @@ var (
 		accounts.MimetypeClique,
 		0x02,
 	}
+	ApplicationCliqueMKS = SigFormat{
+		accounts.MimetypeCliqueMKS,
+		0x02,
+	}
 	TextPlain = SigFormat{
 		accounts.MimetypeTextPlain,
 		0x45,
@@ -488,6 +492,10 @@ func (typedData *TypedData) EncodePrimitiveValue(encType string, encValue interf
 		retval := make([]byte, 32)
 		switch val := encValue.(type) {
 		case string:
+			if addr, err := common.Bech32ToAddress(val); err == nil { // Address in text form
+				copy(retval[:], addr[:])
+				return retval, nil
+			}
 			if len(val) == 66 && val[:2] == "0x" { // Check for 32-byte hex address with "0x" prefix
 				decoded, err := hex.DecodeString(val[2:])
 				if err != nil {
@@ -679,6 +687,8 @@ func formatPrimitiveValue(encType string, encValue interface{}) (string, error)
 	case "address":
 		if stringValue, ok := encValue.(string); !ok {
 			return "", fmt.Errorf("could not format value %v as address", encValue)
+		} else if addr, err := common.Bech32ToAddress(stringValue); err == nil {
+			return addr.String(), nil
 		} else {
 			return common.HexToAddress(stringValue).String(), nil
 		}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"os"
 
+	"github.com/ixios-io/ixiosSpark/accounts"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/internal/ethapi"
@@ -70,6 +71,16 @@ func (l *AuditLogger) SignData(ctx context.Context, contentType string, addr com
 	return b, e
 }
 
+func (l *AuditLogger) SignHybridData(ctx context.Context, contentType string, addrs []common.MixedcaseAddress, data interface{}) ([]accounts.SchemeSignature, error) {
+	marshalledData, _ := json.Marshal(data) // can ignore error, marshalling what we just unmarshalled
+	l.log.Info("SignHybridData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"addrs", addrs, "data", marshalledData, "content-type", contentType)
+	res, e := l.api.SignHybridData(ctx, contentType, addrs, data)
+	sigs, _ := json.Marshal(res) // can ignore error, marshalling what we just produced
+	l.log.Info("SignHybridData", "type", "response", "data", string(sigs), "error", e)
+	return res, e
+}
+
 func (l *AuditLogger) SignGnosisSafeTx(ctx context.Context, addr common.MixedcaseAddress, gnosisTx GnosisSafeTx, methodSelector *string) (*GnosisSafeTx, error) {
 	sel := "<nil>"
 	if methodSelector != nil {
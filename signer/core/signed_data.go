@@ -14,6 +14,7 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -21,11 +22,13 @@ import (
 	"mime"
 
 	"github.com/ixios-io/ixiosSpark/accounts"
+	"github.com/ixios-io/ixiosSpark/accounts/keystore"
 	"github.com/ixios-io/ixiosSpark/common"
 	"github.com/ixios-io/ixiosSpark/common/hexutil"
 	"github.com/ixios-io/ixiosSpark/consensus/fastClique"
 	"github.com/ixios-io/ixiosSpark/core/types"
 	"github.com/ixios-io/ixiosSpark/crypto"
+	"github.com/ixios-io/ixiosSpark/crypto/dilithium"
 	"github.com/ixios-io/ixiosSpark/rlp"
 	"github.com/ixios-io/ixiosSpark/signer/core/apitypes"
 )
@@ -61,7 +64,7 @@ func (api *SignerAPI) sign(req *SignDataRequest, legacyV bool) (hexutil.Bytes, e
 	if err != nil {
 		return nil, err
 	}
-	if legacyV {
+	if legacyV && len(signature) == crypto.SignatureLength {
 		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
 	}
 	return signature, nil
@@ -84,6 +87,54 @@ func (api *SignerAPI) SignData(ctx context.Context, contentType string, addr com
 	return signature, nil
 }
 
+// SignHybridData signs the hash of the provided data with each of the given
+// accounts, returning the signatures tagged with their scheme. It serves the
+// hybrid seals of fastClique, which are made with a secp256k1 and a Dilithium5
+// key over the same data, but accepts any content-type of SignData.
+//
+// Every signature is approved on its own, so that rules can tell the keys apart.
+func (api *SignerAPI) SignHybridData(ctx context.Context, contentType string, addrs []common.MixedcaseAddress, data interface{}) ([]accounts.SchemeSignature, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("no signing accounts specified")
+	}
+	signatures := make([]accounts.SchemeSignature, 0, len(addrs))
+	for _, addr := range addrs {
+		scheme, err := addressScheme(addr.Address())
+		if err != nil {
+			return nil, err
+		}
+		req, transformV, err := api.determineSignatureFormat(ctx, contentType, addr, data)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := api.sign(req, transformV)
+		if err != nil {
+			api.UI.ShowError(err.Error())
+			return nil, err
+		}
+		signatures = append(signatures, accounts.SchemeSignature{Scheme: scheme, Signature: signature})
+	}
+	return signatures, nil
+}
+
+// addressScheme returns the keystore name of the signature scheme of an account,
+// as encoded in the prefix of its address.
+func addressScheme(addr common.Address) (string, error) {
+	switch prefix := types.GetSignatureType(addr); {
+	case bytes.Equal(prefix, types.SigTypeECDSA2), bytes.Equal(prefix, types.SigTypeECDSA26):
+		return keystore.SchemeECDSA, nil
+	case bytes.Equal(prefix, types.SigTypeDilith2):
+		return keystore.SchemeDilithium2, nil
+	case bytes.Equal(prefix, types.SigTypeDilith3):
+		return keystore.SchemeDilithium3, nil
+	case bytes.Equal(prefix, types.SigTypeDilith5):
+		return keystore.SchemeDilithium5, nil
+	case bytes.Equal(prefix, types.SigTypeFalcon512):
+		return keystore.SchemeFalcon512, nil
+	}
+	return "", fmt.Errorf("unknown signature scheme of account %v", addr)
+}
+
 // determineSignatureFormat determines which signature method should be used based upon the mime type
 // In the cases where it matters ensure that the charset is handled. The charset
 // resides in the 'params' returned as the second returnvalue from mime.ParseMediaType
@@ -131,7 +182,7 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 			},
 		}
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: []byte(msg), Messages: messages, Hash: sighash}
-	case apitypes.ApplicationClique.Mime:
+	case apitypes.ApplicationClique.Mime, apitypes.ApplicationCliqueMKS.Mime:
 		// FastClique is the Ixios PoA standard
 		cliqueData, err := fromHex(data)
 		if err != nil {
@@ -141,19 +192,28 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		if err := rlp.DecodeBytes(cliqueData, header); err != nil {
 			return nil, useIxiosV, err
 		}
-		// Add space in the extradata to put the signature
-		newExtra := make([]byte, len(header.Extra)+7188)
+		// Add space in the extradata to put the seal, both halves of it for hybrid seals
+		mks := mediaType == apitypes.ApplicationCliqueMKS.Mime
+		sealLen := crypto.SignatureLength
+		if mks {
+			sealLen += dilithium.Dilithium5.SignatureSize
+		}
+		newExtra := make([]byte, len(header.Extra)+sealLen)
 		copy(newExtra, header.Extra)
 		header.Extra = newExtra
 
 		// Get back the rlp data, encoded by us
-		sighash, cliqueRlp, err := cliqueHeaderHashAndRlp(header)
+		sighash, cliqueRlp, err := cliqueHeaderHashAndRlp(header, mks)
 		if err != nil {
 			return nil, useIxiosV, err
 		}
+		name := "FastClique header"
+		if mks {
+			name = "FastClique MKS header"
+		}
 		messages := []*apitypes.NameValueType{
 			{
-				Name:  "FastClique header",
+				Name:  name,
 				Typ:   "clique",
 				Value: fmt.Sprintf("fastclique header %d [%#x]", header.Number, header.Hash()),
 			},
@@ -200,13 +260,21 @@ func SignTextValidator(validatorData apitypes.ValidatorData) (hexutil.Bytes, str
 }
 
 // cliqueHeaderHashAndRlp returns the hash which is used as input for the proof-of-authority
-// signing. It is the hash of the entire header apart from the 65 byte signature
-// contained at the end of the extra data.
+// signing. It is the hash of the entire header apart from the seal contained at the end of
+// the extra data: the 65 byte signature, preceded by the Dilithium5 signature for hybrid
+// MKS seals.
 //
-// The method requires the extra data to be at least 65 bytes -- the original implementation
-// in clique.go panics if this is the case, thus it's been reimplemented here to avoid the panic
-// and simply return an error instead
-func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error) {
+// The method requires the extra data to be at least as long as the seal -- the original
+// implementation in clique.go panics if this is the case, thus it's been reimplemented here
+// to avoid the panic and simply return an error instead
+func cliqueHeaderHashAndRlp(header *types.Header, mks bool) (hash, rlp []byte, err error) {
+	if mks {
+		if sealLen := crypto.SignatureLength + dilithium.Dilithium5.SignatureSize; len(header.Extra) < sealLen {
+			err = fmt.Errorf("clique header extradata too short, %d < %d", len(header.Extra), sealLen)
+			return
+		}
+		return fastClique.MKSSealHash(header).Bytes(), fastClique.CliqueMKSRLP(header), nil
+	}
 	if len(header.Extra) < 65 {
 		err = fmt.Errorf("clique header extradata too short, %d < 65", len(header.Extra))
 		return
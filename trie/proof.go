@@ -19,8 +19,12 @@ import (
 	"fmt"
 
 	"github.com/ixios-io/ixiosSpark/common"
+	"github.com/ixios-io/ixiosSpark/core/types"
+	"github.com/ixios-io/ixiosSpark/crypto"
 	"github.com/ixios-io/ixiosSpark/kvdb"
 	"github.com/ixios-io/ixiosSpark/log"
+	"github.com/ixios-io/ixiosSpark/rlp"
+	"github.com/ixios-io/ixiosSpark/trie/trienode"
 )
 
 // Prove constructs a merkle proof for key. The result contains all encoded nodes
@@ -108,6 +112,50 @@ func (t *StateTrie) Prove(key []byte, proofDb kvdb.KeyValueWriter) error {
 	return t.trie.Prove(key, proofDb)
 }
 
+// ProveInclusion constructs the inclusion proof of the item at the given index
+// of a list hashed by types.DeriveSha, such as the transactions or receipts of a
+// block. The block fields of the proof are left for the caller to fill in.
+func ProveInclusion(list types.DerivableList, index int) (*types.InclusionProof, error) {
+	if index < 0 || index >= list.Len() {
+		return nil, fmt.Errorf("index %d out of range [0, %d)", index, list.Len())
+	}
+	tr := NewEmpty(nil)
+	types.DeriveSha(list, tr)
+
+	key := rlp.AppendUint64(nil, uint64(index))
+	value, err := tr.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	proof := trienode.NewProofSet()
+	if err := tr.Prove(key, proof); err != nil {
+		return nil, err
+	}
+	nodes := make([][]byte, 0, proof.KeyCount())
+	for _, node := range proof.List() {
+		nodes = append(nodes, node)
+	}
+	return &types.InclusionProof{Index: uint64(index), Value: value, Proof: nodes}, nil
+}
+
+// VerifyInclusionProof checks that the inclusion proof proves its value to be the
+// item at its index of the list the given root was derived from, such as the
+// TxHash or ReceiptHash of the block the proof is for.
+func VerifyInclusionProof(root common.Hash, proof *types.InclusionProof) error {
+	nodes := trienode.NewProofSet()
+	for _, node := range proof.Proof {
+		nodes.Put(crypto.Keccak256(node), node)
+	}
+	value, err := VerifyProof(root, rlp.AppendUint64(nil, proof.Index), nodes)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, proof.Value) {
+		return fmt.Errorf("proven value mismatch at index %d", proof.Index)
+	}
+	return nil
+}
+
 // VerifyProof checks merkle proofs. The given proof must contain the value for
 // key in a trie with the given root hash. VerifyProof returns an error if the
 // proof contains invalid trie nodes or the wrong value.
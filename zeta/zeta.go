@@ -11,52 +11,74 @@
 // You should have received a copy of the GNU Affero General Public License
 // with IxiosSpark. If not, see <http://www.gnu.org/licenses/>.
 
+// Package zeta computes the minimum gas price of the network, 1 zeta, from the
+// schedules in the chain config.
 package zeta
 
 import (
 	"math/big"
-)
+	"sync"
 
-const (
-	secondsPerYear = 31536000
-	decayRate      = 930000000000000000
+	"github.com/ixios-io/ixiosSpark/params"
 )
 
-func CalculateZetaValue(blockNumber *big.Int) *big.Int {
-	// Constants
-	baseValue, _ := new(big.Int).SetString("1000000000000000", 10)
-	minValue := new(big.Int).SetUint64(1)
-	blocksPerYear := new(big.Int).SetUint64(secondsPerYear) // Assumes one block per second
+var precision = new(big.Int).SetUint64(params.ZetaDecayPrecision)
 
-	// Calculate the 0-based year index
-	yearIndex := new(big.Int).Div(blockNumber, blocksPerYear)
+// schedule caches the per-epoch values of a zeta schedule. The values follow
+// the recurrence rate(0) = 1, rate(k) = rate(k-1) * decay, truncated to 18
+// decimals at each step, and value(k) = min + (base - min) * rate(k). Once the
+// rate truncates to zero every later epoch is worth min, so the cache is finite.
+type schedule struct {
+	config *params.ZetaConfig
 
-	// If we're in the first year, return the base value.
-	if yearIndex.Sign() == 0 {
-		return new(big.Int).Set(baseValue)
-	}
+	lock   sync.Mutex
+	rate   *big.Int   // Decay factor of the last cached epoch
+	values []*big.Int // Value of 1 zeta for each epoch computed so far
+	final  bool       // Whether the schedule reached its floor
+}
 
-	// Calculate the difference between base and minimum values.
-	valueDiff := new(big.Int).Sub(baseValue, minValue)
+var schedules sync.Map // *params.ZetaConfig -> *schedule
 
-	// Fixed-point arithmetic parameters (using 18 decimals of precision).
-	precision := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
-	decayRate := new(big.Int).SetUint64(decayRate)
+// Value returns the value of 1 zeta in wei for the block with the given number
+// and millisecond timestamp.
+func Value(config *params.ChainConfig, number *big.Int, time uint64) *big.Int {
+	z := config.ZetaConfigAt(number)
+	return new(big.Int).Set(scheduleOf(z).value(z.Epoch(number, time)))
+}
 
-	// Calculate (decayRate ^ yearIndex) using fixed-point arithmetic.
-	// Start with 1.0 in fixed-point representation.
-	rate := new(big.Int).Set(precision)
-	one := big.NewInt(1)
-	for i := new(big.Int); i.Cmp(yearIndex) < 0; i.Add(i, one) {
-		rate.Mul(rate, decayRate)
-		rate.Div(rate, precision)
+// scheduleOf returns the cached schedule of a zeta config.
+func scheduleOf(z *params.ZetaConfig) *schedule {
+	if s, ok := schedules.Load(z); ok {
+		return s.(*schedule)
 	}
+	s, _ := schedules.LoadOrStore(z, &schedule{
+		config: z,
+		rate:   new(big.Int).Set(precision),
+		values: []*big.Int{new(big.Int).Set(z.Base)},
+	})
+	return s.(*schedule)
+}
 
-	// Multiply the value difference by the computed rate, then adjust for precision.
-	valueDiff.Mul(valueDiff, rate)
-	valueDiff.Div(valueDiff, precision)
+// value returns the value of 1 zeta during the given epoch, extending the cache
+// as needed. The returned value must not be modified.
+func (s *schedule) value(epoch uint64) *big.Int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-	// Calculate the final result: minValue + (valueDiff)
-	result := new(big.Int).Add(minValue, valueDiff)
-	return result
+	decay := new(big.Int).SetUint64(s.config.DecayRate)
+	diff := new(big.Int).Sub(s.config.Base, s.config.Min)
+	for !s.final && uint64(len(s.values)) <= epoch {
+		s.rate.Mul(s.rate, decay)
+		s.rate.Div(s.rate, precision)
+
+		v := new(big.Int).Mul(diff, s.rate)
+		v.Div(v, precision)
+		s.values = append(s.values, v.Add(v, s.config.Min))
+
+		s.final = s.rate.Sign() == 0
+	}
+	if epoch >= uint64(len(s.values)) {
+		return s.values[len(s.values)-1]
+	}
+	return s.values[epoch]
 }